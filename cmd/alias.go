@@ -29,9 +29,15 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
 	"github.com/bomctl/bomctl/internal/pkg/options"
 )
 
+type aliasDefinition struct {
+	Alias string `json:"alias"`
+	ID    string `json:"id"`
+}
+
 func aliasCmd() *cobra.Command {
 	aliasCmd := &cobra.Command{
 		Use:   "alias",
@@ -62,6 +68,7 @@ func aliasListCmd() *cobra.Command {
 			}
 
 			aliasDefinitions := []string{}
+			data := []aliasDefinition{}
 
 			for _, doc := range documents {
 				alias, err := backend.GetDocumentUniqueAnnotation(doc.GetMetadata().GetId(), db.AliasAnnotation)
@@ -72,10 +79,16 @@ func aliasListCmd() *cobra.Command {
 				if alias != "" {
 					aliasDefinitions = append(aliasDefinitions,
 						fmt.Sprintf("%v → %v", alias, doc.GetMetadata().GetId()))
+					data = append(data, aliasDefinition{Alias: alias, ID: doc.GetMetadata().GetId()})
 				}
 			}
 
 			sort.Strings(aliasDefinitions)
+			sort.Slice(data, func(i, j int) bool { return data[i].Alias < data[j].Alias })
+
+			if writeEnvelope(os.Stdout, optionsFromContext(cmd), envelope.KindAliasList, data) {
+				return
+			}
 
 			fmt.Fprintf(os.Stdout, "\nAlias Definitions\n%v\n", strings.Repeat("─", cliTableWidth))
 			fmt.Fprintf(os.Stdout, "%v\n\n", strings.Join(aliasDefinitions, "\n"))
@@ -0,0 +1,61 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/browse.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/browse"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+func browseCmd() *cobra.Command {
+	opts := &options.BrowseOptions{}
+
+	browseCmd := &cobra.Command{
+		Use:   "browse [flags] SBOM_ID...",
+		Short: "Interactively browse SBOM documents in local cache",
+		Long: fmt.Sprintf("%s%s",
+			"Interactively browse SBOM documents in local cache, drilling into their nodes, properties and links. ",
+			"Press / to search documents by ID, alias, name or tag, and nodes by name or purl",
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
+
+			rejectStructuredOutput(cmd, opts.Options)
+
+			backend := backendFromContext(cmd)
+
+			defer backend.CloseClient()
+
+			if err := browse.Browse(args, opts); err != nil {
+				opts.Logger.Fatal(err)
+			}
+		},
+		ValidArgsFunction: completions,
+	}
+
+	browseCmd.Flags().StringArrayVar(&opts.Tags, "tag", []string{},
+		"Tag(s) used to filter documents (can be specified multiple times)")
+
+	return browseCmd
+}
@@ -0,0 +1,242 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/diff.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/diff"
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+const (
+	diffFormatJSON = "json"
+	diffFormatText = "text"
+)
+
+func diffCmd() *cobra.Command {
+	opts := &options.DiffOptions{}
+	outputFile := outputFileValue("")
+	formatValue := newChoiceValue("Output format", diffFormatText, diffFormatJSON)
+	failOnValue := newChoiceValue("Exit with a non-zero code if any risk finding has at least this severity",
+		diff.SeverityNone.String(), diff.SeverityNames()[1:]...)
+
+	diffCmd := &cobra.Command{
+		Use: "diff [flags] { BASE_SBOM_ID REVISED_SBOM_ID | --revision NUMBER SBOM_ID }",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if cmd.Flags().Changed("revision") {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+
+			return cobra.ExactArgs(2)(cmd, args) //nolint:mnd
+		},
+		Short: "Show component differences between two SBOM documents in local storage",
+		Long: fmt.Sprintf("%s%s%s%s%s%s",
+			"Show component differences between two SBOM documents in local storage. Nodes are matched by purl, ",
+			"then by name and version, and reported as added, removed or changed along with changes to the ",
+			"dependency edges between them. Changes are assessed for supply chain risks such as typosquats, version ",
+			"downgrades, host changes and new origins. Output is either a human-readable summary or a JSON report.\n\n",
+			"With --revision, a single SBOM_ID is given and revisions from its lineage are compared instead (see the ",
+			"history command). A single --revision is compared to the latest revision",
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Options = optionsFromContext(cmd)
+			backend := backendFromContext(cmd)
+
+			defer backend.CloseClient()
+
+			opts.Format = formatValue.String()
+
+			threshold, err := diff.ParseSeverity(failOnValue.String())
+			if err != nil {
+				opts.Logger.Fatal(err)
+			}
+
+			var report *diff.Report
+
+			if len(opts.Revisions) > 0 {
+				report, err = diff.DiffRevisions(args[0], opts)
+			} else {
+				report, err = diff.Diff(args[0], args[1], opts)
+			}
+
+			if err != nil {
+				opts.Logger.Fatal(err)
+			}
+
+			var out io.Writer = os.Stdout
+
+			if outputFile != "" {
+				opts.OutputFile, err = os.Create(outputFile.String())
+				if err != nil {
+					opts.Logger.Fatal("error creating output file", "outputFile", outputFile)
+				}
+
+				defer opts.OutputFile.Close()
+
+				out = opts.OutputFile
+			}
+
+			if err := writeDiffReport(out, report, diffOutputFormat(opts)); err != nil {
+				opts.Logger.Fatal(err)
+			}
+
+			if findings := report.FindingsAtOrAbove(threshold); len(findings) > 0 {
+				// The report has been written, so the failure is only logged and reported by the exit code.
+				opts.Logger.Error("Risk findings at or above threshold", "failOn", threshold, "count", len(findings))
+
+				cmd.SilenceErrors, cmd.SilenceUsage = true, true
+
+				return errRiskFindings
+			}
+
+			return nil
+		},
+		ValidArgsFunction: completions,
+	}
+
+	diffCmd.Flags().VarP(&outputFile, "output-file", "o", "Path to output file")
+	diffCmd.Flags().VarP(formatValue, "format", "f", formatValue.Usage())
+	diffCmd.Flags().Var(failOnValue, "fail-on", failOnValue.Usage())
+	diffCmd.Flags().IntSliceVar(&opts.Revisions, "revision", []int{},
+		"Revision number(s) of SBOM_ID to compare (can be specified up to two times)")
+
+	cobra.CheckErr(diffCmd.Flags().MarkDeprecated("format", "use --output json instead"))
+	cobra.CheckErr(diffCmd.RegisterFlagCompletionFunc("format", formatValue.CompletionFunc()))
+	cobra.CheckErr(diffCmd.RegisterFlagCompletionFunc("fail-on", failOnValue.CompletionFunc()))
+
+	return diffCmd
+}
+
+// diffOutputFormat returns the format to write the diff report in. The deprecated --format json is written
+// as the same envelope as --output json.
+func diffOutputFormat(opts *options.DiffOptions) string {
+	if opts.Format == diffFormatJSON && !envelope.IsStructured(opts.OutputFormat) {
+		return envelope.FormatJSON
+	}
+
+	return opts.OutputFormat
+}
+
+func writeDiffReport(out io.Writer, report *diff.Report, format string) error {
+	if envelope.IsStructured(format) {
+		if err := envelope.New(envelope.KindDiffReport, report).Write(out, format); err != nil {
+			return fmt.Errorf("failed to write diff report: %w", err)
+		}
+
+		return nil
+	}
+
+	renderer := lipgloss.NewRenderer(out)
+	addedStyle := renderer.NewStyle().Foreground(green)
+	removedStyle := renderer.NewStyle().Foreground(magenta)
+	changedStyle := renderer.NewStyle().Foreground(yellow)
+
+	fmt.Fprintf(out, "Base    : %s\n", diffDocumentLabel(report.Base))
+	fmt.Fprintf(out, "Revised : %s\n", diffDocumentLabel(report.Revised))
+
+	for _, node := range report.Added {
+		fmt.Fprintln(out, addedStyle.Render("+ "+diffNodeLabel(node)))
+	}
+
+	for _, node := range report.Removed {
+		fmt.Fprintln(out, removedStyle.Render("- "+diffNodeLabel(node)))
+	}
+
+	for _, change := range report.Changed {
+		fmt.Fprintln(out, changedStyle.Render("~ "+diffNodeLabel(change.Base)))
+
+		for _, field := range change.Fields {
+			fmt.Fprintf(out, "    %s: %q -> %q\n", field.Field, field.Base, field.Revised)
+		}
+	}
+
+	writeDiffEdges(out, report, addedStyle, removedStyle, changedStyle)
+
+	findingStyle := renderer.NewStyle().Foreground(red)
+
+	for _, finding := range report.Findings {
+		fmt.Fprintln(out, findingStyle.Render(fmt.Sprintf("! [%s] %s: %s (%s)",
+			finding.Severity, diffNodeLabel(finding.Node), finding.Message, finding.Rule)))
+	}
+
+	edges, risks := report.Summary.Edges, report.Summary.Risks
+
+	fmt.Fprintf(out, "\nNodes : %d added, %d removed, %d changed, %d unchanged\n",
+		report.Summary.Added, report.Summary.Removed, report.Summary.Changed, report.Summary.Unchanged)
+	fmt.Fprintf(out, "Edges : %d added, %d removed, %d type changed, %d moved, %d promoted\n",
+		edges.Added, edges.Removed, edges.TypeChanged, edges.Moved, edges.Promoted)
+	fmt.Fprintf(out, "Risks : %d high, %d medium, %d low\n", risks.High, risks.Medium, risks.Low)
+
+	return nil
+}
+
+func writeDiffEdges(out io.Writer, report *diff.Report, addedStyle, removedStyle, changedStyle lipgloss.Style) {
+	for _, edge := range report.Edges.Added {
+		fmt.Fprintln(out, addedStyle.Render(fmt.Sprintf("+ %s -> %s (%s)",
+			diffNodeLabel(edge.From), diffNodeLabel(edge.To), edge.Type)))
+	}
+
+	for _, edge := range report.Edges.Removed {
+		fmt.Fprintln(out, removedStyle.Render(fmt.Sprintf("- %s -> %s (%s)",
+			diffNodeLabel(edge.From), diffNodeLabel(edge.To), edge.Type)))
+	}
+
+	for _, change := range report.Edges.TypeChanged {
+		fmt.Fprintln(out, changedStyle.Render(fmt.Sprintf("~ %s -> %s",
+			diffNodeLabel(change.From), diffNodeLabel(change.To))))
+		fmt.Fprintf(out, "    type: %q -> %q\n", change.Base, change.Revised)
+	}
+
+	for _, move := range report.Edges.Moved {
+		fmt.Fprintln(out, changedStyle.Render(fmt.Sprintf("~ %s moved from %s to %s",
+			diffNodeLabel(move.Node), diffNodeLabel(move.BaseParent), diffNodeLabel(move.RevisedParent))))
+	}
+
+	for _, node := range report.Edges.Promoted {
+		fmt.Fprintln(out, changedStyle.Render(fmt.Sprintf("~ %s changed from transitive to direct dependency",
+			diffNodeLabel(node))))
+	}
+}
+
+func diffDocumentLabel(ref diff.DocumentRef) string {
+	if ref.Alias != "" {
+		return fmt.Sprintf("%s (%s)", ref.ID, ref.Alias)
+	}
+
+	return ref.ID
+}
+
+func diffNodeLabel(node diff.NodeRef) string {
+	switch {
+	case node.Name == "":
+		return node.ID
+	case node.Version == "":
+		return node.Name
+	default:
+		return node.Name + "@" + node.Version
+	}
+}
@@ -0,0 +1,73 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/enrich.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/enrich"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+func enrichCmd() *cobra.Command {
+	opts := &options.EnrichOptions{}
+
+	enrichCmd := &cobra.Command{
+		Use:   "enrich [flags] SBOM_ID",
+		Args:  cobra.ExactArgs(1),
+		Short: "Enrich the components of an SBOM document using a Transparency Exchange API server",
+		Long: fmt.Sprintf("%s%s%s%s%s%s",
+			"Enrich the components of an SBOM document in local storage using a Transparency Exchange API (TEA) ",
+			"server. Each component is looked up by purl, and components whose version matches a published release ",
+			"are given properties holding the product and release UUIDs and the URLs of the release's artifacts, ",
+			"such as SBOMs and VEX documents. BOM artifacts are fetched into local storage, recorded in the ",
+			"component's properties and linked to from the enriched document.\n\nThe enriched document is stored ",
+			"as a new revision of the original, which remains unchanged",
+		),
+		Example: "  bomctl enrich --tea-url https://tea.example.com/tea/v1 SBOM_ID",
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
+			backend := backendFromContext(cmd)
+			backend.Logger.SetPrefix("enrich")
+
+			defer backend.CloseClient()
+
+			document, err := enrich.Enrich(args[0], opts)
+			if err != nil {
+				opts.Logger.Fatal(err)
+			}
+
+			opts.Logger.Info("Stored enriched revision", "id", document.GetMetadata().GetId())
+			writeStoredDocuments(opts.Options, backend, document)
+		},
+		ValidArgsFunction: completions,
+	}
+
+	enrichCmd.Flags().StringVar(&opts.TEAURL, "tea-url", "",
+		"Base URL of the TEA server, including the API version path")
+	enrichCmd.Flags().BoolVar(&opts.UseNetRC, "netrc", false,
+		"Use .netrc file for authentication when fetching BOM artifacts")
+
+	cobra.CheckErr(enrichCmd.MarkFlagRequired("tea-url"))
+
+	return enrichCmd
+}
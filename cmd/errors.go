@@ -27,4 +27,5 @@ var (
 	errEncodingNotSupported = errors.New("encoding not supported for selected format")
 	errFileNotFound         = errors.New("not a file or does not exist")
 	errFormatNotSupported   = errors.New("format not supported")
+	errRiskFindings         = errors.New("risk findings at or above threshold")
 )
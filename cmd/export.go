@@ -22,6 +22,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"slices"
 
@@ -32,19 +33,38 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
 	"github.com/bomctl/bomctl/internal/pkg/export"
 	"github.com/bomctl/bomctl/internal/pkg/options"
 )
 
+// exportedDocument is a document written to a file by the export command.
+type exportedDocument struct {
+	ID    string `json:"id"`
+	Alias string `json:"alias,omitempty"`
+	File  string `json:"file"`
+}
+
 func exportCmd() *cobra.Command { //nolint:funlen
 	opts := &options.ExportOptions{}
 	outputFile := outputFileValue("")
+	keyPath := ""
 
 	exportCmd := &cobra.Command{
 		Use:   "export [flags] SBOM_ID...",
 		Args:  cobra.MinimumNArgs(1),
 		Short: "Export stored SBOM(s) to filesystem",
-		Long:  "Export stored SBOM(s) to filesystem",
+		Long: fmt.Sprintf("%s%s%s%s%s%s%s%s%s",
+			"Export stored SBOM(s) to filesystem.\n\nWith --guac, the SBOM(s) and every document they link to are ",
+			"written to a directory that can be ingested by the GUAC file collector. Links between the documents are ",
+			"written into them as BOM external references to the files of the documents they link to. A manifest ",
+			"mapping each file to its document ID and listing the links is written next to the directory, to a file ",
+			"named after it with a .bomctl-manifest.json suffix, for tools that restore or check the links after ",
+			"ingestion.\n\n",
+			"With --attest, each SBOM is written as the predicate of an in-toto statement in a DSSE envelope, with ",
+			"the hashes of its root node(s) as the subject. The envelope is signed if a private key is given with ",
+			"--key",
+		),
 		Run: func(cmd *cobra.Command, args []string) {
 			opts.Options = optionsFromContext(cmd)
 			backend := backendFromContext(cmd)
@@ -60,6 +80,31 @@ func exportCmd() *cobra.Command { //nolint:funlen
 			}
 
 			opts.Format = format
+			opts.Signer = attestationSigner(opts.Options, opts.Attest, keyPath)
+
+			if opts.GUACDir != "" {
+				if outputFile != "" {
+					opts.Logger.Fatal("The --output-file option cannot be used with the --guac option.")
+				}
+
+				if opts.Attest {
+					opts.Logger.Fatal("The --attest option cannot be used with the --guac option.")
+				}
+
+				manifest, err := export.ExportGUAC(args, opts)
+				if err != nil {
+					opts.Logger.Fatal(err)
+				}
+
+				writeEnvelope(os.Stdout, opts.Options, envelope.KindExportedDocumentList,
+					guacExportedDocuments(manifest, opts.GUACDir))
+
+				return
+			}
+
+			if outputFile == "" && envelope.IsStructured(opts.OutputFormat) {
+				opts.Logger.Fatal("The --output option requires the --output-file or --guac option.")
+			}
 
 			if outputFile != "" {
 				if len(args) > 1 {
@@ -86,11 +131,23 @@ func exportCmd() *cobra.Command { //nolint:funlen
 				opts.Logger.Errorf("documentID(s) not found: %s", args)
 			}
 
+			exported := []exportedDocument{}
+
 			for _, document := range documents {
-				if err := export.Export(document.GetMetadata().GetId(), opts); err != nil {
+				id := document.GetMetadata().GetId()
+
+				if err := export.Export(id, opts); err != nil {
 					opts.Logger.Fatal(err)
 				}
+
+				exported = append(exported, exportedDocument{
+					ID:    id,
+					Alias: backend.GetDocumentAlias(id),
+					File:  outputFile.String(),
+				})
 			}
+
+			writeEnvelope(os.Stdout, opts.Options, envelope.KindExportedDocumentList, exported)
 		},
 		ValidArgsFunction: completions,
 	}
@@ -100,6 +157,10 @@ func exportCmd() *cobra.Command { //nolint:funlen
 	exportCmd.Flags().VarP(&outputFile, "output-file", "o", "Path to output file")
 	exportCmd.Flags().VarP(formatValue, "format", "f", formatValue.Usage())
 	exportCmd.Flags().VarP(encodingValue, "encoding", "e", encodingValue.Usage())
+	exportCmd.Flags().StringVar(&opts.GUACDir, "guac", "",
+		"Directory to write SBOM(s) and linked documents to for GUAC ingestion")
+	exportCmd.Flags().BoolVar(&opts.Attest, "attest", false, "Write SBOM(s) as DSSE wrapped in-toto attestations")
+	exportCmd.Flags().StringVar(&keyPath, "key", "", "Path to a PEM encoded private key to sign attestations with")
 
 	cobra.CheckErr(exportCmd.RegisterFlagCompletionFunc("format", formatValue.CompletionFunc()))
 	cobra.CheckErr(exportCmd.RegisterFlagCompletionFunc("encoding", encodingValue.CompletionFunc()))
@@ -107,6 +168,22 @@ func exportCmd() *cobra.Command { //nolint:funlen
 	return exportCmd
 }
 
+// guacExportedDocuments returns the documents listed in a GUAC manifest, with paths to the files they were
+// written to in guacDir.
+func guacExportedDocuments(manifest *export.GUACManifest, guacDir string) []exportedDocument {
+	exported := []exportedDocument{}
+
+	for _, document := range manifest.Documents {
+		exported = append(exported, exportedDocument{
+			ID:    document.ID,
+			Alias: document.Alias,
+			File:  filepath.Join(guacDir, document.File),
+		})
+	}
+
+	return exported
+}
+
 func encodingChoice() *choiceValue {
 	return newChoiceValue("Output encoding ('xml' supported for CycloneDX formats only)", formats.JSON, formats.XML)
 }
@@ -20,9 +20,12 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 
+	"github.com/protobom/protobom/pkg/sbom"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/bomctl/bomctl/internal/pkg/fetch"
 	"github.com/bomctl/bomctl/internal/pkg/options"
@@ -36,13 +39,22 @@ func fetchCmd() *cobra.Command {
 		Use:   "fetch [flags] SBOM_URL...",
 		Args:  cobra.MinimumNArgs(1),
 		Short: "Fetch SBOM file(s) from HTTP(S), OCI, Git, or GitHub URLs",
-		Long:  "Fetch SBOM file(s) from HTTP(S), OCI, Git, or GitHub URLs",
+		Long: fmt.Sprintf("%s%s%s%s",
+			"Fetch SBOM file(s) from HTTP(S), OCI, Git, or GitHub URLs\n\n",
+			"When trusted keys are given with --trusted-key or the trusted_keys config value, signatures published ",
+			"with the SBOM are discovered and verified against them, and the result is stored with the document. ",
+			"With --require-signature, documents without a signature verified by a trusted key are rejected",
+		),
 		Run: func(cmd *cobra.Command, args []string) {
 			opts.Options = optionsFromContext(cmd)
 			backend := backendFromContext(cmd)
 
 			defer backend.CloseClient()
 
+			opts.TrustedKeys = viper.GetStringSlice("trusted_keys")
+			opts.RequireSignature = viper.GetBool("require_signature")
+			opts.AutoLink = autoLinkStrategies(opts.Options)
+
 			if outputFileName != "" {
 				if len(args) > 1 {
 					opts.Logger.Fatal("The --output-file option cannot be used when more than one URL is provided.")
@@ -58,11 +70,18 @@ func fetchCmd() *cobra.Command {
 				defer opts.OutputFile.Close()
 			}
 
+			documents := []*sbom.Document{}
+
 			for _, url := range args {
-				if _, err := fetch.Fetch(url, opts); err != nil {
+				document, err := fetch.Fetch(url, opts)
+				if err != nil {
 					opts.Logger.Fatal(err)
 				}
+
+				documents = append(documents, document)
 			}
+
+			writeStoredDocuments(opts.Options, backend, documents...)
 		},
 	}
 
@@ -71,6 +90,12 @@ func fetchCmd() *cobra.Command {
 	fetchCmd.Flags().StringVar(&opts.Alias, "alias", "", "Readable identifier to apply to document")
 	fetchCmd.Flags().StringArrayVar(&opts.Tags, "tag", []string{},
 		"Tag(s) to apply to document (can be specified multiple times)")
+	fetchCmd.Flags().StringArray("trusted-key", []string{},
+		"Path to a PEM encoded public key trusted to sign fetched SBOMs (can be specified multiple times)")
+	fetchCmd.Flags().Bool("require-signature", false, "Reject SBOMs without a signature verified by a trusted key")
+
+	cobra.CheckErr(viper.BindPFlag("trusted_keys", fetchCmd.Flags().Lookup("trusted-key")))
+	cobra.CheckErr(viper.BindPFlag("require_signature", fetchCmd.Flags().Lookup("require-signature")))
 
 	return fetchCmd
 }
@@ -0,0 +1,121 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/history.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	lgtable "github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
+)
+
+type revisionInfo struct {
+	ID       string `json:"id"`
+	Alias    string `json:"alias"`
+	Date     string `json:"date"`
+	Revision int    `json:"revision"`
+	Latest   bool   `json:"latest"`
+}
+
+func historyCmd() *cobra.Command {
+	historyCmd := &cobra.Command{
+		Use:   "history [flags] SBOM_ID",
+		Args:  cobra.ExactArgs(1),
+		Short: "List the revisions of an SBOM document in local storage",
+		Long: fmt.Sprintf("%s%s",
+			"List the revisions of an SBOM document in local storage, from the original document to the latest ",
+			"revision. Revision numbers can be passed to the diff command's --revision flag",
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			backend := backendFromContext(cmd)
+			backend.Logger.SetPrefix("history")
+
+			defer backend.CloseClient()
+
+			revisions, err := backend.GetDocumentRevisions(args[0])
+			if err != nil {
+				backend.Logger.Fatalf("failed to get document revisions: %v", err)
+			}
+
+			rows := [][]string{}
+			data := []revisionInfo{}
+
+			for idx, revision := range revisions {
+				id := revision.GetMetadata().GetId()
+
+				latest, err := backend.GetDocumentAnnotations(id, db.LatestRevisionAnnotation)
+				if err != nil {
+					backend.Logger.Fatalf("failed to get document revisions: %v", err)
+				}
+
+				// Fall back to when the revision was stored if its metadata has no date.
+				date, err := backend.GetDocumentUniqueAnnotation(id, db.StoredAtAnnotation)
+				if err != nil {
+					backend.Logger.Fatalf("failed to get document revisions: %v", err)
+				}
+
+				if timestamp := revision.GetMetadata().GetDate().AsTime(); timestamp.Unix() > 0 {
+					date = timestamp.UTC().Format(time.RFC3339)
+				}
+
+				row := []string{strconv.Itoa(idx + 1), id, backend.GetDocumentAlias(id), date, ""}
+				if len(latest) > 0 {
+					row[len(row)-1] = "*"
+				}
+
+				rows = append(rows, row)
+				data = append(data, revisionInfo{
+					ID:       id,
+					Alias:    row[2],
+					Date:     date,
+					Revision: idx + 1,
+					Latest:   len(latest) > 0,
+				})
+			}
+
+			if writeEnvelope(os.Stdout, optionsFromContext(cmd), envelope.KindRevisionList, data) {
+				return
+			}
+
+			fmt.Fprintln(os.Stdout, lgtable.New().
+				Headers("Revision", "ID", "Alias", "Date", "Latest").
+				Rows(rows...).
+				BorderTop(false).
+				BorderBottom(false).
+				BorderLeft(false).
+				BorderRight(false).
+				BorderHeader(true).
+				StyleFunc(func(_, _ int) lipgloss.Style {
+					return lipgloss.NewStyle().Padding(0, 1)
+				}).
+				Render())
+		},
+		ValidArgsFunction: completions,
+	}
+
+	return historyCmd
+}
@@ -43,6 +43,8 @@ func importCmd() *cobra.Command {
 
 			defer backend.CloseClient()
 
+			opts.AutoLink = autoLinkStrategies(opts.Options)
+
 			if slices.Contains(args, "-") && len(args) > 1 {
 				opts.Logger.Fatal("Piped input and file path args cannot be specified simultaneously.")
 			}
@@ -62,9 +64,12 @@ func importCmd() *cobra.Command {
 				}
 			}
 
-			if err := imprt.Import(opts); err != nil {
+			documents, err := imprt.Import(opts)
+			if err != nil {
 				opts.Logger.Fatal(err)
 			}
+
+			writeStoredDocuments(opts.Options, backend, documents...)
 		},
 	}
 
@@ -22,13 +22,18 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 
 	"github.com/charmbracelet/lipgloss"
+	lgtable "github.com/charmbracelet/lipgloss/table"
 	"github.com/charmbracelet/lipgloss/tree"
 	"github.com/muesli/termenv"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
 	"github.com/bomctl/bomctl/internal/pkg/link"
 	"github.com/bomctl/bomctl/internal/pkg/options"
 	"github.com/bomctl/bomctl/internal/pkg/sliceutil"
@@ -39,9 +44,16 @@ const (
 	cyan    = lipgloss.ANSIColor(termenv.ANSICyan)
 	green   = lipgloss.ANSIColor(termenv.ANSIGreen)
 	magenta = lipgloss.ANSIColor(termenv.ANSIMagenta)
+	red     = lipgloss.ANSIColor(termenv.ANSIRed)
 	yellow  = lipgloss.ANSIColor(termenv.ANSIYellow)
 )
 
+type linkList struct {
+	From     options.LinkTarget   `json:"from"`
+	Outgoing []options.LinkTarget `json:"outgoing"`
+	Incoming []options.LinkTarget `json:"incoming"`
+}
+
 func linkCmd() *cobra.Command {
 	linkCmd := &cobra.Command{
 		Use:   "link",
@@ -54,7 +66,7 @@ func linkCmd() *cobra.Command {
 
 	cobra.CheckErr(linkCmd.RegisterFlagCompletionFunc("type", typeValue.CompletionFunc()))
 
-	linkCmd.AddCommand(linkAddCmd(), linkClearCmd(), linkListCmd(), linkRemoveCmd())
+	linkCmd.AddCommand(linkAddCmd(), linkCheckCmd(), linkClearCmd(), linkInferCmd(), linkListCmd(), linkRemoveCmd())
 
 	return linkCmd
 }
@@ -85,6 +97,43 @@ func linkAddCmd() *cobra.Command {
 	return addCmd
 }
 
+func linkCheckCmd() *cobra.Command {
+	opts := &options.LinkOptions{}
+
+	checkCmd := &cobra.Command{
+		Use:   "check [flags]",
+		Short: "Check the integrity of all links in the cache",
+		Long: fmt.Sprintf("%s%s%s%s",
+			"Check every document and node link in the cache, reporting links to documents that are no longer in ",
+			"the cache, links to superseded document revisions, duplicate links and cycles of links between ",
+			"documents. With --fix, dangling links are removed, links to superseded revisions are repointed to the ",
+			"latest revision and duplicate links are collapsed into one",
+		),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, _ []string) {
+			opts.Options = optionsFromContext(cmd)
+			backend := backendFromContext(cmd)
+
+			defer backend.CloseClient()
+
+			problems, err := link.CheckLinks(backend, opts)
+			if err != nil {
+				opts.Logger.Fatal(err)
+			}
+
+			if writeEnvelope(os.Stdout, opts.Options, envelope.KindLinkProblemList, problems) || len(problems) == 0 {
+				return
+			}
+
+			writeLinkProblems(problems)
+		},
+	}
+
+	checkCmd.Flags().BoolVar(&opts.Fix, "fix", false, "Fix dangling, stale and duplicate links")
+
+	return checkCmd
+}
+
 func linkClearCmd() *cobra.Command {
 	opts := &options.LinkOptions{}
 
@@ -111,6 +160,41 @@ func linkClearCmd() *cobra.Command {
 	return clearCmd
 }
 
+func linkInferCmd() *cobra.Command {
+	opts := &options.LinkOptions{}
+
+	inferCmd := &cobra.Command{
+		Use:   "infer [flags] [SBOM_ID...]",
+		Short: "Link nodes to the documents describing them",
+		Long: fmt.Sprintf("%s%s%s%s",
+			"Link nodes of the specified documents, or of all documents in the cache if none are specified, ",
+			"to the documents describing them. A node is linked to a document if its purl or its name and version ",
+			"match the document's root component, or if it references the document's CycloneDX serial number in a ",
+			"BOM external reference. The inferred links are reported",
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
+			backend := backendFromContext(cmd)
+
+			defer backend.CloseClient()
+
+			inferred, err := link.InferLinks(backend, args, opts)
+			if err != nil {
+				opts.Logger.Fatal(err)
+			}
+
+			if writeEnvelope(os.Stdout, opts.Options, envelope.KindInferredLinkList, inferred) || len(inferred) == 0 {
+				return
+			}
+
+			writeInferredLinks(inferred)
+		},
+		ValidArgsFunction: completions,
+	}
+
+	return inferCmd
+}
+
 func linkListCmd() *cobra.Command {
 	opts := &options.LinkOptions{}
 
@@ -133,6 +217,15 @@ func linkListCmd() *cobra.Command {
 				opts.Logger.Fatal(err)
 			}
 
+			data := linkList{
+				From:     opts.Links[0].From,
+				Outgoing: append([]options.LinkTarget{}, opts.Links[0].To...),
+				Incoming: append([]options.LinkTarget{}, incoming...),
+			}
+			if writeEnvelope(os.Stdout, opts.Options, envelope.KindLinkList, data) {
+				return
+			}
+
 			fmt.Fprintln(os.Stdout, newLinksTree(opts.Links[0], incoming))
 		},
 		ValidArgsFunction: completions,
@@ -168,6 +261,44 @@ func linkRemoveCmd() *cobra.Command {
 	return removeCmd
 }
 
+func writeInferredLinks(inferred []link.InferredLink) {
+	rows := sliceutil.Extract(inferred, func(il link.InferredLink) []string {
+		return []string{il.From.String(), il.To.String(), il.Match, il.Value}
+	})
+
+	fmt.Fprintln(os.Stdout, lgtable.New().
+		Headers("Node", "Document", "Match", "Value").
+		Rows(rows...).
+		BorderTop(false).
+		BorderBottom(false).
+		BorderLeft(false).
+		BorderRight(false).
+		BorderHeader(true).
+		StyleFunc(func(_, _ int) lipgloss.Style {
+			return lipgloss.NewStyle().Padding(0, 1)
+		}).
+		Render())
+}
+
+func writeLinkProblems(problems []link.LinkProblem) {
+	rows := sliceutil.Extract(problems, func(lp link.LinkProblem) []string {
+		return []string{lp.Problem, lp.From.String(), lp.To.String(), lp.Detail, strconv.FormatBool(lp.Fixed)}
+	})
+
+	fmt.Fprintln(os.Stdout, lgtable.New().
+		Headers("Problem", "From", "To", "Detail", "Fixed").
+		Rows(rows...).
+		BorderTop(false).
+		BorderBottom(false).
+		BorderLeft(false).
+		BorderRight(false).
+		BorderHeader(true).
+		StyleFunc(func(_, _ int) lipgloss.Style {
+			return lipgloss.NewStyle().Padding(0, 1)
+		}).
+		Render())
+}
+
 func newLinksTree(links options.Link, incoming []options.LinkTarget) *tree.Tree {
 	style := lipgloss.NewStyle()
 
@@ -250,12 +381,29 @@ func populateLinkTargets(linkType string, from, to []string, backend *db.Backend
 	return links
 }
 
+// autoLinkStrategies returns the link inference strategies enabled by the auto_link config value, with which
+// documents are linked as they are added to the cache.
+func autoLinkStrategies(opts *options.Options) []string {
+	strategies := viper.GetStringSlice("auto_link")
+
+	for _, strategy := range strategies {
+		if !slices.Contains(link.Strategies(), strategy) {
+			opts.Logger.Fatal("Unsupported auto_link strategy", "strategy", strategy, "supported", link.Strategies())
+		}
+	}
+
+	return strategies
+}
+
 func resolveDocumentID(id string, backend *db.Backend) string {
 	document, err := backend.GetDocumentByIDOrAlias(id)
 	cobra.CheckErr(err)
 
+	// Keep the given ID of a document missing from the cache, so it can be linked to before it is fetched.
 	if document == nil {
 		backend.Logger.Warn("Document not found", "id", id)
+
+		return id
 	}
 
 	return document.GetMetadata().GetId()
@@ -22,41 +22,79 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
+	"github.com/bomctl/bomctl/internal/pkg/list"
+	"github.com/bomctl/bomctl/internal/pkg/options"
 	"github.com/bomctl/bomctl/internal/pkg/outpututil"
 )
 
 func listCmd() *cobra.Command {
-	tags := []string{}
+	opts := &options.ListOptions{}
 
 	listCmd := &cobra.Command{
 		Use:     "list [flags] SBOM_ID...",
 		Aliases: []string{"ls"},
 		Short:   "List SBOM documents in local cache",
-		Long:    "List SBOM documents in local cache",
+		Long: fmt.Sprintf("%s%s%s%s%s%s",
+			"List SBOM documents in local cache.\n\n",
+			"Documents can be filtered with a Common Expression Language (CEL) expression over the fields id, alias, ",
+			"name, version, nodes, source_url, source_format, tags, date, revision_status and annotations. ",
+			"The output columns can be selected with --columns, or each document can be rendered with a Go template ",
+			"over the fields ID, Alias, Name, Version, Nodes, SourceURL, SourceFormat, Tags, Date, RevisionStatus and ",
+			"Annotations. The template functions join and date format lists and dates",
+		),
+		Example: `  bomctl list --filter 'source_format.contains("cyclonedx") && nodes > 100'
+  bomctl list --columns id,name,tags,revision_status
+  bomctl list --template '{{.ID}} {{join .Tags ","}}'`,
 		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
 			backend := backendFromContext(cmd)
-			backend.Logger.SetPrefix("list")
 
 			defer backend.CloseClient()
 
-			documents, err := backend.GetDocumentsByIDOrAlias(args...)
+			records, err := list.List(args, opts)
 			if err != nil {
-				backend.Logger.Fatalf("failed to get documents: %v", err)
+				opts.Logger.Fatal(err)
 			}
 
-			if len(tags) > 0 {
-				documents, err = backend.FilterDocumentsByTag(documents, tags...)
+			if writeEnvelope(os.Stdout, opts.Options, envelope.KindDocumentList, records) {
+				return
+			}
+
+			if opts.Template != "" {
+				if err := list.WriteTemplate(os.Stdout, records, opts.Template); err != nil {
+					opts.Logger.Fatal(err)
+				}
+
+				return
+			}
+
+			columns := []string{"id", "alias", "version", "nodes"}
+			tableOpts := []outpututil.TableOption{}
+
+			if len(opts.Columns) > 0 {
+				headers, err := list.Headers(opts.Columns...)
 				if err != nil {
-					backend.Logger.Fatalf("failed to get documents: %v", err)
+					opts.Logger.Fatal(err)
 				}
+
+				columns = opts.Columns
+				tableOpts = append(tableOpts, outpututil.WithColumns(headers...))
 			}
 
-			listOutput := outpututil.NewTable()
-			for _, document := range documents {
-				listOutput.AddRow(document, backend)
+			listOutput := outpututil.NewTable(tableOpts...)
+
+			for _, record := range records {
+				values, err := record.Values(columns...)
+				if err != nil {
+					opts.Logger.Fatal(err)
+				}
+
+				listOutput.AddRow(values...)
 			}
 
 			fmt.Fprintln(os.Stdout, listOutput.String())
@@ -64,8 +102,19 @@ func listCmd() *cobra.Command {
 		ValidArgsFunction: completions,
 	}
 
-	listCmd.Flags().StringArrayVar(&tags, "tag", []string{},
+	listCmd.Flags().StringArrayVar(&opts.Tags, "tag", []string{},
 		"Tag(s) used to filter documents (can be specified multiple times)")
+	listCmd.Flags().StringVar(&opts.Filter, "filter", "", "CEL expression used to filter documents")
+	listCmd.Flags().StringSliceVar(&opts.Columns, "columns", []string{},
+		fmt.Sprintf("Comma-separated columns to output %v", list.ColumnNames()))
+	listCmd.Flags().StringVar(&opts.Template, "template", "", "Go template used to output each document")
+
+	listCmd.MarkFlagsMutuallyExclusive("columns", "template")
+
+	cobra.CheckErr(listCmd.RegisterFlagCompletionFunc("columns",
+		func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return slices.Clone(list.ColumnNames()), cobra.ShellCompDirectiveNoFileComp
+		}))
 
 	return listCmd
 }
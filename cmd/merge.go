@@ -52,9 +52,17 @@ func mergeCmd() *cobra.Command {
 
 			opts.DocumentName = documentName
 
-			if _, err := merge.Merge(args, opts); err != nil {
+			documentID, err := merge.Merge(args, opts)
+			if err != nil {
 				backend.Logger.Fatal(err)
 			}
+
+			document, err := backend.GetDocumentByID(documentID)
+			if err != nil {
+				backend.Logger.Fatal(err)
+			}
+
+			writeStoredDocuments(opts.Options, backend, document)
 		},
 		ValidArgsFunction: completions,
 	}
@@ -20,20 +20,40 @@
 package cmd
 
 import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
 	"github.com/spf13/cobra"
 
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
 	"github.com/bomctl/bomctl/internal/pkg/options"
 	"github.com/bomctl/bomctl/internal/pkg/push"
 )
 
+// pushedDocument is a document pushed by the push command, along with the URL it was pushed to.
+type pushedDocument struct {
+	ID    string `json:"id"`
+	Alias string `json:"alias,omitempty"`
+	URL   string `json:"url"`
+}
+
 func pushCmd() *cobra.Command {
 	opts := &options.PushOptions{}
+	keyPath := ""
 
 	pushCmd := &cobra.Command{
 		Use:   "push [flags] SBOM_ID DEST_PATH",
 		Args:  cobra.MinimumNArgs(2),
 		Short: "Push stored SBOM file to remote URL or filesystem",
-		Long:  "Push stored SBOM file to remote URL or filesystem",
+		Long: fmt.Sprintf("%s%s%s%s",
+			"Push stored SBOM file to remote URL or filesystem.\n\n",
+			"With --attest, SBOMs are pushed as the predicate of an in-toto statement in a DSSE envelope, with the ",
+			"hashes of their root node(s) as the subject. The envelope is signed if a private key is given with ",
+			"--key",
+		),
 		Run: func(cmd *cobra.Command, args []string) {
 			opts.Options = optionsFromContext(cmd)
 			backend := backendFromContext(cmd)
@@ -49,6 +69,7 @@ func pushCmd() *cobra.Command {
 			}
 
 			opts.Format = format
+			opts.Signer = attestationSigner(opts.Options, opts.Attest, keyPath)
 
 			// Get the document to obtain its ID, in case the provided ID was an alias.
 			document, err := backend.GetDocumentByIDOrAlias(args[0])
@@ -59,6 +80,9 @@ func pushCmd() *cobra.Command {
 			if err := push.Push(document.GetMetadata().GetId(), args[1], opts); err != nil {
 				opts.Logger.Fatal(err)
 			}
+
+			writeEnvelope(os.Stdout, opts.Options, envelope.KindPushedDocumentList,
+				pushedDocuments(backend, document.GetMetadata().GetId(), args[1], opts.ReferenceURLs))
 		},
 		ValidArgsFunction: completions,
 	}
@@ -68,10 +92,28 @@ func pushCmd() *cobra.Command {
 	pushCmd.Flags().VarP(formatValue, "format", "f", formatValue.Usage())
 	pushCmd.Flags().VarP(encodingValue, "encoding", "e", encodingValue.Usage())
 	pushCmd.Flags().BoolVar(&opts.UseNetRC, "netrc", false, "Use .netrc file for authentication to remote hosts")
-	pushCmd.Flags().BoolVar(&opts.UseTree, "tree", false, "Recursively push all SBOMs in external reference tree")
+	pushCmd.Flags().BoolVar(&opts.UseTree, "tree", false, "Recursively push all linked and externally referenced SBOMs")
+	pushCmd.Flags().BoolVar(&opts.Attest, "attest", false, "Push SBOM(s) as DSSE wrapped in-toto attestations")
+	pushCmd.Flags().StringVar(&keyPath, "key", "", "Path to a PEM encoded private key to sign attestations with")
 
 	cobra.CheckErr(pushCmd.RegisterFlagCompletionFunc("format", formatValue.CompletionFunc()))
 	cobra.CheckErr(pushCmd.RegisterFlagCompletionFunc("encoding", encodingValue.CompletionFunc()))
 
 	return pushCmd
 }
+
+// pushedDocuments returns the document with the specified ID pushed to pushURL, followed by the documents in
+// its tree pushed to referenceURLs.
+func pushedDocuments(backend *db.Backend, id, pushURL string, referenceURLs map[string]string) []pushedDocument {
+	pushed := []pushedDocument{{ID: id, Alias: backend.GetDocumentAlias(id), URL: pushURL}}
+
+	for _, treeID := range slices.Sorted(maps.Keys(referenceURLs)) {
+		pushed = append(pushed, pushedDocument{
+			ID:    treeID,
+			Alias: backend.GetDocumentAlias(treeID),
+			URL:   referenceURLs[treeID],
+		})
+	}
+
+	return pushed
+}
@@ -0,0 +1,151 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/query.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/charmbracelet/lipgloss"
+	lgtable "github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/query"
+)
+
+const (
+	queryFormatJSON  = "json"
+	queryFormatTable = "table"
+)
+
+func queryCmd() *cobra.Command {
+	opts := &options.QueryOptions{}
+	outputFile := outputFileValue("")
+	formatValue := newChoiceValue("Output format", queryFormatTable, queryFormatJSON)
+
+	queryCmd := &cobra.Command{
+		Use:   "query [flags] EXPRESSION [SBOM_ID...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Query nodes of SBOM documents in local cache using a CEL expression",
+		Long: fmt.Sprintf("%s%s%s%s%s",
+			"Query nodes of SBOM documents in local cache using a Common Expression Language (CEL) expression. ",
+			"The expression is evaluated against every node of the specified documents, or of all documents if none ",
+			"are specified, and must evaluate to a bool.\n\n",
+			"Available fields: id, name, version, node_type, purl, description, licenses, license_concluded, suppliers, ",
+			"originators, url_home, url_download, hashes, identifiers, document_id, document_name, document_alias, "+
+				"document_tags.\nThe compareVersions(a, b) function compares two version strings, returning -1, 0 or 1",
+		),
+		Example: `  bomctl query 'name == "log4j-core" && compareVersions(version, "2.17.0") < 0'
+  bomctl query 'purl.startsWith("pkg:npm/") && "MIT" in licenses' SBOM_ID`,
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
+			backend := backendFromContext(cmd)
+
+			defer backend.CloseClient()
+
+			opts.Format = formatValue.String()
+
+			results, err := query.Query(args[0], args[1:], opts)
+			if err != nil {
+				opts.Logger.Fatal(err)
+			}
+
+			var out io.Writer = os.Stdout
+
+			if outputFile != "" {
+				opts.OutputFile, err = os.Create(outputFile.String())
+				if err != nil {
+					opts.Logger.Fatal("error creating output file", "outputFile", outputFile)
+				}
+
+				defer opts.OutputFile.Close()
+
+				out = opts.OutputFile
+			}
+
+			if writeEnvelope(out, opts.Options, envelope.KindQueryResults, results) {
+				return
+			}
+
+			if err := writeQueryResults(out, results, opts.Format); err != nil {
+				opts.Logger.Fatal(err)
+			}
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return completions(cmd, args, toComplete)
+		},
+	}
+
+	queryCmd.Flags().VarP(&outputFile, "output-file", "o", "Path to output file")
+	queryCmd.Flags().VarP(formatValue, "format", "f", formatValue.Usage())
+	queryCmd.Flags().StringArrayVar(&opts.Tags, "tag", []string{},
+		"Tag(s) used to filter documents (can be specified multiple times)")
+
+	cobra.CheckErr(queryCmd.RegisterFlagCompletionFunc("format", formatValue.CompletionFunc()))
+
+	return queryCmd
+}
+
+func writeQueryResults(out io.Writer, results []query.Result, format string) error {
+	if format == queryFormatJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(results); err != nil {
+			return fmt.Errorf("failed to encode query results: %w", err)
+		}
+
+		return nil
+	}
+
+	rows := [][]string{}
+
+	for _, result := range results {
+		document := result.DocumentID
+		if result.DocumentAlias != "" {
+			document = result.DocumentAlias
+		}
+
+		rows = append(rows, []string{document, result.Name, result.Version, result.Purl})
+	}
+
+	fmt.Fprintln(out, lgtable.New().
+		Headers("Document", "Name", "Version", "Purl").
+		Rows(rows...).
+		BorderTop(false).
+		BorderBottom(false).
+		BorderLeft(false).
+		BorderRight(false).
+		BorderHeader(true).
+		StyleFunc(func(_, _ int) lipgloss.Style {
+			return lipgloss.NewStyle().Padding(0, 1)
+		}).
+		Render())
+
+	return nil
+}
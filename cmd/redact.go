@@ -0,0 +1,80 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/redact.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/redact"
+)
+
+func redactCmd() *cobra.Command {
+	opts := &options.RedactOptions{}
+
+	redactCmd := &cobra.Command{
+		Use:   "redact [flags] SBOM_ID",
+		Args:  cobra.ExactArgs(1),
+		Short: "Redact fields of an SBOM document in local storage",
+		Long: fmt.Sprintf("%s%s%s%s%s%s",
+			"Redact fields of an SBOM document in local storage. The redacted document is stored as a new revision ",
+			"of the original, which remains unchanged and can be found with the history command.\n\n",
+			"Fields are specified by path, starting with node or metadata followed by the names of nested fields, ",
+			"and are replaced entirely. Patterns are regular expressions whose matches are replaced in every text ",
+			"field. Node identifiers matching a pattern are replaced with opaque identifiers. The root nodes of the ",
+			"redacted document are given a bomctl:redacted_from property holding the ID of the original document",
+		),
+		Example: `  bomctl redact --field node.suppliers --field node.originators.email SBOM_ID
+  bomctl redact --pattern '[\w.+-]+@example\.com' --pattern '[\w.-]+\.corp\.example\.com' SBOM_ID`,
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
+			backend := backendFromContext(cmd)
+			backend.Logger.SetPrefix("redact")
+
+			defer backend.CloseClient()
+
+			document, err := redact.Redact(args[0], opts)
+			if err != nil {
+				opts.Logger.Fatal(err)
+			}
+
+			opts.Logger.Info("Stored redacted revision", "id", document.GetMetadata().GetId())
+			writeStoredDocuments(opts.Options, backend, document)
+		},
+		ValidArgsFunction: completions,
+	}
+
+	redactCmd.Flags().StringArrayVar(&opts.Fields, "field", []string{},
+		"Path of a field to redact, such as node.suppliers (can be specified multiple times)")
+	redactCmd.Flags().StringArrayVar(&opts.Patterns, "pattern", []string{},
+		"Regular expression whose matches are redacted (can be specified multiple times)")
+	redactCmd.Flags().StringVar(&opts.Replacement, "replacement", redact.DefaultReplacement,
+		"Text that redacted values are replaced with")
+
+	cobra.CheckErr(redactCmd.RegisterFlagCompletionFunc("field",
+		func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			return slices.Clone(redact.FieldNames()), cobra.ShellCompDirectiveNoFileComp
+		}))
+
+	return redactCmd
+}
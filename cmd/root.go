@@ -21,15 +21,19 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/charmbracelet/log"
+	"github.com/protobom/protobom/pkg/sbom"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
 	"github.com/bomctl/bomctl/internal/pkg/logger"
 	"github.com/bomctl/bomctl/internal/pkg/options"
 )
@@ -41,7 +45,16 @@ const (
 	modeUserExec  = 0o100
 )
 
-type optionsKey struct{}
+type (
+	optionsKey struct{}
+
+	// storedDocument identifies a document stored in the cache by a command.
+	storedDocument struct {
+		ID    string `json:"id"`
+		Alias string `json:"alias,omitempty"`
+		Name  string `json:"name,omitempty"`
+	}
+)
 
 func backendFromContext(cmd *cobra.Command) *db.Backend {
 	backend, err := db.BackendFromContext(cmd.Context())
@@ -100,6 +113,14 @@ func initConfig(cmd *cobra.Command) func() {
 	}
 }
 
+func initOutput(cmd *cobra.Command) func() {
+	return func() {
+		// Report errors as envelopes on stdout instead of as text with usage on stderr.
+		structured := envelope.IsStructured(cmd.Flag("output").Value.String())
+		cmd.SilenceErrors, cmd.SilenceUsage = structured, structured
+	}
+}
+
 func optionsFromContext(cmd *cobra.Command) *options.Options {
 	opts, ok := cmd.Context().Value(optionsKey{}).(*options.Options)
 	if !ok {
@@ -117,6 +138,9 @@ func preRun(cmd *cobra.Command, _ []string) {
 		log.SetLevel(log.DebugLevel)
 	}
 
+	outputFormat := cmd.Flag("output").Value.String()
+	logger.SetOutputFormat(outputFormat)
+
 	cacheDir := viper.GetString("cache_dir")
 
 	// Get first top-level subcommand.
@@ -128,6 +152,7 @@ func preRun(cmd *cobra.Command, _ []string) {
 	opts := options.New().
 		WithCacheDir(cacheDir).
 		WithConfigFile(viper.ConfigFileUsed()).
+		WithOutputFormat(outputFormat).
 		WithVerbosity(verbosity).
 		WithLogger(logger.New(subcmd.Name()))
 
@@ -154,27 +179,43 @@ func rootCmd() *cobra.Command {
 
 	cacheDir := directoryValue(defaultCacheDir())
 	configFile := existingFileValue(defaultConfig())
+	outputValue := newChoiceValue("Output format", envelope.FormatText, envelope.FormatJSON, envelope.FormatYAML)
 
 	rootCmd.PersistentFlags().Var(&cacheDir, "cache-dir", "Cache directory")
 	rootCmd.PersistentFlags().Var(&configFile, "config", "Config file")
 	rootCmd.PersistentFlags().CountP("verbose", "v", "Enable debug output")
+	rootCmd.PersistentFlags().Var(outputValue, "output", outputValue.Usage())
+
+	cobra.CheckErr(rootCmd.RegisterFlagCompletionFunc("output", outputValue.CompletionFunc()))
 
-	cobra.OnInitialize(initCache, initConfig(rootCmd))
+	cobra.OnInitialize(initCache, initConfig(rootCmd), initOutput(rootCmd))
 
 	// Bind flags to their associated viper configurations.
 	cobra.CheckErr(viper.BindPFlag("cache_dir", rootCmd.PersistentFlags().Lookup("cache-dir")))
 
 	rootCmd.AddCommand(
 		aliasCmd(),
+		browseCmd(),
+		diffCmd(),
+		enrichCmd(),
 		exportCmd(),
 		fetchCmd(),
+		historyCmd(),
 		importCmd(),
 		linkCmd(),
 		listCmd(),
 		mergeCmd(),
 		pushCmd(),
+		queryCmd(),
+		redactCmd(),
+		serveCmd(),
+		signCmd(),
+		splitCmd(),
 		tagCmd(),
+		trimCmd(),
+		verifyCmd(),
 		versionCmd(),
+		visualizeCmd(),
 	)
 
 	return rootCmd
@@ -190,9 +231,56 @@ func Execute() (rc int) {
 		}
 	}()
 
-	if err := rootCmd().Execute(); err != nil {
+	rootCmd := rootCmd()
+
+	if err := rootCmd.Execute(); err != nil {
 		rc = 1
+
+		// Errors are only silenced once a structured output format has been parsed. Risk findings are already
+		// part of the diff report written to stdout.
+		if rootCmd.SilenceErrors && !errors.Is(err, errRiskFindings) {
+			cobra.CheckErr(envelope.NewError("bomctl", err.Error(), nil).
+				Write(os.Stdout, rootCmd.Flag("output").Value.String()))
+		}
 	}
 
 	return rc
 }
+
+// rejectStructuredOutput exits with an error if a structured output format was selected for a command that
+// has no structured output.
+func rejectStructuredOutput(cmd *cobra.Command, opts *options.Options) {
+	if envelope.IsStructured(opts.OutputFormat) {
+		opts.Logger.Fatalf("The --output option cannot be used with the %s command.", cmd.Name())
+	}
+}
+
+// writeEnvelope writes data to out as an envelope of the given kind if a structured
+// output format was selected, and reports whether it did so.
+func writeEnvelope(out io.Writer, opts *options.Options, kind string, data any) bool {
+	if !envelope.IsStructured(opts.OutputFormat) {
+		return false
+	}
+
+	if err := envelope.New(kind, data).Write(out, opts.OutputFormat); err != nil {
+		opts.Logger.Fatal(err)
+	}
+
+	return true
+}
+
+// writeStoredDocuments writes the IDs of the documents stored by a command to stdout if a structured output
+// format was selected.
+func writeStoredDocuments(opts *options.Options, backend *db.Backend, documents ...*sbom.Document) {
+	data := []storedDocument{}
+
+	for _, document := range documents {
+		data = append(data, storedDocument{
+			ID:    document.GetMetadata().GetId(),
+			Alias: backend.GetDocumentAlias(document.GetMetadata().GetId()),
+			Name:  document.GetMetadata().GetName(),
+		})
+	}
+
+	writeEnvelope(os.Stdout, opts, envelope.KindStoredDocumentList, data)
+}
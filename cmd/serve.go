@@ -0,0 +1,69 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/serve.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/serve"
+)
+
+const defaultServePort = 8080
+
+func serveCmd() *cobra.Command {
+	opts := &options.ServeOptions{}
+
+	serveCmd := &cobra.Command{
+		Use:   "serve [flags]",
+		Args:  cobra.NoArgs,
+		Short: "Serve the SBOM documents in local storage over HTTP",
+		Long: fmt.Sprintf("%s%s",
+			"Serve the SBOM documents in local storage as a JSON API on localhost. With --ui, also serve a web viewer ",
+			"for browsing documents, their dependency graphs and the links between them",
+		),
+		Run: func(cmd *cobra.Command, _ []string) {
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			opts.Options = optionsFromContext(cmd).WithContext(ctx)
+
+			rejectStructuredOutput(cmd, opts.Options)
+
+			backend := backendFromContext(cmd)
+
+			defer backend.CloseClient()
+
+			if err := serve.Serve(opts); err != nil {
+				opts.Logger.Fatal(err)
+			}
+		},
+	}
+
+	serveCmd.Flags().StringVar(&opts.Host, "host", "localhost", "Host address to listen on")
+	serveCmd.Flags().IntVarP(&opts.Port, "port", "p", defaultServePort, "Port to listen on")
+	serveCmd.Flags().BoolVar(&opts.UI, "ui", false, "Serve the web viewer")
+
+	return serveCmd
+}
@@ -0,0 +1,185 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/sign.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/sign"
+)
+
+// documentSignature is a signature made by the sign command, as a base64 encoded detached signature and as
+// a Sigstore bundle.
+//
+//nolint:govet // Field order determines the serialized key order.
+type documentSignature struct {
+	DocumentID string       `json:"documentId"`
+	Alias      string       `json:"alias,omitempty"`
+	KeyHint    string       `json:"keyHint"`
+	Signature  string       `json:"signature"`
+	Bundle     *sign.Bundle `json:"bundle"`
+}
+
+func signCmd() *cobra.Command {
+	opts := &options.SignOptions{}
+	outputFile := outputFileValue("")
+
+	signCmd := &cobra.Command{
+		Use:   "sign [flags] SBOM_ID...",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Sign SBOM document(s) in local storage with a private key",
+		Long: fmt.Sprintf("%s%s%s%s%s%s%s%s",
+			"Sign SBOM document(s) in local storage with an ECDSA or Ed25519 private key. The signed bytes are ",
+			"those written by export with the original format: the bytes a document was imported or fetched from, ",
+			"or those stored with a revision when it was made. Documents with neither, such as merged and split ",
+			"documents, are signed as their serialization in their source format, which is then stored with them.\n\n",
+			"Signatures are stored with the document as Sigstore bundles, replacing any previous signature made ",
+			"with the same key, and written to stdout or --output-file as a base64 encoded detached signature or ",
+			"with --bundle as a Sigstore bundle, both compatible with cosign verify-blob. Keys generated by cosign ",
+			"generate-key-pair are decrypted with the password in the "+sign.PasswordEnvVar+" environment variable",
+		),
+		Example: `  bomctl sign --key cosign.key SBOM_ID
+  bomctl sign --key cosign.key --bundle --output-file sbom.sigstore.json SBOM_ID`,
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
+			backend := backendFromContext(cmd)
+			backend.Logger.SetPrefix("sign")
+
+			defer backend.CloseClient()
+
+			var out io.Writer = os.Stdout
+
+			if outputFile != "" {
+				if len(args) > 1 {
+					opts.Logger.Fatal("The --output-file option cannot be used when more than one SBOM is provided.")
+				}
+
+				file, err := os.Create(outputFile.String())
+				if err != nil {
+					opts.Logger.Fatal("error creating output file", "outputFile", outputFile)
+				}
+
+				defer file.Close()
+
+				out = file
+			}
+
+			signer, err := sign.LoadPrivateKey(opts.KeyPath, []byte(os.Getenv(sign.PasswordEnvVar)))
+			if err != nil {
+				opts.Logger.Fatal(err, "key", opts.KeyPath)
+			}
+
+			structured := envelope.IsStructured(opts.OutputFormat)
+			signatures := []documentSignature{}
+
+			for _, sbomID := range args {
+				bundle, err := sign.Sign(sbomID, signer, opts)
+				if err != nil {
+					opts.Logger.Fatal(err)
+				}
+
+				signatures = append(signatures, newDocumentSignature(backend, sbomID, bundle))
+
+				// With structured output, signatures are only written to stdout as part of the envelope.
+				if structured && outputFile == "" {
+					continue
+				}
+
+				if err := writeSignature(out, bundle, opts.Bundle); err != nil {
+					opts.Logger.Fatal(err)
+				}
+			}
+
+			writeEnvelope(os.Stdout, opts.Options, envelope.KindSignatureList, signatures)
+		},
+		ValidArgsFunction: completions,
+	}
+
+	signCmd.Flags().StringVar(&opts.KeyPath, "key", "", "Path to a PEM encoded private key")
+	signCmd.Flags().BoolVar(&opts.Bundle, "bundle", false, "Write a Sigstore bundle instead of a detached signature")
+	signCmd.Flags().VarP(&outputFile, "output-file", "o", "Path to output file")
+
+	cobra.CheckErr(signCmd.MarkFlagRequired("key"))
+
+	return signCmd
+}
+
+// attestationSigner loads the private key at keyPath to sign attestations with, if one is given.
+func attestationSigner(opts *options.Options, attest bool, keyPath string) crypto.Signer {
+	if keyPath == "" {
+		return nil
+	}
+
+	if !attest {
+		opts.Logger.Fatal("The --key option can only be used with the --attest option.")
+	}
+
+	signer, err := sign.LoadPrivateKey(keyPath, []byte(os.Getenv(sign.PasswordEnvVar)))
+	if err != nil {
+		opts.Logger.Fatal(err, "key", keyPath)
+	}
+
+	return signer
+}
+
+// newDocumentSignature returns the signature of the document with the specified ID or alias made by bundle.
+func newDocumentSignature(backend *db.Backend, sbomID string, bundle *sign.Bundle) documentSignature {
+	documentID := sbomID
+
+	if document, err := backend.GetDocumentByIDOrAlias(sbomID); err == nil && document != nil {
+		documentID = document.GetMetadata().GetId()
+	}
+
+	return documentSignature{
+		DocumentID: documentID,
+		Alias:      backend.GetDocumentAlias(documentID),
+		KeyHint:    bundle.VerificationMaterial.PublicKey.Hint,
+		Signature:  base64.StdEncoding.EncodeToString(bundle.MessageSignature.Signature),
+		Bundle:     bundle,
+	}
+}
+
+func writeSignature(out io.Writer, bundle *sign.Bundle, asBundle bool) error {
+	data := []byte(base64.StdEncoding.EncodeToString(bundle.MessageSignature.Signature))
+
+	if asBundle {
+		var err error
+
+		if data, err = json.Marshal(bundle); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	}
+
+	if _, err := fmt.Fprintln(out, string(data)); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
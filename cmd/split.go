@@ -0,0 +1,79 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/split.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/split"
+)
+
+func splitCmd() *cobra.Command {
+	opts := &options.SplitOptions{}
+
+	splitCmd := &cobra.Command{
+		Use:   "split [flags] SBOM_ID",
+		Args:  cobra.ExactArgs(1),
+		Short: "Split an SBOM document in local storage into several linked documents",
+		Long: fmt.Sprintf("%s%s%s%s%s",
+			"Split an SBOM document in local storage into several documents. Each piece holds the nodes selected by ",
+			"a purl type, a CEL selector (see the query command for the available fields) or a direct dependency of ",
+			"the root elements, along with every node they depend on.\n\nEach piece is stored as a new document, and ",
+			"the nodes it was split off at are linked to it in the original document, which is otherwise unchanged. ",
+			"Tags of the original document are copied to the new documents",
+		),
+		Example: `  bomctl split --top-level SBOM_ID
+  bomctl split --purl-type npm --selector 'name.startsWith("service-")' SBOM_ID`,
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
+			backend := backendFromContext(cmd)
+			backend.Logger.SetPrefix("split")
+
+			defer backend.CloseClient()
+
+			documents, err := split.Split(args[0], opts)
+			if err != nil {
+				opts.Logger.Fatal(err)
+			}
+
+			for _, document := range documents {
+				opts.Logger.Info("Stored split document", "id", document.GetMetadata().GetId(),
+					"name", document.GetMetadata().GetName())
+			}
+
+			writeStoredDocuments(opts.Options, backend, documents...)
+		},
+		ValidArgsFunction: completions,
+	}
+
+	splitCmd.Flags().StringArrayVar(&opts.PurlTypes, "purl-type", []string{},
+		"Purl type whose nodes are split into a document, such as npm (can be specified multiple times)")
+	splitCmd.Flags().StringArrayVar(&opts.Selectors, "selector", []string{},
+		"CEL expression whose matching nodes are split into a document (can be specified multiple times)")
+	splitCmd.Flags().BoolVar(&opts.TopLevel, "top-level", false,
+		"Split each direct dependency of the root elements into a document")
+	splitCmd.Flags().StringArrayVar(&opts.Tags, "tag", []string{},
+		"Tag(s) to apply to split documents (can be specified multiple times)")
+
+	return splitCmd
+}
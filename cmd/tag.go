@@ -28,8 +28,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
 )
 
+type tagList struct {
+	ID   string   `json:"id"`
+	Tags []string `json:"tags"`
+}
+
 func tagCmd() *cobra.Command {
 	tagCmd := &cobra.Command{
 		Use:   "tag",
@@ -137,6 +143,11 @@ func tagListCmd() *cobra.Command {
 
 			sort.Strings(tags)
 
+			data := tagList{ID: document.GetMetadata().GetId(), Tags: append([]string{}, tags...)}
+			if writeEnvelope(os.Stdout, optionsFromContext(cmd), envelope.KindTagList, data) {
+				return
+			}
+
 			fmt.Fprintf(os.Stdout, "\nTags for %v\n%v\n", args[0], strings.Repeat("─", cliTableWidth))
 			fmt.Fprintf(os.Stdout, "%v\n\n", strings.Join(tags, "\n"))
 		},
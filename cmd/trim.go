@@ -0,0 +1,71 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/trim.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/trim"
+)
+
+func trimCmd() *cobra.Command {
+	opts := &options.TrimOptions{}
+
+	trimCmd := &cobra.Command{
+		Use:   "trim [flags] SBOM_ID",
+		Args:  cobra.ExactArgs(1),
+		Short: "Remove subtrees from the dependency graph of an SBOM document in local storage",
+		Long: fmt.Sprintf("%s%s%s%s",
+			"Remove subtrees from the dependency graph of an SBOM document in local storage. The trimmed document ",
+			"is stored as a new revision of the original, which remains unchanged and can be found with the history ",
+			"command.\n\nNodes are selected by purl type, by node ID or by depth from the root elements. Nodes that ",
+			"are no longer reachable from the root elements are removed as well, and edges are rewritten accordingly",
+		),
+		Example: `  bomctl trim --purl-type npm SBOM_ID
+  bomctl trim --node 'pkg:golang/github.com/example/tool@v1.0.0' --depth 2 SBOM_ID`,
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
+			backend := backendFromContext(cmd)
+			backend.Logger.SetPrefix("trim")
+
+			defer backend.CloseClient()
+
+			document, err := trim.Trim(args[0], opts)
+			if err != nil {
+				opts.Logger.Fatal(err)
+			}
+
+			opts.Logger.Info("Stored trimmed revision", "id", document.GetMetadata().GetId())
+			writeStoredDocuments(opts.Options, backend, document)
+		},
+		ValidArgsFunction: completions,
+	}
+
+	trimCmd.Flags().StringArrayVar(&opts.PurlTypes, "purl-type", []string{},
+		"Purl type whose nodes are removed, such as npm (can be specified multiple times)")
+	trimCmd.Flags().StringArrayVar(&opts.NodeIDs, "node", []string{},
+		"ID of a node to remove (can be specified multiple times)")
+	trimCmd.Flags().IntVar(&opts.MaxDepth, "depth", 0, "Maximum depth from the root elements (0 for unlimited)")
+
+	return trimCmd
+}
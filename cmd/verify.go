@@ -0,0 +1,155 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/verify.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"crypto"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	lgtable "github.com/charmbracelet/lipgloss/table"
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/sign"
+	"github.com/bomctl/bomctl/internal/pkg/verify"
+)
+
+func verifyCmd() *cobra.Command {
+	opts := &options.VerifyOptions{}
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify [flags] SBOM_ID...",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Verify the integrity and signatures of SBOM document(s) in local storage",
+		Long: fmt.Sprintf("%s%s%s%s%s%s",
+			"Verify the integrity and signatures of SBOM document(s) in local storage, and report whether each ",
+			"document passed or failed. The original content of a document must match the SHA-256 digest recorded ",
+			"when it was imported, fetched or revised.\n\n",
+			"Signatures stored by the sign command are verified against the public key(s) given with --key, along ",
+			"with a detached signature or Sigstore bundle file. When public keys are given, a document fails unless ",
+			"at least one of its signatures is verified with them. The command exits with a non-zero code on failure",
+		),
+		Example: `  bomctl verify SBOM_ID
+  bomctl verify --key cosign.pub SBOM_ID
+  bomctl verify --key cosign.pub --signature sbom.sig SBOM_ID`,
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
+			backend := backendFromContext(cmd)
+			backend.Logger.SetPrefix("verify")
+
+			defer backend.CloseClient()
+
+			if (opts.SignaturePath != "" || opts.BundlePath != "") && len(args) > 1 {
+				opts.Logger.Fatal("The --signature and --bundle options cannot be used when more than one SBOM is provided.")
+			}
+
+			publicKeys := []crypto.PublicKey{}
+
+			for _, keyPath := range opts.KeyPaths {
+				publicKey, err := sign.LoadPublicKey(keyPath)
+				if err != nil {
+					opts.Logger.Fatal(err, "key", keyPath)
+				}
+
+				publicKeys = append(publicKeys, publicKey)
+			}
+
+			results := []*verify.Result{}
+			failed := 0
+
+			for _, sbomID := range args {
+				result, err := verify.Verify(sbomID, publicKeys, opts)
+				if err != nil {
+					opts.Logger.Fatal(err)
+				}
+
+				if !result.Passed() {
+					failed++
+
+					opts.Logger.Error("Verification failed", "sbomID", result.DocumentID, "reason", result.Message)
+				}
+
+				results = append(results, result)
+			}
+
+			if !writeEnvelope(os.Stdout, opts.Options, envelope.KindVerificationList, results) {
+				writeVerificationResults(results)
+			}
+
+			if failed > 0 {
+				opts.Logger.Error("Verification failed for SBOM document(s)", "count", failed)
+				os.Exit(1)
+			}
+		},
+		ValidArgsFunction: completions,
+	}
+
+	verifyCmd.Flags().StringArrayVar(&opts.KeyPaths, "key", []string{},
+		"Path to a PEM encoded public key (can be specified multiple times)")
+	verifyCmd.Flags().StringVar(&opts.SignaturePath, "signature", "", "Path to a detached signature to verify")
+	verifyCmd.Flags().StringVar(&opts.BundlePath, "bundle", "", "Path to a Sigstore bundle to verify")
+
+	return verifyCmd
+}
+
+func writeVerificationResults(results []*verify.Result) {
+	rows := [][]string{}
+
+	for _, result := range results {
+		counts := map[string]int{}
+
+		for _, check := range result.Signatures {
+			counts[check.Status]++
+		}
+
+		signatures := []string{}
+
+		for _, status := range []string{verify.StatusPass, verify.StatusFail, verify.StatusSkipped} {
+			if counts[status] > 0 {
+				signatures = append(signatures, fmt.Sprintf("%d %s", counts[status], status))
+			}
+		}
+
+		if len(signatures) == 0 {
+			signatures = append(signatures, "none")
+		}
+
+		rows = append(rows, []string{
+			result.DocumentID, result.Alias, result.Digest.Status, strings.Join(signatures, ", "), result.Status,
+		})
+	}
+
+	fmt.Fprintln(os.Stdout, lgtable.New().
+		Headers("ID", "Alias", "Digest", "Signatures", "Result").
+		Rows(rows...).
+		BorderTop(false).
+		BorderBottom(false).
+		BorderLeft(false).
+		BorderRight(false).
+		BorderHeader(true).
+		StyleFunc(func(_, _ int) lipgloss.Style {
+			return lipgloss.NewStyle().Padding(0, 1)
+		}).
+		Render())
+}
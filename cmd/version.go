@@ -21,9 +21,11 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
 	"github.com/bomctl/bomctl/internal/pkg/logger"
 )
 
@@ -51,12 +53,22 @@ var (
 	VersionString = fmt.Sprintf("%s (built on %s)", Version, BuildDate)
 )
 
+type versionInfo struct {
+	Version   string `json:"version"`
+	BuildDate string `json:"buildDate"`
+}
+
 func versionCmd() *cobra.Command {
 	versionCmd := &cobra.Command{
 		Use:   "version",
 		Short: "Show version",
 		Long:  "Print the version",
-		Run: func(_ *cobra.Command, _ []string) {
+		Run: func(cmd *cobra.Command, _ []string) {
+			data := versionInfo{Version: Version, BuildDate: BuildDate}
+			if writeEnvelope(os.Stdout, optionsFromContext(cmd), envelope.KindVersion, data) {
+				return
+			}
+
 			logger.New("bomctl").Print("", "version", Version, "buildDate", BuildDate)
 		},
 	}
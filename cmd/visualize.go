@@ -0,0 +1,85 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: cmd/visualize.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/visualize"
+)
+
+func visualizeCmd() *cobra.Command {
+	opts := &options.VisualizeOptions{}
+	outputFile := outputFileValue("")
+	formatValue := newChoiceValue("Output format", visualize.FormatDOT, visualize.FormatMermaid)
+
+	visualizeCmd := &cobra.Command{
+		Use:   "visualize [flags] SBOM_ID",
+		Args:  cobra.ExactArgs(1),
+		Short: "Render the dependency graph of an SBOM document",
+		Long: fmt.Sprintf("%s%s",
+			"Render the dependency graph of an SBOM document in local storage as Graphviz DOT or a Mermaid ",
+			"flowchart, starting from the document's root elements",
+		),
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.Options = optionsFromContext(cmd)
+
+			rejectStructuredOutput(cmd, opts.Options)
+
+			backend := backendFromContext(cmd)
+
+			defer backend.CloseClient()
+
+			opts.Format = formatValue.String()
+
+			if outputFile != "" {
+				out, err := os.Create(outputFile.String())
+				if err != nil {
+					opts.Logger.Fatal("error creating output file", "outputFile", outputFile)
+				}
+
+				opts.OutputFile = out
+
+				defer opts.OutputFile.Close()
+			}
+
+			if err := visualize.Visualize(args[0], opts); err != nil {
+				opts.Logger.Fatal(err)
+			}
+		},
+		ValidArgsFunction: completions,
+	}
+
+	visualizeCmd.Flags().VarP(&outputFile, "output-file", "o", "Path to output file")
+	visualizeCmd.Flags().VarP(formatValue, "format", "f", formatValue.Usage())
+	visualizeCmd.Flags().StringArrayVar(&opts.CollapseTypes, "collapse", []string{},
+		"Purl type whose nodes are collapsed into a single node (can be specified multiple times)")
+	visualizeCmd.Flags().IntVar(&opts.MaxDepth, "depth", 0, "Maximum depth from the root elements (0 for unlimited)")
+	visualizeCmd.Flags().StringArrayVar(&opts.Highlight, "highlight", []string{},
+		"ID, purl or name of a node to highlight (can be specified multiple times)")
+
+	cobra.CheckErr(visualizeCmd.RegisterFlagCompletionFunc("format", formatValue.CompletionFunc()))
+
+	return visualizeCmd
+}
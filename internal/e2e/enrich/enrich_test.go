@@ -0,0 +1,59 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/e2e/enrich/enrich_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package e2e_enrich_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/rogpeppe/go-internal/testscript"
+
+	"github.com/bomctl/bomctl/cmd"
+	"github.com/bomctl/bomctl/internal/e2e/e2eutil"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+func TestBomctlEnrich(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	t.Parallel()
+	testscript.Run(t, testscript.Params{
+		Dir:                 ".",
+		RequireExplicitExec: true,
+		Cmds:                e2eutil.CustomCommands(),
+		Setup: func(env *testscript.Env) error {
+			server := testutil.NewTEAServer(testutil.TEARelease{
+				Purl:    "pkg:golang/dario.cat/mergo@v1.0.0",
+				Version: "v1.0.0",
+			})
+
+			env.Defer(server.Close)
+			env.Setenv("TEA_URL", server.URL+testutil.TEAPath)
+
+			return nil
+		},
+	})
+}
+
+func TestMain(m *testing.M) {
+	os.Exit(testscript.RunMain(m, map[string]func() int{"bomctl": cmd.Execute}))
+}
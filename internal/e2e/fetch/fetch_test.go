@@ -20,6 +20,8 @@
 package e2e_fetch_test
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
@@ -40,6 +42,12 @@ func TestBomctlFetch(t *testing.T) {
 		Setup: func(env *testscript.Env) error {
 			env.Setenv("HTTPS_PROXY", os.Getenv("HTTPS_PROXY"))
 
+			// Serve the files of the test script, so that SBOMs and their signatures can be fetched locally.
+			server := httptest.NewServer(http.FileServer(http.Dir(env.WorkDir)))
+
+			env.Defer(server.Close)
+			env.Setenv("SBOM_SERVER", server.URL)
+
 			return nil
 		},
 	})
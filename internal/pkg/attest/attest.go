@@ -0,0 +1,278 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/attest/attest.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package attest
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/outpututil"
+	"github.com/bomctl/bomctl/internal/pkg/sign"
+)
+
+const (
+	// EnvelopeMediaType is the media type of DSSE envelopes, used for attestation layers of OCI images.
+	EnvelopeMediaType = "application/vnd.dsse.envelope.v1+json"
+
+	// PayloadType is the DSSE payload type of in-toto statements.
+	PayloadType = "application/vnd.in-toto+json"
+
+	// PredicateTypeCycloneDX is the in-toto predicate type of CycloneDX SBOMs.
+	PredicateTypeCycloneDX = "https://cyclonedx.org/bom"
+
+	// PredicateTypeSPDX is the in-toto predicate type of SPDX SBOMs.
+	PredicateTypeSPDX = "https://spdx.dev/Document"
+
+	// StatementType is the type of the in-toto statements created by bomctl.
+	StatementType = "https://in-toto.io/Statement/v1"
+
+	statementTypePrefix = "https://in-toto.io/Statement/"
+)
+
+var (
+	errNoSubject            = errors.New("no root node of the document has a supported hash to use as subject")
+	errUnsupportedEncoding  = errors.New("only JSON encoded SBOMs can be attested")
+	errUnsupportedPayload   = errors.New("unsupported DSSE payload type")
+	errUnsupportedPredicate = errors.New("in-toto statement does not hold an SBOM")
+)
+
+// subjectAlgorithms maps the hash algorithms of protobom nodes to the names of in-toto digest set entries.
+//
+//nolint:gochecknoglobals
+var subjectAlgorithms = map[sbom.HashAlgorithm]string{
+	sbom.HashAlgorithm_MD5:      "md5",
+	sbom.HashAlgorithm_SHA1:     "sha1",
+	sbom.HashAlgorithm_SHA224:   "sha224",
+	sbom.HashAlgorithm_SHA256:   "sha256",
+	sbom.HashAlgorithm_SHA384:   "sha384",
+	sbom.HashAlgorithm_SHA512:   "sha512",
+	sbom.HashAlgorithm_SHA3_256: "sha3_256",
+	sbom.HashAlgorithm_SHA3_384: "sha3_384",
+	sbom.HashAlgorithm_SHA3_512: "sha3_512",
+	sbom.HashAlgorithm_BLAKE3:   "blake3",
+}
+
+type (
+	// WriteFunc writes a document to a stream in the given format, like outpututil.WriteStream.
+	WriteFunc func(*sbom.Document, formats.Format, *options.Options, io.WriteCloser) error
+
+	// Envelope is a DSSE envelope, as written by cosign attest.
+	Envelope struct {
+		PayloadType string      `json:"payloadType"`
+		Payload     []byte      `json:"payload"`
+		Signatures  []Signature `json:"signatures"`
+	}
+
+	Signature struct {
+		KeyID string `json:"keyid"`
+		Sig   []byte `json:"sig"`
+	}
+
+	// Statement is an in-toto statement with an SBOM as its predicate.
+	//
+	//nolint:govet // Field order determines the serialized key order.
+	Statement struct {
+		Type          string          `json:"_type"`
+		Subject       []Subject       `json:"subject"`
+		PredicateType string          `json:"predicateType"`
+		Predicate     json.RawMessage `json:"predicate"`
+	}
+
+	//nolint:govet // Field order determines the serialized key order.
+	Subject struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	}
+)
+
+// Unwrap extracts the SBOM from data if it holds a DSSE envelope or an in-toto statement with an SPDX or
+// CycloneDX predicate, and returns it along with the envelope or statement. Any other data is returned
+// unchanged, without an envelope.
+func Unwrap(data []byte) (sbomData, envelope []byte, err error) {
+	statementData := data
+
+	dsse := &Envelope{}
+	if err := json.Unmarshal(data, dsse); err == nil && dsse.PayloadType != "" && dsse.Payload != nil {
+		if dsse.PayloadType != PayloadType {
+			return nil, nil, fmt.Errorf("%w: %s", errUnsupportedPayload, dsse.PayloadType)
+		}
+
+		statementData = dsse.Payload
+	}
+
+	statement := &Statement{}
+	if err := json.Unmarshal(statementData, statement); err != nil ||
+		!strings.HasPrefix(statement.Type, statementTypePrefix) {
+		return data, nil, nil
+	}
+
+	if !strings.HasPrefix(statement.PredicateType, PredicateTypeCycloneDX) &&
+		!strings.HasPrefix(statement.PredicateType, PredicateTypeSPDX) {
+		return nil, nil, fmt.Errorf("%w: %s", errUnsupportedPredicate, statement.PredicateType)
+	}
+
+	return statement.Predicate, data, nil
+}
+
+// Wrap returns a DSSE envelope holding an in-toto statement with sbomData as its predicate, and the hashes
+// of the root nodes of document as its subject. The envelope is signed with signer, unless it is nil.
+func Wrap(document *sbom.Document, format formats.Format, sbomData []byte, signer crypto.Signer) ([]byte, error) {
+	if format.Encoding() != formats.JSON {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedEncoding, format)
+	}
+
+	predicateType := PredicateTypeSPDX
+	if format.Type() == formats.CDXFORMAT {
+		predicateType = PredicateTypeCycloneDX
+	}
+
+	subject := subjects(document)
+	if len(subject) == 0 {
+		return nil, fmt.Errorf("%w: %s", errNoSubject, document.GetMetadata().GetId())
+	}
+
+	payload, err := json.Marshal(&Statement{
+		Type:          StatementType,
+		Subject:       subject,
+		PredicateType: predicateType,
+		Predicate:     json.RawMessage(bytes.TrimSpace(sbomData)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	envelope := &Envelope{PayloadType: PayloadType, Payload: payload, Signatures: []Signature{}}
+
+	if signer != nil {
+		keyID, err := sign.KeyHint(signer.Public())
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		bundle, err := sign.SignPayload(signer, pae(PayloadType, payload))
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		envelope.Signatures = append(envelope.Signatures, Signature{KeyID: keyID, Sig: bundle.MessageSignature.Signature})
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return data, nil
+}
+
+// WriteStream writes document to stream in the given format, wrapped in a DSSE envelope by Wrap.
+func WriteStream(
+	document *sbom.Document, format formats.Format, signer crypto.Signer, opts *options.Options, stream io.Writer,
+) error {
+	return wrapStream(outpututil.WriteStream, document, format, signer, opts, stream)
+}
+
+// StreamWriter returns write, or the function writing documents with write as attestations signed with signer
+// if enabled is set, so that callers write documents either way alike.
+func StreamWriter(enabled bool, signer crypto.Signer, write WriteFunc) WriteFunc {
+	if !enabled {
+		return write
+	}
+
+	return func(document *sbom.Document, format formats.Format, opts *options.Options, stream io.WriteCloser) error {
+		return wrapStream(write, document, format, signer, opts, stream)
+	}
+}
+
+func wrapStream(
+	write WriteFunc,
+	document *sbom.Document,
+	format formats.Format,
+	signer crypto.Signer,
+	opts *options.Options,
+	stream io.Writer,
+) error {
+	// Documents in their original format are attested with the predicate type of their source format.
+	if format == db.OriginalFormat {
+		format = formats.Format(document.GetMetadata().GetSourceData().GetFormat())
+	}
+
+	buf := &outpututil.BufferWriter{Buffer: &bytes.Buffer{}}
+
+	if err := write(document, format, opts, buf); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	data, err := Wrap(document, format, buf.Bytes(), signer)
+	if err != nil {
+		return err
+	}
+
+	if _, err := stream.Write(data); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// pae returns the DSSE pre-authentication encoding of payload, which is the message that gets signed.
+func pae(payloadType string, payload []byte) []byte {
+	return fmt.Appendf(nil, "DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload)
+}
+
+func subjects(document *sbom.Document) []Subject {
+	subject := []Subject{}
+
+	for _, node := range document.GetNodeList().GetRootNodes() {
+		digest := map[string]string{}
+
+		for algorithm, value := range node.GetHashes() {
+			if name, ok := subjectAlgorithms[sbom.HashAlgorithm(algorithm)]; ok {
+				digest[name] = strings.ToLower(value)
+			}
+		}
+
+		if len(digest) == 0 {
+			continue
+		}
+
+		name := node.GetName()
+		if name == "" {
+			name = node.GetId()
+		}
+
+		subject = append(subject, Subject{Name: name, Digest: digest})
+	}
+
+	slices.SortFunc(subject, func(a, b Subject) int { return strings.Compare(a.Name, b.Name) })
+
+	return subject
+}
@@ -0,0 +1,174 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/attest/attest_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package attest_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/attest"
+	"github.com/bomctl/bomctl/internal/pkg/sign"
+)
+
+const sbomData = `{"bomFormat":"CycloneDX","specVersion":"1.5","version":1}`
+
+type attestSuite struct {
+	suite.Suite
+	document *sbom.Document
+	key      ed25519.PrivateKey
+}
+
+func (as *attestSuite) SetupSuite() {
+	var err error
+
+	_, as.key, err = ed25519.GenerateKey(rand.Reader)
+	as.Require().NoError(err)
+
+	as.document = sbom.NewDocument()
+	as.document.Metadata.Id = "urn:uuid:9f8e7d6c-5b4a-4392-8170-6a5b4c3d2e1f"
+	as.document.NodeList.AddRootNode(&sbom.Node{
+		Id:   "app",
+		Name: "app",
+		Hashes: map[int32]string{
+			int32(sbom.HashAlgorithm_SHA256): "2C26B46B68FFC68FF99B453C1D30413413422D706483BFA0F98A5E886266E7AE",
+			int32(sbom.HashAlgorithm_MD6):    "ignored",
+		},
+	})
+	as.document.NodeList.AddRootNode(&sbom.Node{Id: "unhashed", Name: "unhashed"})
+}
+
+func (as *attestSuite) unmarshalEnvelope(data []byte) (*attest.Envelope, *attest.Statement) {
+	envelope := &attest.Envelope{}
+	as.Require().NoError(json.Unmarshal(data, envelope))
+
+	statement := &attest.Statement{}
+	as.Require().NoError(json.Unmarshal(envelope.Payload, statement))
+
+	return envelope, statement
+}
+
+func (as *attestSuite) TestWrap() {
+	data, err := attest.Wrap(as.document, formats.CDX15JSON, []byte(sbomData+"\n"), as.key)
+	as.Require().NoError(err)
+
+	envelope, statement := as.unmarshalEnvelope(data)
+
+	as.Equal(attest.PayloadType, envelope.PayloadType)
+	as.Equal(attest.StatementType, statement.Type)
+	as.Equal(attest.PredicateTypeCycloneDX, statement.PredicateType)
+	as.JSONEq(sbomData, string(statement.Predicate))
+	as.Equal([]attest.Subject{{
+		Name:   "app",
+		Digest: map[string]string{"sha256": "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"},
+	}}, statement.Subject)
+
+	// The signature covers the DSSE pre-authentication encoding of the payload.
+	keyID, err := sign.KeyHint(as.key.Public())
+	as.Require().NoError(err)
+	as.Require().Len(envelope.Signatures, 1)
+	as.Equal(keyID, envelope.Signatures[0].KeyID)
+
+	pae := fmt.Sprintf("DSSEv1 %d %s %d %s", len(attest.PayloadType), attest.PayloadType,
+		len(envelope.Payload), envelope.Payload)
+	publicKey, ok := as.key.Public().(ed25519.PublicKey)
+	as.Require().True(ok)
+	as.True(ed25519.Verify(publicKey, []byte(pae), envelope.Signatures[0].Sig))
+
+	data, err = attest.Wrap(as.document, formats.SPDX23JSON, []byte(`{"spdxVersion":"SPDX-2.3"}`), nil)
+	as.Require().NoError(err)
+
+	envelope, statement = as.unmarshalEnvelope(data)
+
+	as.Empty(envelope.Signatures)
+	as.Equal(attest.PredicateTypeSPDX, statement.PredicateType)
+}
+
+func (as *attestSuite) TestWrapErrors() {
+	_, err := attest.Wrap(as.document, formats.Format("application/vnd.cyclonedx+xml;version=1.5"), []byte("<bom/>"), nil)
+	as.Require().Error(err)
+
+	unhashed := sbom.NewDocument()
+	unhashed.NodeList.AddRootNode(&sbom.Node{Id: "unhashed"})
+
+	_, err = attest.Wrap(unhashed, formats.CDX15JSON, []byte(sbomData), nil)
+	as.Require().Error(err)
+}
+
+func (as *attestSuite) TestUnwrap() {
+	envelope, err := attest.Wrap(as.document, formats.CDX15JSON, []byte(sbomData), as.key)
+	as.Require().NoError(err)
+
+	statement := fmt.Sprintf(`{"_type":"https://in-toto.io/Statement/v0.1","subject":[],`+
+		`"predicateType":"https://cyclonedx.org/bom/v1.5","predicate":%s}`, sbomData)
+
+	for _, data := range []struct {
+		name     string
+		input    string
+		expected string
+		envelope bool
+		wantErr  bool
+	}{
+		{name: "DSSE envelope", input: string(envelope), expected: sbomData, envelope: true},
+		{name: "in-toto statement", input: statement, expected: sbomData, envelope: true},
+		{name: "plain SBOM", input: sbomData, expected: sbomData},
+		{name: "XML SBOM", input: "<bom/>", expected: "<bom/>"},
+		{
+			name:    "unsupported payload type",
+			input:   `{"payloadType":"text/plain","payload":"dGV4dA==","signatures":[]}`,
+			wantErr: true,
+		},
+		{
+			name: "unsupported predicate type",
+			input: `{"_type":"https://in-toto.io/Statement/v1","subject":[],` +
+				`"predicateType":"https://slsa.dev/provenance/v1","predicate":{}}`,
+			wantErr: true,
+		},
+	} {
+		as.Run(data.name, func() {
+			sbomData, wrapper, err := attest.Unwrap([]byte(data.input))
+			if data.wantErr {
+				as.Require().Error(err)
+
+				return
+			}
+
+			as.Require().NoError(err)
+			as.Equal(data.expected, string(sbomData))
+
+			if data.envelope {
+				as.Equal(data.input, string(wrapper))
+			} else {
+				as.Nil(wrapper)
+			}
+		})
+	}
+}
+
+func TestAttestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(attestSuite))
+}
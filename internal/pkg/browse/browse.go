@@ -0,0 +1,277 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/browse/browse.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package browse
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+const (
+	defaultHeight = 24
+	defaultWidth  = 80
+
+	// Lines used by the header and footer around the list.
+	chromeHeight = 5
+)
+
+var errDocumentNotFound = errors.New("document not found")
+
+type (
+	// Model is the bubbletea model of the document browser. It holds a stack of screens,
+	// the last of which is displayed.
+	Model struct {
+		err       error
+		backend   *db.Backend
+		opts      *options.BrowseOptions
+		styles    *styles
+		screens   []*screen
+		search    textinput.Model
+		width     int
+		height    int
+		searching bool
+	}
+
+	styles struct {
+		title    lipgloss.Style
+		faint    lipgloss.Style
+		cursor   lipgloss.Style
+		selected lipgloss.Style
+		err      lipgloss.Style
+	}
+)
+
+// Browse opens the interactive browser on the documents with the specified IDs or aliases,
+// or on all documents in the cache if none are specified.
+func Browse(sbomIDs []string, opts *options.BrowseOptions) error {
+	model, err := NewModel(sbomIDs, opts)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tea.NewProgram(model, tea.WithAltScreen(), tea.WithContext(opts.Context())).Run(); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// NewModel creates a Model listing the documents with the specified IDs or aliases,
+// filtered by opts.Tags if set.
+func NewModel(sbomIDs []string, opts *options.BrowseOptions) (*Model, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	documents, err := backend.GetDocumentsByIDOrAlias(sbomIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if len(opts.Tags) > 0 {
+		if documents, err = backend.FilterDocumentsByTag(documents, opts.Tags...); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	search := textinput.New()
+	search.Prompt = "/"
+
+	model := &Model{
+		backend: backend,
+		opts:    opts,
+		styles:  newStyles(),
+		search:  search,
+		width:   defaultWidth,
+		height:  defaultHeight,
+	}
+
+	model.screens = []*screen{model.documentsScreen(documents)}
+
+	return model, nil
+}
+
+func (m *Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.current().move(0, m.listHeight())
+	case tea.KeyMsg:
+		if m.searching {
+			return m, m.updateSearch(msg)
+		}
+
+		return m, m.updateList(msg)
+	}
+
+	return m, nil
+}
+
+func (m *Model) View() string {
+	current := m.current()
+
+	titles := make([]string, len(m.screens))
+	for idx, s := range m.screens {
+		titles[idx] = s.title
+	}
+
+	header := m.styles.title.MaxWidth(m.width).Render(strings.Join(titles, " › "))
+
+	footer := current.position() + " • ↑/↓ move • enter open • / search"
+	if current.links != nil {
+		footer += " • l links"
+	}
+
+	footer += " • esc back • q quit"
+
+	status := ""
+
+	switch {
+	case m.err != nil:
+		status = m.styles.err.Render(m.err.Error())
+	case m.searching:
+		status = m.search.View()
+	case current.query != "":
+		status = m.styles.faint.Render("filter: " + current.query)
+	}
+
+	return strings.Join([]string{
+		header,
+		"",
+		current.render(m.listHeight(), m.width, m.styles),
+		"",
+		status,
+		m.styles.faint.MaxWidth(m.width).Render(footer),
+	}, "\n")
+}
+
+func (m *Model) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch msg.Type {
+	case tea.KeyEnter:
+		m.searching = false
+		m.search.Blur()
+
+		return nil
+	case tea.KeyEsc:
+		m.searching = false
+		m.search.Blur()
+		m.current().filter("")
+
+		return nil
+	case tea.KeyCtrlC:
+		return tea.Quit
+	default:
+		var cmd tea.Cmd
+
+		m.search, cmd = m.search.Update(msg)
+		m.current().filter(m.search.Value())
+
+		return cmd
+	}
+}
+
+func (m *Model) updateList(msg tea.KeyMsg) tea.Cmd {
+	current := m.current()
+	height := m.listHeight()
+	m.err = nil
+
+	switch msg.String() {
+	case "ctrl+c", "q":
+		return tea.Quit
+	case "up", "k":
+		current.move(-1, height)
+	case "down", "j":
+		current.move(1, height)
+	case "pgup", "b":
+		current.move(-height, height)
+	case "pgdown", "f", " ":
+		current.move(height, height)
+	case "home", "g":
+		current.move(-len(current.visible), height)
+	case "end", "G":
+		current.move(len(current.visible), height)
+	case "/":
+		m.searching = true
+		m.search.SetValue(current.query)
+		m.search.CursorEnd()
+
+		return m.search.Focus()
+	case "enter", "right":
+		if selected := current.selected(); selected != nil && selected.open != nil {
+			m.push(selected.open)
+		}
+	case "l":
+		if current.links != nil {
+			m.push(current.links)
+		}
+	case "esc", "backspace", "left":
+		switch {
+		case current.query != "":
+			current.filter("")
+		case len(m.screens) > 1:
+			m.screens = m.screens[:len(m.screens)-1]
+		}
+	}
+
+	return nil
+}
+
+func (m *Model) push(open func() (*screen, error)) {
+	next, err := open()
+	if err != nil {
+		m.err = err
+
+		return
+	}
+
+	m.screens = append(m.screens, next)
+}
+
+func (m *Model) current() *screen {
+	return m.screens[len(m.screens)-1]
+}
+
+func (m *Model) listHeight() int {
+	return max(1, m.height-chromeHeight)
+}
+
+func newStyles() *styles {
+	return &styles{
+		title:    lipgloss.NewStyle().Bold(true),
+		faint:    lipgloss.NewStyle().Faint(true),
+		cursor:   lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(termenv.ANSICyan)),
+		selected: lipgloss.NewStyle().Bold(true),
+		err:      lipgloss.NewStyle().Foreground(lipgloss.ANSIColor(termenv.ANSIRed)),
+	}
+}
@@ -0,0 +1,190 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/browse/browse_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package browse_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/browse"
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+type browseSuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	documentInfo []testutil.DocumentInfo
+}
+
+func (bs *browseSuite) SetupSuite() {
+	var err error
+
+	bs.Backend, err = testutil.NewTestBackend()
+	bs.Require().NoError(err, "failed database backend creation")
+
+	bs.documentInfo, err = testutil.AddTestDocuments(bs.Backend)
+	bs.Require().NoError(err, "failed database backend setup")
+
+	bs.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, bs.Backend))
+
+	bs.Require().NoError(bs.Backend.AddDocumentAnnotations(
+		bs.documentInfo[0].Document.GetMetadata().GetId(),
+		db.LinkToAnnotation,
+		bs.documentInfo[1].Document.GetMetadata().GetId(),
+	))
+}
+
+func (bs *browseSuite) TearDownSuite() {
+	bs.Backend.CloseClient()
+}
+
+func (bs *browseSuite) newModel(ids ...string) *browse.Model {
+	bs.T().Helper()
+
+	model, err := browse.NewModel(ids, &options.BrowseOptions{Options: bs.Options})
+	bs.Require().NoError(err)
+
+	model.Update(tea.WindowSizeMsg{Width: 200, Height: 40})
+
+	return model
+}
+
+func (bs *browseSuite) press(model *browse.Model, keys ...string) string {
+	bs.T().Helper()
+
+	for _, key := range keys {
+		var msg tea.KeyMsg
+
+		switch key {
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		case "down":
+			msg = tea.KeyMsg{Type: tea.KeyDown}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+		}
+
+		model.Update(msg)
+	}
+
+	return model.View()
+}
+
+func (bs *browseSuite) TestDocuments() {
+	view := bs.newModel().View()
+
+	bs.Contains(view, "Documents")
+	bs.Contains(view, "> "+bs.documentInfo[0].Document.GetMetadata().GetId())
+	bs.Contains(view, bs.documentInfo[1].Document.GetMetadata().GetId())
+	bs.Contains(view, "1/2")
+
+	view = bs.newModel("spdx").View()
+	bs.NotContains(view, bs.documentInfo[0].Document.GetMetadata().GetId())
+	bs.Contains(view, "1/1")
+
+	model, err := browse.NewModel(nil, &options.BrowseOptions{Options: bs.Options, Tags: []string{"tag3"}})
+	bs.Require().NoError(err)
+	bs.Contains(model.View(), "1/1")
+}
+
+func (bs *browseSuite) TestSearch() {
+	model := bs.newModel()
+
+	// Search documents by alias, then clear the filter.
+	view := bs.press(model, "/", "s", "p", "d", "x", "enter")
+	bs.Contains(view, "filter: spdx")
+	bs.Contains(view, "1/1")
+
+	view = bs.press(model, "esc")
+	bs.NotContains(view, "filter:")
+	bs.Contains(view, "1/2")
+
+	// Open the CycloneDX document and search nodes by purl.
+	nodes := bs.documentInfo[0].Document.GetNodeList().GetNodes()
+	view = bs.press(model, "enter")
+	bs.Contains(view, "Documents › "+bs.documentInfo[0].Document.GetMetadata().GetId()+" (cdx)")
+	bs.Contains(view, "1/"+strconv.Itoa(len(nodes)))
+
+	view = bs.press(model, "/", "p", "k", "g", ":", "n", "o", "m", "a", "t", "c", "h")
+	bs.Contains(view, "0/"+strconv.Itoa(len(nodes)))
+	bs.Contains(view, "No matching items")
+
+	view = bs.press(model, "esc")
+	bs.Contains(view, "1/"+strconv.Itoa(len(nodes)))
+}
+
+func (bs *browseSuite) TestNode() {
+	model := bs.newModel("cdx")
+	document := bs.documentInfo[0].Document
+
+	var purl string
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		if purl = string(node.Purl()); purl != "" {
+			break
+		}
+	}
+
+	bs.Require().NotEmpty(purl)
+
+	bs.press(model, "enter", "/")
+
+	view := bs.press(model, strings.Split(purl, "")...)
+	bs.Contains(view, "1/1")
+
+	view = bs.press(model, "enter", "enter")
+	bs.Contains(view, "Purl  "+purl)
+	bs.Contains(view, "ID  ")
+
+	// Back out to the document list.
+	bs.press(model, "esc", "esc", "esc")
+	bs.Contains(model.View(), "1/1")
+	bs.NotContains(model.View(), " › ")
+}
+
+func (bs *browseSuite) TestLinks() {
+	model := bs.newModel()
+	spdxID := bs.documentInfo[1].Document.GetMetadata().GetId()
+
+	view := bs.press(model, "enter", "l")
+	bs.Contains(view, "Links")
+	bs.Contains(view, "> → "+spdxID+" (spdx)")
+
+	view = bs.press(model, "enter")
+	bs.Contains(view, "Links › "+spdxID+" (spdx)")
+
+	view = bs.press(model, "l")
+	bs.Contains(view, "> ← "+bs.documentInfo[0].Document.GetMetadata().GetId()+" (cdx)")
+}
+
+func TestBrowseSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(browseSuite))
+}
@@ -0,0 +1,135 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/browse/list.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package browse
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type (
+	// item is a selectable row of a screen. Items with an open function can be drilled into.
+	item struct {
+		open        func() (*screen, error)
+		title       string
+		description string
+		search      string
+	}
+
+	// screen is a titled, searchable list of items.
+	screen struct {
+		links   func() (*screen, error)
+		title   string
+		query   string
+		items   []item
+		visible []int
+		cursor  int
+		offset  int
+	}
+)
+
+func newItem(title, description string, open func() (*screen, error), searchFields ...string) item {
+	return item{
+		title:       title,
+		description: description,
+		open:        open,
+		search:      strings.ToLower(strings.Join(searchFields, "\x00")),
+	}
+}
+
+func newScreen(title string, items []item) *screen {
+	s := &screen{title: title, items: items}
+	s.filter("")
+
+	return s
+}
+
+// filter shows only items whose search fields contain query, ignoring case.
+func (s *screen) filter(query string) {
+	s.query = query
+	s.visible = s.visible[:0]
+	query = strings.ToLower(query)
+
+	for idx := range s.items {
+		if query == "" || strings.Contains(s.items[idx].search, query) {
+			s.visible = append(s.visible, idx)
+		}
+	}
+
+	s.cursor, s.offset = 0, 0
+}
+
+func (s *screen) selected() *item {
+	if len(s.visible) == 0 {
+		return nil
+	}
+
+	return &s.items[s.visible[s.cursor]]
+}
+
+// move moves the cursor by delta rows, keeping it within the visible items and scrolling
+// the window of height rows to follow it.
+func (s *screen) move(delta, height int) {
+	s.cursor = max(0, min(len(s.visible)-1, s.cursor+delta))
+
+	switch {
+	case s.cursor < s.offset:
+		s.offset = s.cursor
+	case s.cursor >= s.offset+height:
+		s.offset = s.cursor - height + 1
+	}
+}
+
+func (s *screen) render(height, width int, styles *styles) string {
+	if len(s.visible) == 0 {
+		return styles.faint.Render("  No matching items")
+	}
+
+	rows := []string{}
+
+	for idx := s.offset; idx < len(s.visible) && idx < s.offset+height; idx++ {
+		current := s.items[s.visible[idx]]
+		row := current.title
+
+		if current.description != "" {
+			row += "  " + styles.faint.Render(current.description)
+		}
+
+		row = lipgloss.NewStyle().MaxWidth(max(0, width-2)).Render(row)
+
+		if idx == s.cursor {
+			rows = append(rows, styles.cursor.Render("> ")+styles.selected.Render(row))
+		} else {
+			rows = append(rows, "  "+row)
+		}
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+func (s *screen) position() string {
+	if len(s.visible) == 0 {
+		return fmt.Sprintf("0/%d", len(s.items))
+	}
+
+	return fmt.Sprintf("%d/%d", s.cursor+1, len(s.visible))
+}
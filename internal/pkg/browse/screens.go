@@ -0,0 +1,235 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/browse/screens.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package browse
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/sliceutil"
+)
+
+func (m *Model) documentsScreen(documents []*sbom.Document) *screen {
+	items := []item{}
+
+	for _, document := range documents {
+		id := document.GetMetadata().GetId()
+		alias := m.backend.GetDocumentAlias(id)
+
+		tags, err := m.backend.GetDocumentTags(id)
+		if err != nil {
+			m.opts.Logger.Debug("failed to get document tags", "id", id, "err", err)
+		}
+
+		description := []string{}
+
+		for _, value := range []string{alias, document.GetMetadata().GetName()} {
+			if value != "" {
+				description = append(description, value)
+			}
+		}
+
+		description = append(description, fmt.Sprintf("%d nodes", len(document.GetNodeList().GetNodes())))
+
+		items = append(items, newItem(id, strings.Join(description, " · "),
+			func() (*screen, error) { return m.nodesScreen(document), nil },
+			append([]string{id, alias, document.GetMetadata().GetName()}, tags...)...,
+		))
+	}
+
+	return newScreen("Documents", items)
+}
+
+func (m *Model) nodesScreen(document *sbom.Document) *screen {
+	id := document.GetMetadata().GetId()
+	items := []item{}
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		items = append(items, newItem(nodeLabel(node), string(node.Purl()),
+			func() (*screen, error) { return m.nodeScreen(document, node) },
+			node.GetName(), string(node.Purl()),
+		))
+	}
+
+	nodes := newScreen(documentLabel(id, m.backend.GetDocumentAlias(id)), items)
+	nodes.links = func() (*screen, error) { return m.linksScreen(id) }
+
+	return nodes
+}
+
+// nodeScreen lists the properties of a node, followed by its edges and outgoing links,
+// each of which can be followed.
+func (m *Model) nodeScreen(document *sbom.Document, node *sbom.Node) (*screen, error) {
+	items := []item{}
+
+	for _, property := range nodeProperties(node) {
+		items = append(items, newItem(property[0], property[1], nil, property[0], property[1]))
+	}
+
+	nodeList := document.GetNodeList()
+
+	for _, edge := range nodeList.GetEdges() {
+		switch {
+		case edge.GetFrom() == node.GetId():
+			for _, to := range edge.GetTo() {
+				items = append(items, m.edgeItem(document, edge.GetType().String()+" →", nodeList.GetNodeByID(to), to))
+			}
+		case slices.Contains(edge.GetTo(), node.GetId()):
+			items = append(items,
+				m.edgeItem(document, "← "+edge.GetType().String(), nodeList.GetNodeByID(edge.GetFrom()), edge.GetFrom()))
+		}
+	}
+
+	annotations, err := m.backend.GetNodeAnnotations(node.GetId(), db.LinkToAnnotation)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	for _, annotation := range annotations {
+		items = append(items, m.documentLinkItem("link →", annotation.Value))
+	}
+
+	return newScreen(nodeLabel(node), items), nil
+}
+
+// linksScreen lists the outgoing and incoming links of a document, resolved the same way as `link list`.
+func (m *Model) linksScreen(id string) (*screen, error) {
+	opts := &options.LinkOptions{
+		Options: m.opts.Options,
+		Links: []options.Link{{
+			From: options.LinkTarget{ID: id, Alias: m.backend.GetDocumentAlias(id), Type: options.LinkTargetTypeDocument},
+		}},
+	}
+
+	incoming, err := link.ListLinks(m.backend, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	items := sliceutil.Extract(opts.Links[0].To, func(target options.LinkTarget) item {
+		return m.documentLinkItem("→", target.ID)
+	})
+
+	for _, source := range incoming {
+		if source.Type == options.LinkTargetTypeDocument {
+			items = append(items, m.documentLinkItem("←", source.ID))
+		} else {
+			items = append(items, newItem("← node "+source.ID, "", nil, source.ID))
+		}
+	}
+
+	return newScreen("Links", items), nil
+}
+
+func (m *Model) edgeItem(document *sbom.Document, direction string, node *sbom.Node, id string) item {
+	if node == nil {
+		return newItem(direction+" "+id, "", nil, id)
+	}
+
+	return newItem(direction+" "+nodeLabel(node), string(node.Purl()),
+		func() (*screen, error) { return m.nodeScreen(document, node) },
+		node.GetName(), string(node.Purl()),
+	)
+}
+
+func (m *Model) documentLinkItem(direction, id string) item {
+	alias := m.backend.GetDocumentAlias(id)
+
+	return newItem(direction+" "+documentLabel(id, alias), "", func() (*screen, error) {
+		document, err := m.backend.GetDocumentByIDOrAlias(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		if document == nil {
+			return nil, fmt.Errorf("%w: %s", errDocumentNotFound, id)
+		}
+
+		return m.nodesScreen(document), nil
+	}, id, alias)
+}
+
+func nodeProperties(node *sbom.Node) [][2]string {
+	properties := [][2]string{
+		{"ID", node.GetId()},
+		{"Type", node.GetType().String()},
+		{"Name", node.GetName()},
+		{"Version", node.GetVersion()},
+		{"Purl", string(node.Purl())},
+		{"File name", node.GetFileName()},
+		{"Summary", node.GetSummary()},
+		{"Description", node.GetDescription()},
+		{"Licenses", strings.Join(node.GetLicenses(), ", ")},
+		{"License concluded", node.GetLicenseConcluded()},
+		{"Copyright", node.GetCopyright()},
+		{"Suppliers", strings.Join(sliceutil.Extract(node.GetSuppliers(), (*sbom.Person).GetName), ", ")},
+		{"Originators", strings.Join(sliceutil.Extract(node.GetOriginators(), (*sbom.Person).GetName), ", ")},
+		{"Home URL", node.GetUrlHome()},
+		{"Download URL", node.GetUrlDownload()},
+		{"Source info", node.GetSourceInfo()},
+		{"Comment", node.GetComment()},
+	}
+
+	for _, purpose := range node.GetPrimaryPurpose() {
+		properties = append(properties, [2]string{"Primary purpose", purpose.String()})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(node.GetIdentifiers())) {
+		if sbom.SoftwareIdentifierType(key) != sbom.SoftwareIdentifierType_PURL {
+			properties = append(properties, [2]string{sbom.SoftwareIdentifierType(key).String(), node.GetIdentifiers()[key]})
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(node.GetHashes())) {
+		properties = append(properties, [2]string{sbom.HashAlgorithm(key).String(), node.GetHashes()[key]})
+	}
+
+	for _, ref := range node.GetExternalReferences() {
+		properties = append(properties, [2]string{"Reference " + strings.ToLower(ref.GetType().String()), ref.GetUrl()})
+	}
+
+	return sliceutil.Filter(properties, func(property [2]string) bool { return property[1] != "" })
+}
+
+func nodeLabel(node *sbom.Node) string {
+	switch {
+	case node.GetName() == "":
+		return node.GetId()
+	case node.GetVersion() == "":
+		return node.GetName()
+	default:
+		return node.GetName() + "@" + node.GetVersion()
+	}
+}
+
+func documentLabel(id, alias string) string {
+	if alias == "" || alias == id {
+		return id
+	}
+
+	return fmt.Sprintf("%s (%s)", id, alias)
+}
@@ -40,6 +40,24 @@ type (
 		PrepareFetch(url *netutil.URL, auth *netutil.BasicAuth, opts *options.Options) error
 	}
 
+	// SignatureFetcher is implemented by fetchers that can discover signatures published alongside the SBOMs
+	// they fetch. FetchSignatures is called after Fetch with the same URL.
+	SignatureFetcher interface {
+		Fetcher
+		FetchSignatures(fetchURL string, opts *options.FetchOptions) ([]*Signature, error)
+	}
+
+	// Signature is a detached signature or Sigstore bundle discovered alongside a fetched SBOM, with the
+	// certificate or public key published with it, if any. Payload holds the signed bytes when they are not
+	// the SBOM itself, such as the manifest of the OCI image containing it.
+	Signature struct {
+		Source      string
+		Payload     []byte
+		Signature   []byte
+		Bundle      []byte
+		Certificate []byte
+	}
+
 	Pusher interface {
 		Client
 		AddFile(pushURL, id string, opts *options.PushOptions) error
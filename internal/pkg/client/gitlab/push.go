@@ -28,9 +28,9 @@ import (
 
 	gitlab "gitlab.com/gitlab-org/api/client-go"
 
+	bomctlclient "github.com/bomctl/bomctl/internal/pkg/client"
 	"github.com/bomctl/bomctl/internal/pkg/db"
 	"github.com/bomctl/bomctl/internal/pkg/options"
-	"github.com/bomctl/bomctl/internal/pkg/outpututil"
 )
 
 type stringWriter struct {
@@ -116,7 +116,7 @@ func (client *Client) AddFile(_pushURL, id string, opts *options.PushOptions) er
 	}
 
 	sbomWriter := &stringWriter{&strings.Builder{}}
-	if err := outpututil.WriteStream(sbom, opts.Format, opts.Options, sbomWriter); err != nil {
+	if err := bomctlclient.WriteDocument(sbom, opts, sbomWriter); err != nil {
 		return fmt.Errorf("failed to serialize SBOM %s: %w", id, err)
 	}
 
@@ -99,6 +99,82 @@ func (hfs *httpFetchSuite) TestClient_Fetch() {
 	}
 }
 
+func (hfs *httpFetchSuite) TestClient_FetchSignatures() {
+	files := map[string]string{
+		"/signed.json":         "{}",
+		"/signed.json.sig":     "c2lnbmF0dXJl",
+		"/signed.json.bundle":  `{"messageSignature":{"signature":"c2lnbmF0dXJl"}}`,
+		"/signed.json.pem":     "-----BEGIN PUBLIC KEY-----",
+		"/bundled.json":        "{}",
+		"/bundled.json.bundle": `{"messageSignature":{"signature":"c2lnbmF0dXJl"}}`,
+		"/unsigned.json":       "{}",
+		"/broken.json":         "{}",
+		"/broken.json.bundle":  `{"messageSignature":{"signature":"c2lnbmF0dXJl"}}`,
+	}
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		// Drop the connection without a response, as a failing server would.
+		if r.URL.Path == "/broken.json.sig" {
+			conn, _, err := nethttp.NewResponseController(w).Hijack()
+			hfs.Require().NoError(err)
+			hfs.Require().NoError(conn.Close())
+
+			return
+		}
+
+		data, ok := files[r.URL.Path]
+		if !ok {
+			nethttp.NotFound(w, r)
+
+			return
+		}
+
+		fmt.Fprint(w, data)
+	}))
+	defer server.Close()
+
+	for _, data := range []struct {
+		name     string
+		path     string
+		expected []string
+	}{
+		{name: "signature and bundle", path: "/signed.json", expected: []string{".sig", ".bundle"}},
+		{name: "bundle only", path: "/bundled.json", expected: []string{".bundle"}},
+		{name: "unsigned", path: "/unsigned.json", expected: []string{}},
+		{name: "failed signature request", path: "/broken.json", expected: []string{".bundle"}},
+	} {
+		hfs.Run(data.name, func() {
+			fetchURL := server.URL + data.path
+			opts := &options.FetchOptions{Options: hfs.Options}
+
+			signatures, err := hfs.FetchSignatures(fetchURL, opts)
+			hfs.Require().NoError(err)
+			hfs.Require().Len(signatures, len(data.expected))
+
+			var certificate []byte
+			if pem, ok := files[data.path+http.CertificateSuffix]; ok {
+				certificate = []byte(pem)
+			}
+
+			for idx, suffix := range data.expected {
+				signature := signatures[idx]
+
+				hfs.Equal(fetchURL+suffix, signature.Source)
+				hfs.Equal(certificate, signature.Certificate)
+				hfs.Nil(signature.Payload)
+
+				if suffix == http.BundleSuffix {
+					hfs.Equal([]byte(files[data.path+suffix]), signature.Bundle)
+					hfs.Nil(signature.Signature)
+				} else {
+					hfs.Equal([]byte(files[data.path+suffix]), signature.Signature)
+					hfs.Nil(signature.Bundle)
+				}
+			}
+		})
+	}
+}
+
 func TestHTTPFetchSuite(t *testing.T) {
 	t.Parallel()
 	suite.Run(t, new(httpFetchSuite))
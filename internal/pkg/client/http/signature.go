@@ -0,0 +1,134 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/client/http/signature.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	bomctlclient "github.com/bomctl/bomctl/internal/pkg/client"
+	"github.com/bomctl/bomctl/internal/pkg/netutil"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+const (
+	BundleSuffix      = ".bundle"
+	CertificateSuffix = ".pem"
+	SignatureSuffix   = ".sig"
+)
+
+// FetchSignatures looks for a detached signature and a Sigstore bundle published next to the SBOM at
+// fetchURL, along with the certificate or public key of the signer, as written by cosign sign-blob.
+func (client *Client) FetchSignatures(fetchURL string, opts *options.FetchOptions) ([]*bomctlclient.Signature, error) {
+	certificate, err := client.fetchSibling(fetchURL, CertificateSuffix, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Credentials in the URL are left out of the reported signature sources.
+	source := client.Parse(fetchURL)
+	source.Username, source.Password = "", ""
+
+	signatures := []*bomctlclient.Signature{}
+
+	for _, suffix := range []string{SignatureSuffix, BundleSuffix} {
+		data, err := client.fetchSibling(fetchURL, suffix, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if data == nil {
+			continue
+		}
+
+		signature := &bomctlclient.Signature{Source: siblingURL(source, suffix), Certificate: certificate}
+
+		if suffix == BundleSuffix {
+			signature.Bundle = data
+		} else {
+			signature.Signature = data
+		}
+
+		signatures = append(signatures, signature)
+	}
+
+	return signatures, nil
+}
+
+// fetchSibling returns the content of the file with the given suffix appended to the path of fetchURL,
+// or nil if there is no such file or it could not be fetched.
+func (client *Client) fetchSibling(fetchURL, suffix string, opts *options.FetchOptions) ([]byte, error) {
+	url := client.Parse(fetchURL)
+	auth := netutil.NewBasicAuth(url.Username, url.Password)
+
+	if opts.UseNetRC {
+		if err := auth.UseNetRC(url.Hostname); err != nil {
+			return nil, fmt.Errorf("failed to set auth: %w", err)
+		}
+	}
+
+	sibling := siblingURL(url, suffix)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, sibling, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating request to %s: %w", sibling, err)
+	}
+
+	auth.SetAuth(req)
+
+	if client.httpClient == nil {
+		client.httpClient = http.DefaultClient
+	}
+
+	// A signature file that cannot be fetched is treated as missing, so that the SBOM is still stored and
+	// reported as unsigned, or rejected if a signature is required.
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		opts.Logger.Warn("Failed to fetch signature file", "url", sibling, "err", err)
+
+		return nil, nil
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		opts.Logger.Debug("No signature file found", "url", sibling, "status", resp.StatusCode)
+
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		opts.Logger.Warn("Failed to read signature file", "url", sibling, "err", err)
+
+		return nil, nil
+	}
+
+	return data, nil
+}
+
+func siblingURL(url *netutil.URL, suffix string) string {
+	sibling := *url
+	sibling.Path += suffix
+
+	return sibling.String()
+}
@@ -42,6 +42,7 @@ type Client struct {
 	ctx         context.Context
 	store       *memory.Store
 	repo        *remote.Repository
+	manifest    ocispec.Descriptor
 	descriptors []ocispec.Descriptor
 }
 
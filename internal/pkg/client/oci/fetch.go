@@ -32,6 +32,15 @@ import (
 	"github.com/bomctl/bomctl/internal/pkg/options"
 )
 
+//nolint:gochecknoglobals
+var sbomMediaTypePrefixes = []string{
+	"application/vnd.cyclonedx",
+	"application/spdx",
+	"text/spdx",
+	"application/vnd.dsse.envelope",
+	"application/vnd.in-toto",
+}
+
 func (client *Client) PrepareFetch(url *netutil.URL, auth *netutil.BasicAuth, opts *options.Options) error {
 	return client.createRepository(url, auth, opts)
 }
@@ -53,6 +62,8 @@ func (client *Client) Fetch(fetchURL string, opts *options.FetchOptions) ([]byte
 
 	opts.Logger.Debug("Fetched manifest", "descriptor", descriptorJSON(&manifest))
 
+	client.manifest = manifest
+
 	sbomDescriptor, err := client.getSBOMDescriptor(&manifest)
 	if err != nil {
 		return nil, err
@@ -74,7 +85,8 @@ func (client *Client) getSBOMDescriptor(manifest *ocispec.Descriptor) (ocispec.D
 	sbomDigests := []string{}
 
 	for _, descriptor := range successors {
-		if slices.ContainsFunc([]string{"application/vnd.cyclonedx", "application/spdx", "text/spdx"},
+		// Attestations are included, as the SBOM in their predicate is extracted when the document is saved.
+		if slices.ContainsFunc(sbomMediaTypePrefixes,
 			func(s string) bool {
 				return strings.HasPrefix(descriptor.MediaType, s)
 			},
@@ -37,10 +37,11 @@ import (
 	"oras.land/oras-go/v2/content"
 	"oras.land/oras-go/v2/errdef"
 
+	"github.com/bomctl/bomctl/internal/pkg/attest"
+	bomctlclient "github.com/bomctl/bomctl/internal/pkg/client"
 	"github.com/bomctl/bomctl/internal/pkg/db"
 	"github.com/bomctl/bomctl/internal/pkg/netutil"
 	"github.com/bomctl/bomctl/internal/pkg/options"
-	"github.com/bomctl/bomctl/internal/pkg/outpututil"
 )
 
 const schemaVersion = 2
@@ -58,7 +59,7 @@ func (client *Client) AddFile(pushURL, id string, opts *options.PushOptions) err
 
 	buf := &ociClientWriter{bytes.NewBuffer([]byte{}), &io.PipeReader{}}
 
-	if err := outpututil.WriteStream(document, opts.Format, opts.Options, buf); err != nil {
+	if err := bomctlclient.WriteDocument(document, opts, buf); err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
@@ -225,6 +226,10 @@ func getDocument(id string, opts *options.Options) (*sbom.Document, error) {
 func getMediaType(opts *options.PushOptions) string {
 	opts.Logger.Debug("Getting mediaType for descriptor", "format", opts.Format)
 
+	if opts.Attest {
+		return attest.EnvelopeMediaType
+	}
+
 	// Only SPDX JSON encoding is currently supported by protobom, and the media type registered with the
 	// IANA has no version parameter (https://www.iana.org/assignments/media-types/application/spdx+json).
 	if opts.Format.Type() == formats.SPDXFORMAT {
@@ -0,0 +1,204 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/client/oci/signature.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package oci
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+
+	bomctlclient "github.com/bomctl/bomctl/internal/pkg/client"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+const (
+	BundleArtifactTypePrefix   = "application/vnd.dev.sigstore.bundle"
+	CertificateAnnotation      = "dev.sigstore.cosign/certificate"
+	SignatureAnnotation        = "dev.cosignproject.cosign/signature"
+	SignatureTagSuffix         = ".sig"
+	SimpleSigningMediaType     = "application/vnd.dev.cosign.simplesigning.v1+json"
+	simpleSigningManifestField = "docker-manifest-digest"
+)
+
+// simpleSigningPayload is the payload signed by cosign sign for container images.
+type simpleSigningPayload struct {
+	Critical struct {
+		Image map[string]string `json:"image"`
+	} `json:"critical"`
+}
+
+// FetchSignatures looks for signatures of the manifest fetched by Fetch, which covers the SBOM through the
+// digest of its layer. Signatures are discovered from the sha256-<digest>.sig tag written by cosign sign,
+// and from referrers holding Sigstore bundles.
+func (client *Client) FetchSignatures(_fetchURL string, opts *options.FetchOptions) ([]*bomctlclient.Signature, error) {
+	manifestData, err := content.FetchAll(client.ctx, client.store, client.manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+
+	signatures, err := client.fetchSignatureTag(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	referrers, err := client.fetchReferrerBundles(manifestData, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(signatures, referrers...), nil
+}
+
+func (client *Client) fetchSignatureTag(opts *options.FetchOptions) ([]*bomctlclient.Signature, error) {
+	tag := strings.Replace(client.manifest.Digest.String(), ":", "-", 1) + SignatureTagSuffix
+
+	descriptor, err := client.repo.Resolve(client.ctx, tag)
+	if errors.Is(err, errdef.ErrNotFound) {
+		opts.Logger.Debug("No signature tag found", "tag", tag)
+
+		return []*bomctlclient.Signature{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve signature tag %s: %w", tag, err)
+	}
+
+	manifest, err := client.fetchManifest(descriptor)
+	if err != nil {
+		return nil, err
+	}
+
+	signatures := []*bomctlclient.Signature{}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != SimpleSigningMediaType {
+			continue
+		}
+
+		payload, err := content.FetchAll(client.ctx, client.repo, layer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch signature payload: %w", err)
+		}
+
+		// Only signatures of the fetched manifest are relevant; the tag may hold signatures of other images.
+		if !client.signsManifest(payload) {
+			opts.Logger.Debug("Skipping signature of another manifest", "digest", layer.Digest)
+
+			continue
+		}
+
+		signature, err := base64.StdEncoding.DecodeString(layer.Annotations[SignatureAnnotation])
+		if err != nil {
+			opts.Logger.Debug("Skipping malformed signature", "digest", layer.Digest, "err", err)
+
+			continue
+		}
+
+		signatures = append(signatures, &bomctlclient.Signature{
+			Source:      client.reference(tag),
+			Payload:     payload,
+			Signature:   signature,
+			Certificate: []byte(layer.Annotations[CertificateAnnotation]),
+		})
+	}
+
+	return signatures, nil
+}
+
+func (client *Client) fetchReferrerBundles(
+	manifestData []byte, opts *options.FetchOptions,
+) ([]*bomctlclient.Signature, error) {
+	referrers := []ocispec.Descriptor{}
+
+	if err := client.repo.Referrers(client.ctx, client.manifest, "", func(descriptors []ocispec.Descriptor) error {
+		for _, descriptor := range descriptors {
+			if strings.HasPrefix(descriptor.ArtifactType, BundleArtifactTypePrefix) {
+				referrers = append(referrers, descriptor)
+			}
+		}
+
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list referrers: %w", err)
+	}
+
+	signatures := []*bomctlclient.Signature{}
+
+	for _, referrer := range referrers {
+		manifest, err := client.fetchManifest(referrer)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, layer := range manifest.Layers {
+			bundle, err := content.FetchAll(client.ctx, client.repo, layer)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch signature bundle: %w", err)
+			}
+
+			opts.Logger.Debug("Found signature bundle", "referrer", referrer.Digest)
+
+			signatures = append(signatures, &bomctlclient.Signature{
+				Source:  client.reference(referrer.Digest.String()),
+				Payload: manifestData,
+				Bundle:  bundle,
+			})
+		}
+	}
+
+	return signatures, nil
+}
+
+func (client *Client) fetchManifest(descriptor ocispec.Descriptor) (*ocispec.Manifest, error) {
+	data, err := content.FetchAll(client.ctx, client.repo, descriptor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch manifest %s: %w", descriptor.Digest, err)
+	}
+
+	manifest := &ocispec.Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", descriptor.Digest, err)
+	}
+
+	return manifest, nil
+}
+
+// reference returns the reference to a tag or digest in the repository of the client.
+func (client *Client) reference(tagOrDigest string) string {
+	reference := client.repo.Reference
+	reference.Reference = tagOrDigest
+
+	return reference.String()
+}
+
+func (client *Client) signsManifest(payload []byte) bool {
+	signed := &simpleSigningPayload{}
+	if err := json.Unmarshal(payload, signed); err != nil {
+		return false
+	}
+
+	return signed.Critical.Image[simpleSigningManifestField] == client.manifest.Digest.String()
+}
@@ -0,0 +1,130 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/client/oci/signature_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package oci_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+
+	"github.com/bomctl/bomctl/internal/pkg/client/oci"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+func (ocs *ociClientSuite) addBlob(mediaType string, data []byte, annotations map[string]string) ocispec.Descriptor {
+	desc := content.NewDescriptorFromBytes(mediaType, data)
+	desc.Annotations = annotations
+
+	ocs.ociTestRepository.blobs[string(desc.Digest)] = data
+	ocs.ociTestRepository.descriptors[string(desc.Digest)] = desc
+
+	return desc
+}
+
+func (ocs *ociClientSuite) addManifest(reference string, manifest any) []byte {
+	data, err := json.Marshal(manifest)
+	ocs.Require().NoError(err)
+
+	ocs.ociTestRepository.manifests[reference] = data
+	ocs.ociTestRepository.manifests[content.NewDescriptorFromBytes("", data).Digest.String()] = data
+
+	return data
+}
+
+func (ocs *ociClientSuite) TestClient_FetchSignatures() {
+	opts := &options.FetchOptions{Options: ocs.Options}
+	serverURL, err := neturl.Parse(ocs.Server.URL)
+	ocs.Require().NoError(err)
+
+	fetchURL := fmt.Sprintf("%s/%s:%s", serverURL.Host, repoName, "v1-single")
+
+	// Without any signatures published, none are found.
+	_, err = ocs.Fetch(fetchURL, opts)
+	ocs.Require().NoError(err)
+
+	signatures, err := ocs.FetchSignatures(fetchURL, opts)
+	ocs.Require().NoError(err)
+	ocs.Empty(signatures)
+
+	manifestDigest := content.NewDescriptorFromBytes("", ocs.ociTestRepository.manifests["v1-single"]).Digest
+
+	payload := func(manifestDigest string) []byte {
+		return []byte(fmt.Sprintf(`{"critical":{"image":{"docker-manifest-digest":%q}}}`, manifestDigest))
+	}
+
+	signature := []byte("signature")
+	signed := ocs.addBlob(oci.SimpleSigningMediaType, payload(manifestDigest.String()), map[string]string{
+		oci.SignatureAnnotation:   base64.StdEncoding.EncodeToString(signature),
+		oci.CertificateAnnotation: "certificate",
+	})
+	other := ocs.addBlob(oci.SimpleSigningMediaType, payload(testSHA), map[string]string{
+		oci.SignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+	})
+
+	tag := strings.Replace(manifestDigest.String(), ":", "-", 1)
+
+	ocs.addManifest(tag+oci.SignatureTagSuffix, ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    configDesc,
+		Layers:    []ocispec.Descriptor{signed, other},
+	})
+
+	bundle := []byte(`{"messageSignature":{"signature":"c2lnbmF0dXJl"}}`)
+	bundleManifest := ocs.addManifest("bundle", ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: oci.BundleArtifactTypePrefix + ".v0.3+json",
+		Config:       ocispec.DescriptorEmptyJSON,
+		Layers:       []ocispec.Descriptor{ocs.addBlob(oci.BundleArtifactTypePrefix+".v0.3+json", bundle, nil)},
+		Subject:      &ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: manifestDigest},
+	})
+
+	bundleDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageManifest, bundleManifest)
+	bundleDesc.ArtifactType = oci.BundleArtifactTypePrefix + ".v0.3+json"
+
+	// The test registry lacks the referrers API, so referrers are listed by the referrers tag schema.
+	ocs.addManifest(tag, ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{bundleDesc},
+	})
+
+	signatures, err = ocs.FetchSignatures(fetchURL, opts)
+	ocs.Require().NoError(err)
+	ocs.Require().Len(signatures, 2)
+
+	ocs.Equal(fmt.Sprintf("%s/%s:%s", serverURL.Host, repoName, tag+oci.SignatureTagSuffix), signatures[0].Source)
+	ocs.Equal(payload(manifestDigest.String()), signatures[0].Payload)
+	ocs.Equal(signature, signatures[0].Signature)
+	ocs.Equal([]byte("certificate"), signatures[0].Certificate)
+	ocs.Nil(signatures[0].Bundle)
+
+	ocs.Equal(fmt.Sprintf("%s/%s@%s", serverURL.Host, repoName, bundleDesc.Digest), signatures[1].Source)
+	ocs.Equal(ocs.ociTestRepository.manifests["v1-single"], signatures[1].Payload)
+	ocs.Equal(bundle, signatures[1].Bundle)
+	ocs.Nil(signatures[1].Signature)
+}
@@ -0,0 +1,63 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/client/push.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package client
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/attest"
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/outpututil"
+)
+
+// WriteDocument writes document to stream in the format of opts for pushing, as an attestation if requested.
+// References to documents pushed along with it are pointed to their destination URLs first, in which case
+// document is serialized rather than written as the original content stored with it.
+func WriteDocument(document *sbom.Document, opts *options.PushOptions, stream io.WriteCloser) error {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	rewritten, err := link.RewriteReferences(backend, document, opts.ReferenceURLs)
+	if err != nil {
+		return fmt.Errorf("rewriting references of %s: %w", document.GetMetadata().GetId(), err)
+	}
+
+	write := outpututil.WriteStream
+
+	if rewritten {
+		opts.Logger.Debug("Rewrote references to pushed documents", "id", document.GetMetadata().GetId())
+
+		write = func(document *sbom.Document, format formats.Format, _ *options.Options, stream io.WriteCloser) error {
+			return outpututil.SerializeStream(document, format, stream)
+		}
+	}
+
+	write = attest.StreamWriter(opts.Attest, opts.Signer, write)
+
+	return write(document, opts.Format, opts.Options, stream)
+}
@@ -27,6 +27,7 @@ import (
 	"fmt"
 	"regexp"
 	"slices"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/protobom/protobom/pkg/reader"
@@ -40,15 +41,20 @@ import (
 
 const (
 	AliasAnnotation           string = "bomctl_annotation_alias"
+	AttestationAnnotation     string = "bomctl_annotation_attestation"
 	BaseDocumentAnnotation    string = "bomctl_annotation_base_document"
 	LatestRevisionAnnotation  string = "bomctl_annotation_latest_revision"
 	LinkToAnnotation          string = "bomctl_annotation_link_to"
 	RevisedDocumentAnnotation string = "bomctl_annotation_revised_document"
+	SignatureAnnotation       string = "bomctl_annotation_signature"
+	SignatureStatusAnnotation string = "bomctl_annotation_signature_status"
 	SourceDataAnnotation      string = "bomctl_annotation_source_data"
 	SourceFormatAnnotation    string = "bomctl_annotation_source_format"
 	SourceHashAnnotation      string = "bomctl_annotation_source_hash"
 	SourceURLAnnotation       string = "bomctl_annotation_source_url"
+	StoredAtAnnotation        string = "bomctl_annotation_stored_at"
 	TagAnnotation             string = "bomctl_annotation_tag"
+	UnresolvedLinkAnnotation  string = "bomctl_annotation_unresolved_link"
 
 	DatabaseFile string = "bomctl.db"
 
@@ -74,6 +80,7 @@ var (
 	errMultipleDocuments         = errors.New("multiple documents matching ID")
 	errInvalidAlias              = errors.New("invalid alias provided")
 	errDuplicateAlias            = errors.New("alias already exists")
+	errDocumentNotFound          = errors.New("document not found")
 	ErrDocumentAliasExists       = errors.New("the document already has an alias")
 )
 
@@ -113,11 +120,6 @@ func NewBackend(opts ...Option) (*Backend, error) {
 
 // AddDocument adds the protobom document to the database and applies given backendOpts.
 func (backend *Backend) AddDocument(sbomData []byte, backendOpts ...Option) (*sbom.Document, error) {
-	// Clear annotations after adding document.
-	defer func() {
-		backend.Options.Annotations = nil
-	}()
-
 	sbomReader := reader.New()
 
 	document, err := sbomReader.ParseStream(bytes.NewReader(sbomData))
@@ -125,24 +127,48 @@ func (backend *Backend) AddDocument(sbomData []byte, backendOpts ...Option) (*sb
 		return nil, fmt.Errorf("parsing SBOM data: %w", err)
 	}
 
+	if err := backend.StoreDocument(document, backendOpts...); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// StoreDocument stores an in-memory protobom document in the database and applies given backendOpts.
+func (backend *Backend) StoreDocument(document *sbom.Document, backendOpts ...Option) error {
+	// Clear annotations after storing document.
+	defer func() {
+		backend.Options.Annotations = nil
+	}()
+
 	// Collect backend options by calling associated functions.
 	for _, fn := range backendOpts {
 		err := fn(backend)
 		if err != nil {
-			return nil, fmt.Errorf("handling document annotations: %w", err)
+			return fmt.Errorf("handling document annotations: %w", err)
 		}
 	}
 
+	// Record when the document was first stored, as its metadata often has no date.
+	storedAt, err := backend.GetDocumentUniqueAnnotation(document.GetMetadata().GetId(), StoredAtAnnotation)
+	if err != nil || storedAt == "" {
+		backend.Options.Annotations = append(backend.Options.Annotations, &ent.Annotation{
+			Name:     StoredAtAnnotation,
+			Value:    time.Now().UTC().Format(time.RFC3339),
+			IsUnique: true,
+		})
+	}
+
 	// Create StoreOptions with the populated backend options struct.
 	opts := &storage.StoreOptions{
 		BackendOptions: backend.Options,
 	}
 
 	if err := backend.Store(document, opts); err != nil {
-		return nil, fmt.Errorf("storing document %s: %w", document.GetMetadata().GetId(), err)
+		return fmt.Errorf("storing document %s: %w", document.GetMetadata().GetId(), err)
 	}
 
-	return document, nil
+	return nil
 }
 
 func (backend *Backend) FilterDocumentsByTag(documents []*sbom.Document, tags ...string) ([]*sbom.Document, error) {
@@ -238,6 +264,92 @@ func (backend *Backend) GetDocumentsByIDOrAlias(ids ...string) ([]*sbom.Document
 	return documents, nil
 }
 
+// GetDocumentRevisions returns every revision in the lineage of the document with the specified ID or alias,
+// starting with the original document and followed by the revisions of each document in order of ID. A document
+// revised more than once has more than one line of revisions, so the last of them is not necessarily the latest,
+// see GetLatestRevision.
+func (backend *Backend) GetDocumentRevisions(id string) ([]*sbom.Document, error) {
+	document, err := backend.GetDocumentByIDOrAlias(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document revisions: %w", err)
+	}
+
+	if document == nil {
+		return nil, fmt.Errorf("%w: %s", errDocumentNotFound, id)
+	}
+
+	parents, err := backend.revisionParents()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document revisions: %w", err)
+	}
+
+	children := map[string][]string{}
+	for revisedID, baseID := range parents {
+		children[baseID] = append(children[baseID], revisedID)
+	}
+
+	// Walk up to the original document.
+	originalID := document.GetMetadata().GetId()
+	visited := map[string]bool{originalID: true}
+
+	for baseID, ok := parents[originalID]; ok && !visited[baseID]; baseID, ok = parents[originalID] {
+		originalID = baseID
+		visited[baseID] = true
+	}
+
+	// Walk down from the original document, collecting each revision in order.
+	lineage := []string{}
+	for queue := []string{originalID}; len(queue) > 0; queue = queue[1:] {
+		if slices.Contains(lineage, queue[0]) {
+			continue
+		}
+
+		lineage = append(lineage, queue[0])
+
+		revisions := children[queue[0]]
+		slices.Sort(revisions)
+
+		queue = append(queue, revisions...)
+	}
+
+	revisions := []*sbom.Document{}
+
+	for _, revisionID := range lineage {
+		revision, err := backend.GetDocumentByID(revisionID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get document revisions: %w", err)
+		}
+
+		if revision != nil {
+			revisions = append(revisions, revision)
+		}
+	}
+
+	return revisions, nil
+}
+
+// GetLatestRevision returns the revision marked as the latest in the lineage of the document with the specified
+// ID or alias, or the last revision in the lineage if none is marked.
+func (backend *Backend) GetLatestRevision(id string) (*sbom.Document, error) {
+	revisions, err := backend.GetDocumentRevisions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range slices.Backward(revisions) {
+		latest, err := backend.GetDocumentAnnotations(revision.GetMetadata().GetId(), LatestRevisionAnnotation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get latest revision: %w", err)
+		}
+
+		if len(latest) > 0 {
+			return revision, nil
+		}
+	}
+
+	return revisions[len(revisions)-1], nil
+}
+
 func (backend *Backend) GetDocumentTags(id string) ([]string, error) {
 	annotations, err := backend.GetDocumentAnnotations(id, TagAnnotation)
 	if err != nil {
@@ -252,6 +364,22 @@ func (backend *Backend) GetDocumentTags(id string) ([]string, error) {
 	return tags, nil
 }
 
+// SetSourceData stores sbomData as the original content of the document with the specified ID, along with its
+// SHA-256 digest.
+func (backend *Backend) SetSourceData(documentID string, sbomData []byte) error {
+	hash := sha256.Sum256(sbomData)
+
+	if err := backend.SetDocumentUniqueAnnotation(documentID, SourceDataAnnotation, string(sbomData)); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if err := backend.SetDocumentUniqueAnnotation(documentID, SourceHashAnnotation, string(hash[:])); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
 func (backend *Backend) SetAlias(documentID, alias string, force bool) error { //revive:disable:flag-parameter
 	if err := backend.validateNewAlias(alias); err != nil {
 		return fmt.Errorf("failed to set alias: %w", err)
@@ -279,6 +407,35 @@ func (backend *Backend) SetAlias(documentID, alias string, force bool) error { /
 	return nil
 }
 
+// revisionParents maps the ID of each revised document to the ID of the document it was based on.
+func (backend *Backend) revisionParents() (map[string]string, error) {
+	metadata, err := backend.Ent().Metadata.Query().All(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("querying document metadata: %w", err)
+	}
+
+	// BaseDocumentAnnotation values reference the storage UUID of the base document, not its native ID.
+	nativeIDs := map[string]string{}
+	for _, data := range metadata {
+		nativeIDs[data.DocumentID.String()] = data.NativeID
+	}
+
+	parents := map[string]string{}
+
+	for _, data := range metadata {
+		baseUUID, err := backend.GetDocumentUniqueAnnotation(data.NativeID, BaseDocumentAnnotation)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		if baseID, ok := nativeIDs[baseUUID]; ok {
+			parents[data.NativeID] = baseID
+		}
+	}
+
+	return parents, nil
+}
+
 func (backend *Backend) validateNewAlias(alias string) (err error) {
 	if isEmptyOrWhitespace := regexp.MustCompile(`^\s*$`).MatchString(alias); isEmptyOrWhitespace {
 		return errInvalidAlias
@@ -297,6 +454,27 @@ func (backend *Backend) validateNewAlias(alias string) (err error) {
 }
 
 func WithSourceDocumentAnnotations(sbomData []byte) Option {
+	return func(backend *Backend) error {
+		if err := WithSourceDataAnnotations(sbomData)(backend); err != nil {
+			return err
+		}
+
+		backend.Options.Annotations = append(backend.Options.Annotations,
+			&ent.Annotation{
+				Name:     LatestRevisionAnnotation,
+				Value:    "true",
+				IsUnique: false,
+			},
+		)
+
+		return nil
+	}
+}
+
+// WithSourceDataAnnotations stores sbomData as the original content of a document, along with its SHA-256
+// digest. Revisions are stored with their serialization at the time they were made, so that they are exported
+// and signed as the same bytes every time.
+func WithSourceDataAnnotations(sbomData []byte) Option {
 	return func(backend *Backend) error {
 		hash := sha256.Sum256(sbomData)
 
@@ -311,17 +489,29 @@ func WithSourceDocumentAnnotations(sbomData []byte) Option {
 				Value:    string(hash[:]),
 				IsUnique: true,
 			},
-			&ent.Annotation{
-				Name:     LatestRevisionAnnotation,
-				Value:    "true",
-				IsUnique: false,
-			},
 		)
 
 		return nil
 	}
 }
 
+// WithAttestationAnnotation keeps the DSSE envelope or in-toto statement a document was extracted from.
+func WithAttestationAnnotation(envelope []byte) Option {
+	return func(backend *Backend) error {
+		if envelope == nil {
+			return nil
+		}
+
+		backend.Options.Annotations = append(backend.Options.Annotations, &ent.Annotation{
+			Name:     AttestationAnnotation,
+			Value:    string(envelope),
+			IsUnique: true,
+		})
+
+		return nil
+	}
+}
+
 func WithRevisedDocumentAnnotations(base *sbom.Document) Option {
 	return func(backend *Backend) error {
 		baseID := base.GetMetadata().GetId()
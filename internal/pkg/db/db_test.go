@@ -20,12 +20,16 @@
 package db_test
 
 import (
+	"bytes"
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/protobom/protobom/pkg/sbom"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/sliceutil"
 	"github.com/bomctl/bomctl/internal/testutil"
 )
 
@@ -156,6 +160,57 @@ func (dbs *dbSuite) TestBackend_GetDocumentsByIDOrAlias() {
 	dbs.Require().Equal(docs[1].GetMetadata().GetId(), dbs.documents[1].GetMetadata().GetId())
 }
 
+func (dbs *dbSuite) TestBackend_GetDocumentRevisions() {
+	baseDoc := dbs.documentInfo[0].Document
+	baseID := baseDoc.GetMetadata().GetId()
+
+	revisionIDs := []string{baseID}
+	previous := baseDoc
+
+	for idx, serial := range []string{
+		"urn:uuid:3e671687-395b-41f5-a30f-a58921a69b80",
+		"urn:uuid:3e671687-395b-41f5-a30f-a58921a69b81",
+	} {
+		// Both the document ID and node list must differ for the revision to be stored separately.
+		content := bytes.ReplaceAll(dbs.documentInfo[0].Content, []byte(baseID), []byte(serial))
+		content = bytes.ReplaceAll(content, []byte(`"9.0.14"`), []byte(fmt.Sprintf(`"9.0.%d"`, 15+idx)))
+
+		revision, err := dbs.Backend.AddDocument(content, db.WithRevisedDocumentAnnotations(previous))
+		dbs.Require().NoError(err)
+
+		revisionIDs = append(revisionIDs, revision.GetMetadata().GetId())
+		previous = revision
+	}
+
+	for _, id := range append(slices.Clone(revisionIDs), "cdx") {
+		revisions, err := dbs.Backend.GetDocumentRevisions(id)
+		dbs.Require().NoError(err)
+
+		dbs.Equal(revisionIDs, sliceutil.Extract(revisions, func(doc *sbom.Document) string {
+			return doc.GetMetadata().GetId()
+		}))
+	}
+
+	latest, err := dbs.Backend.GetLatestRevision(baseID)
+	dbs.Require().NoError(err)
+	dbs.Equal(revisionIDs[2], latest.GetMetadata().GetId())
+
+	// The latest revision is the one marked as such, not the last in the lineage.
+	dbs.Require().NoError(dbs.Backend.RemoveDocumentAnnotations(revisionIDs[2], db.LatestRevisionAnnotation))
+	dbs.Require().NoError(dbs.Backend.AddDocumentAnnotations(revisionIDs[1], db.LatestRevisionAnnotation, "true"))
+
+	latest, err = dbs.Backend.GetLatestRevision("cdx")
+	dbs.Require().NoError(err)
+	dbs.Equal(revisionIDs[1], latest.GetMetadata().GetId())
+
+	revisions, err := dbs.Backend.GetDocumentRevisions("spdx")
+	dbs.Require().NoError(err)
+	dbs.Len(revisions, 1)
+
+	_, err = dbs.Backend.GetDocumentRevisions("missing")
+	dbs.Require().EqualError(err, "document not found: missing")
+}
+
 func (dbs *dbSuite) TestBackend_GetDocumentTags() {
 	tags, err := dbs.Backend.GetDocumentTags(dbs.documents[0].GetMetadata().GetId())
 	dbs.Require().NoError(err)
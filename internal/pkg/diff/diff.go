@@ -0,0 +1,264 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/diff/diff.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+const comparedRevisions = 2
+
+var (
+	errDocumentNotFound = errors.New("document not found")
+	errInvalidRevision  = errors.New("invalid revision")
+)
+
+type (
+	// DocumentRef identifies one side of a diff.
+	DocumentRef struct {
+		ID    string `json:"id"`
+		Alias string `json:"alias,omitempty"`
+		Name  string `json:"name,omitempty"`
+	}
+
+	// NodeRef is a condensed representation of a node included in a diff report.
+	NodeRef struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Version string `json:"version,omitempty"`
+		Purl    string `json:"purl,omitempty"`
+	}
+
+	// FieldChange records a single node field whose value differs between documents.
+	FieldChange struct {
+		Field   string `json:"field"`
+		Base    string `json:"base"`
+		Revised string `json:"revised"`
+	}
+
+	// NodeChange pairs a matched base and revised node with the fields that differ between them.
+	NodeChange struct {
+		Base    NodeRef       `json:"base"`
+		Revised NodeRef       `json:"revised"`
+		Fields  []FieldChange `json:"fields"`
+	}
+
+	// Summary holds the node counts of a diff report.
+	Summary struct {
+		Added     int         `json:"added"`
+		Removed   int         `json:"removed"`
+		Changed   int         `json:"changed"`
+		Unchanged int         `json:"unchanged"`
+		Edges     EdgeSummary `json:"edges"`
+		Risks     RiskSummary `json:"risks"`
+	}
+
+	// Report is the result of comparing a base document to a revised document.
+	Report struct {
+		Base     DocumentRef  `json:"base"`
+		Revised  DocumentRef  `json:"revised"`
+		Added    []NodeRef    `json:"added"`
+		Removed  []NodeRef    `json:"removed"`
+		Changed  []NodeChange `json:"changed"`
+		Edges    EdgeReport   `json:"edges"`
+		Findings []Finding    `json:"findings"`
+		Summary  Summary      `json:"summary"`
+	}
+)
+
+// Diff compares the documents identified by baseID and revisedID, each of which may be a document ID or alias.
+func Diff(baseID, revisedID string, opts *options.DiffOptions) (*Report, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	opts.Logger.Debug("Comparing documents", "base", baseID, "revised", revisedID)
+
+	base, err := getDocument(backend, baseID)
+	if err != nil {
+		return nil, err
+	}
+
+	revised, err := getDocument(backend, revisedID)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareStored(backend, base, revised), nil
+}
+
+// DiffRevisions compares two revisions from the lineage of the document identified by id, which may be
+// any revision's document ID or alias. Revisions are numbered from 1, the original document. If opts
+// specifies a single revision, it is compared to the latest revision.
+func DiffRevisions(id string, opts *options.DiffOptions) (*Report, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	revisions, err := backend.GetDocumentRevisions(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	numbers := slices.Clone(opts.Revisions)
+	if len(numbers) == 1 {
+		latest, err := backend.GetLatestRevision(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		numbers = append(numbers, slices.IndexFunc(revisions, func(revision *sbom.Document) bool {
+			return revision.GetMetadata().GetId() == latest.GetMetadata().GetId()
+		})+1)
+	}
+
+	if len(numbers) != comparedRevisions {
+		return nil, fmt.Errorf("%w: expected one or two revisions, got %d", errInvalidRevision, len(opts.Revisions))
+	}
+
+	for _, number := range numbers {
+		if number < 1 || number > len(revisions) {
+			return nil, fmt.Errorf("%w: %d (document has %d revisions)", errInvalidRevision, number, len(revisions))
+		}
+	}
+
+	opts.Logger.Debug("Comparing revisions", "id", id, "base", numbers[0], "revised", numbers[1])
+
+	return compareStored(backend, revisions[numbers[0]-1], revisions[numbers[1]-1]), nil
+}
+
+// Compare generates a report of the nodes added, removed and changed from base to revised,
+// along with the changes to the dependency graph connecting them and any risks those changes pose.
+func Compare(base, revised *sbom.Document) *Report {
+	report := &Report{
+		Base:    newDocumentRef(base),
+		Revised: newDocumentRef(revised),
+		Added:   []NodeRef{},
+		Removed: []NodeRef{},
+		Changed: []NodeChange{},
+	}
+
+	matches := matchNodes(base.GetNodeList().GetNodes(), revised.GetNodeList().GetNodes())
+
+	for _, match := range matches.pairs {
+		fields := compareNodes(match.base, match.revised)
+		if len(fields) == 0 {
+			report.Summary.Unchanged++
+
+			continue
+		}
+
+		report.Changed = append(report.Changed, NodeChange{
+			Base:    newNodeRef(match.base),
+			Revised: newNodeRef(match.revised),
+			Fields:  fields,
+		})
+	}
+
+	for _, node := range matches.removed {
+		report.Removed = append(report.Removed, newNodeRef(node))
+	}
+
+	for _, node := range matches.added {
+		report.Added = append(report.Added, newNodeRef(node))
+	}
+
+	report.Summary.Added = len(report.Added)
+	report.Summary.Removed = len(report.Removed)
+	report.Summary.Changed = len(report.Changed)
+
+	report.Edges = compareEdges(base, revised, matches)
+	report.Summary.Edges = EdgeSummary{
+		Added:       len(report.Edges.Added),
+		Removed:     len(report.Edges.Removed),
+		TypeChanged: len(report.Edges.TypeChanged),
+		Moved:       len(report.Edges.Moved),
+		Promoted:    len(report.Edges.Promoted),
+	}
+
+	report.Findings = assessRisks(base.GetNodeList().GetNodes(), matches)
+
+	for _, finding := range report.Findings {
+		switch finding.Severity {
+		case SeverityHigh:
+			report.Summary.Risks.High++
+		case SeverityMedium:
+			report.Summary.Risks.Medium++
+		case SeverityLow, SeverityNone:
+			report.Summary.Risks.Low++
+		}
+	}
+
+	return report
+}
+
+// HasChanges reports whether any nodes or edges were added, removed or changed.
+func (r *Report) HasChanges() bool {
+	edges := r.Summary.Edges
+
+	return r.Summary.Added+r.Summary.Removed+r.Summary.Changed > 0 ||
+		edges.Added+edges.Removed+edges.TypeChanged+edges.Moved+edges.Promoted > 0
+}
+
+func compareStored(backend *db.Backend, base, revised *sbom.Document) *Report {
+	report := Compare(base, revised)
+	report.Base.Alias = backend.GetDocumentAlias(report.Base.ID)
+	report.Revised.Alias = backend.GetDocumentAlias(report.Revised.ID)
+
+	return report
+}
+
+func getDocument(backend *db.Backend, id string) (*sbom.Document, error) {
+	document, err := backend.GetDocumentByIDOrAlias(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if document == nil {
+		return nil, fmt.Errorf("%w: %s", errDocumentNotFound, id)
+	}
+
+	return document, nil
+}
+
+func newDocumentRef(document *sbom.Document) DocumentRef {
+	return DocumentRef{
+		ID:   document.GetMetadata().GetId(),
+		Name: document.GetMetadata().GetName(),
+	}
+}
+
+func newNodeRef(node *sbom.Node) NodeRef {
+	return NodeRef{
+		ID:      node.GetId(),
+		Name:    node.GetName(),
+		Version: node.GetVersion(),
+		Purl:    string(node.Purl()),
+	}
+}
@@ -0,0 +1,297 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/diff/diff_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package diff_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/diff"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+type diffSuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	documentInfo []testutil.DocumentInfo
+}
+
+func (ds *diffSuite) SetupSuite() {
+	var err error
+
+	ds.Backend, err = testutil.NewTestBackend()
+	ds.Require().NoError(err, "failed database backend creation")
+
+	ds.documentInfo, err = testutil.AddTestDocuments(ds.Backend)
+	ds.Require().NoError(err, "failed database backend setup")
+
+	ds.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, ds.Backend))
+}
+
+func (ds *diffSuite) TearDownSuite() {
+	ds.Backend.CloseClient()
+}
+
+func (ds *diffSuite) TestDiff() {
+	opts := &options.DiffOptions{Options: ds.Options}
+
+	report, err := diff.Diff("cdx", ds.documentInfo[0].Document.GetMetadata().GetId(), opts)
+	ds.Require().NoError(err)
+
+	ds.Equal("cdx", report.Base.Alias)
+	ds.Equal(report.Base.ID, report.Revised.ID)
+	ds.False(report.HasChanges())
+	ds.Equal(len(ds.documentInfo[0].Document.GetNodeList().GetNodes()), report.Summary.Unchanged)
+
+	_, err = diff.Diff("cdx", "missing", opts)
+	ds.Require().EqualError(err, "document not found: missing")
+}
+
+func (ds *diffSuite) TestDiffRevisions() {
+	content := bytes.ReplaceAll(ds.documentInfo[1].Content,
+		[]byte(`"versionInfo": "1.0.9-r3"`), []byte(`"versionInfo": "1.0.9-r2"`))
+	content = bytes.ReplaceAll(content,
+		[]byte("https://spdx.org/spdxdocs/apko/"), []byte("https://spdx.org/spdxdocs/apko2/"))
+
+	revision, err := ds.Backend.AddDocument(content, db.WithRevisedDocumentAnnotations(ds.documentInfo[1].Document))
+	ds.Require().NoError(err)
+
+	opts := &options.DiffOptions{Options: ds.Options, Revisions: []int{1}}
+
+	report, err := diff.DiffRevisions("spdx", opts)
+	ds.Require().NoError(err)
+
+	ds.Equal(ds.documentInfo[1].Document.GetMetadata().GetId(), report.Base.ID)
+	ds.Equal(revision.GetMetadata().GetId(), report.Revised.ID)
+	ds.Equal("spdx", report.Revised.Alias)
+	ds.Equal(2, report.Summary.Changed)
+	ds.Equal(2, report.Summary.Risks.High)
+
+	opts.Revisions = []int{2, 1}
+
+	report, err = diff.DiffRevisions(report.Base.ID, opts)
+	ds.Require().NoError(err)
+	ds.Equal(revision.GetMetadata().GetId(), report.Base.ID)
+	ds.Zero(report.Summary.Risks.High)
+
+	opts.Revisions = []int{3}
+
+	_, err = diff.DiffRevisions("spdx", opts)
+	ds.Require().EqualError(err, "invalid revision: 3 (document has 2 revisions)")
+
+	opts.Revisions = []int{1, 2, 3}
+
+	_, err = diff.DiffRevisions("spdx", opts)
+	ds.Require().EqualError(err, "invalid revision: expected one or two revisions, got 3")
+}
+
+func (ds *diffSuite) TestCompare() {
+	base := newDocument("base",
+		newNode("a", "alpha", "1.0.0", "pkg:generic/alpha@1.0.0"),
+		newNode("b", "bravo", "2.0.0", "pkg:generic/bravo@2.0.0"),
+		newNode("c", "charlie", "3.0.0", ""),
+		newNode("d", "delta", "4.0.0", ""),
+	)
+
+	revised := newDocument("revised",
+		newNode("a", "alpha", "1.0.0", "pkg:generic/alpha@1.0.0"),
+		newNode("b2", "bravo", "2.1.0", "pkg:generic/bravo@2.1.0"),
+		newNode("c2", "charlie", "3.0.0", ""),
+		newNode("e", "echo", "5.0.0", "pkg:generic/echo@5.0.0"),
+	)
+
+	revised.GetNodeList().GetNodes()[2].LicenseConcluded = "MIT"
+
+	report := diff.Compare(base, revised)
+
+	ds.True(report.HasChanges())
+	ds.Equal(diff.Summary{Added: 1, Removed: 1, Changed: 2, Unchanged: 1}, report.Summary)
+
+	ds.Require().Len(report.Added, 1)
+	ds.Equal("e", report.Added[0].ID)
+
+	ds.Require().Len(report.Removed, 1)
+	ds.Equal("d", report.Removed[0].ID)
+
+	ds.Require().Len(report.Changed, 2)
+	ds.Equal("b", report.Changed[0].Base.ID)
+	ds.Equal("b2", report.Changed[0].Revised.ID)
+	ds.Contains(report.Changed[0].Fields, diff.FieldChange{Field: "version", Base: "2.0.0", Revised: "2.1.0"})
+
+	ds.Equal("c", report.Changed[1].Base.ID)
+	ds.Equal([]diff.FieldChange{{Field: "license_concluded", Base: "", Revised: "MIT"}}, report.Changed[1].Fields)
+}
+
+func (ds *diffSuite) TestCompare_SameID() {
+	base := newDocument("base",
+		newNode("a", "requests", "2.31.0", "pkg:pypi/requests@2.31.0"),
+		newNode("b", "bravo", "1.0.0", ""),
+		newNode("c", "charlie", "1.0.0", ""),
+	)
+
+	revised := newDocument("revised",
+		newNode("a", "reqeusts", "2.31.0", "pkg:pypi/reqeusts@2.31.0"),
+		newNode("b", "bravo", "1.1.0", ""),
+		newNode("c", "charly", "1.0.0", ""),
+	)
+
+	report := diff.Compare(base, revised)
+
+	ds.Equal([]int{2, 2, 1}, []int{report.Summary.Added, report.Summary.Removed, report.Summary.Changed})
+	ds.NotZero(report.Summary.Risks.Medium)
+
+	ds.Require().Len(report.Changed, 1)
+	ds.Equal("b", report.Changed[0].Base.ID)
+	ds.Equal([]string{"reqeusts", "charly"}, []string{report.Added[0].Name, report.Added[1].Name})
+	ds.Equal([]string{"requests", "charlie"}, []string{report.Removed[0].Name, report.Removed[1].Name})
+}
+
+func (ds *diffSuite) TestCompare_Edges() {
+	base := newDocument("base",
+		newNode("r", "root", "1", ""), newNode("a", "alpha", "1", ""), newNode("b", "bravo", "1", ""),
+		newNode("c", "charlie", "1", ""), newNode("d", "delta", "1", ""), newNode("f", "foxtrot", "1", ""),
+	)
+
+	base.NodeList.RootElements = []string{"r"}
+	base.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_dependsOn, From: "r", To: []string{"a", "c"}},
+		{Type: sbom.Edge_dependsOn, From: "a", To: []string{"b", "f"}},
+		{Type: sbom.Edge_contains, From: "c", To: []string{"d"}},
+	}
+
+	revised := newDocument("revised",
+		newNode("r", "root", "1", ""), newNode("a", "alpha", "1", ""), newNode("b", "bravo", "1", ""),
+		newNode("c", "charlie", "1", ""), newNode("d", "delta", "1", ""), newNode("e", "echo", "1", ""),
+	)
+
+	revised.NodeList.RootElements = []string{"r"}
+	revised.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_dependsOn, From: "r", To: []string{"a", "b", "c", "e"}},
+		{Type: sbom.Edge_dependsOn, From: "c", To: []string{"d"}},
+	}
+
+	report := diff.Compare(base, revised)
+
+	ds.Equal(diff.EdgeSummary{Added: 1, Removed: 1, TypeChanged: 1, Moved: 1, Promoted: 1}, report.Summary.Edges)
+
+	ds.Equal("r", report.Edges.Added[0].From.ID)
+	ds.Equal("e", report.Edges.Added[0].To.ID)
+
+	ds.Equal("a", report.Edges.Removed[0].From.ID)
+	ds.Equal("f", report.Edges.Removed[0].To.ID)
+
+	ds.Equal("d", report.Edges.TypeChanged[0].To.ID)
+	ds.Equal("contains", report.Edges.TypeChanged[0].Base)
+	ds.Equal("dependsOn", report.Edges.TypeChanged[0].Revised)
+
+	ds.Equal("b", report.Edges.Moved[0].Node.ID)
+	ds.Equal("a", report.Edges.Moved[0].BaseParent.ID)
+	ds.Equal("r", report.Edges.Moved[0].RevisedParent.ID)
+
+	ds.Equal("b", report.Edges.Promoted[0].ID)
+}
+
+func (ds *diffSuite) TestCompare_Findings() {
+	base := newDocument("base",
+		newNode("lodash", "lodash", "4.17.21", "pkg:npm/lodash@4.17.21"),
+		newNode("left-pad", "left-pad", "1.3.0", "pkg:npm/left-pad@1.3.0"),
+		newNode("requests", "requests", "2.0.0", "pkg:pypi/requests@2.0.0"),
+	)
+
+	base.GetNodeList().GetNodes()[0].UrlDownload = "https://registry.npmjs.org/lodash/-/lodash-4.17.21.tgz"
+	base.GetNodeList().GetNodes()[1].UrlHome = "https://github.com/stevemao/left-pad"
+
+	revised := newDocument("revised",
+		newNode("lodash", "lodash", "4.17.20", "pkg:npm/lodash@4.17.20"),
+		newNode("left-pad", "left-pad", "1.3.0", "pkg:npm/left-pad@1.3.0"),
+		newNode("requests", "requests", "2.0.0", "pkg:pypi/requests@2.0.0"),
+		newNode("1odash", "1odash", "4.17.21", "pkg:npm/1odash@4.17.21"),
+		newNode("serde", "serde", "1.0.0", "pkg:cargo/serde@1.0.0"),
+		newNode("unknown", "unknown", "1.0.0", ""),
+	)
+
+	revised.GetNodeList().GetNodes()[0].UrlDownload = "https://registry.npmjs.org/lodash/-/lodash-4.17.20.tgz"
+	revised.GetNodeList().GetNodes()[1].UrlHome = "https://left-pad.example.com"
+	revised.GetNodeList().GetNodes()[3].UrlDownload = "https://1odash.example.com/1odash-4.17.21.tgz"
+
+	report := diff.Compare(base, revised)
+
+	ds.Equal(diff.RiskSummary{High: 3, Medium: 1, Low: 1}, report.Summary.Risks)
+
+	rules := map[string]diff.Severity{}
+	for _, finding := range report.Findings {
+		rules[finding.Node.ID+" "+finding.Rule] = finding.Severity
+	}
+
+	ds.Equal(map[string]diff.Severity{
+		"lodash downgrade":     diff.SeverityHigh,
+		"left-pad host-change": diff.SeverityHigh,
+		"1odash typosquat":     diff.SeverityHigh,
+		"serde new-origin":     diff.SeverityLow,
+		"unknown unidentified": diff.SeverityMedium,
+	}, rules)
+
+	ds.Len(report.FindingsAtOrAbove(diff.SeverityHigh), 3)
+	ds.Len(report.FindingsAtOrAbove(diff.SeverityMedium), 4)
+	ds.Len(report.FindingsAtOrAbove(diff.SeverityLow), 5)
+	ds.Empty(report.FindingsAtOrAbove(diff.SeverityNone))
+}
+
+func (ds *diffSuite) TestParseSeverity() {
+	for idx, name := range diff.SeverityNames() {
+		severity, err := diff.ParseSeverity(name)
+		ds.Require().NoError(err)
+		ds.Equal(diff.Severity(idx), severity)
+		ds.Equal(name, severity.String())
+	}
+
+	_, err := diff.ParseSeverity("critical")
+	ds.Require().EqualError(err, "unknown severity: critical")
+}
+
+func TestDiffSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(diffSuite))
+}
+
+func newDocument(id string, nodes ...*sbom.Node) *sbom.Document {
+	document := sbom.NewDocument()
+	document.Metadata.Id = id
+	document.NodeList.Nodes = nodes
+
+	return document
+}
+
+func newNode(id, name, version, purl string) *sbom.Node {
+	node := &sbom.Node{Id: id, Name: name, Version: version}
+
+	if purl != "" {
+		node.Identifiers = map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): purl}
+	}
+
+	return node
+}
@@ -0,0 +1,267 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/diff/edges.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package diff
+
+import (
+	"slices"
+
+	"github.com/protobom/protobom/pkg/sbom"
+)
+
+type (
+	// EdgeRef is a single relationship between two nodes included in a diff report.
+	EdgeRef struct {
+		From NodeRef `json:"from"`
+		To   NodeRef `json:"to"`
+		Type string  `json:"type"`
+	}
+
+	// EdgeTypeChange records a relationship present in both documents whose edge type differs.
+	EdgeTypeChange struct {
+		From    NodeRef `json:"from"`
+		To      NodeRef `json:"to"`
+		Base    string  `json:"base"`
+		Revised string  `json:"revised"`
+	}
+
+	// EdgeMove records a node that was re-parented, i.e. one of its incoming edges was
+	// removed and another was added from a different node.
+	EdgeMove struct {
+		Node          NodeRef `json:"node"`
+		BaseParent    NodeRef `json:"baseParent"`
+		RevisedParent NodeRef `json:"revisedParent"`
+		Type          string  `json:"type"`
+	}
+
+	// EdgeReport holds the dependency graph changes between two documents.
+	EdgeReport struct {
+		Added       []EdgeRef        `json:"added"`
+		Removed     []EdgeRef        `json:"removed"`
+		TypeChanged []EdgeTypeChange `json:"typeChanged"`
+		Moved       []EdgeMove       `json:"moved"`
+		// Promoted lists nodes that were transitive dependencies in the base document and are direct
+		// dependencies of a root node in the revised document.
+		Promoted []NodeRef `json:"promoted"`
+	}
+
+	// EdgeSummary holds the edge counts of a diff report.
+	EdgeSummary struct {
+		Added       int `json:"added"`
+		Removed     int `json:"removed"`
+		TypeChanged int `json:"typeChanged"`
+		Moved       int `json:"moved"`
+		Promoted    int `json:"promoted"`
+	}
+
+	edgeKey struct {
+		from     string
+		to       string
+		edgeType sbom.Edge_Type
+	}
+
+	// edgeGraph is the set of a document's edges, with node IDs translated to identity keys.
+	edgeGraph struct {
+		identities map[string]string
+		refs       map[string]NodeRef
+		keySet     map[edgeKey]struct{}
+		keys       []edgeKey
+		roots      []string
+	}
+)
+
+func compareEdges(base, revised *sbom.Document, matches *nodeMatches) EdgeReport {
+	baseIdentities, revisedIdentities := matches.identities()
+	baseGraph := newEdgeGraph(base.GetNodeList(), baseIdentities)
+	revisedGraph := newEdgeGraph(revised.GetNodeList(), revisedIdentities)
+
+	report := EdgeReport{
+		Added:       []EdgeRef{},
+		Removed:     []EdgeRef{},
+		TypeChanged: []EdgeTypeChange{},
+		Moved:       []EdgeMove{},
+		Promoted:    []NodeRef{},
+	}
+
+	removed := baseGraph.difference(revisedGraph)
+	added := revisedGraph.difference(baseGraph)
+
+	// Same relationship with a different edge type.
+	for idx := 0; idx < len(removed); idx++ {
+		match := slices.IndexFunc(added, func(key edgeKey) bool {
+			return key.from == removed[idx].from && key.to == removed[idx].to
+		})
+		if match == -1 {
+			continue
+		}
+
+		report.TypeChanged = append(report.TypeChanged, EdgeTypeChange{
+			From:    baseGraph.refs[removed[idx].from],
+			To:      baseGraph.refs[removed[idx].to],
+			Base:    removed[idx].edgeType.String(),
+			Revised: added[match].edgeType.String(),
+		})
+
+		added = slices.Delete(added, match, match+1)
+		removed = slices.Delete(removed, idx, idx+1)
+		idx--
+	}
+
+	// Same child node with a different parent.
+	for idx := 0; idx < len(added); idx++ {
+		match := slices.IndexFunc(removed, func(key edgeKey) bool { return key.to == added[idx].to })
+		if match == -1 {
+			continue
+		}
+
+		report.Moved = append(report.Moved, EdgeMove{
+			Node:          revisedGraph.refs[added[idx].to],
+			BaseParent:    baseGraph.refs[removed[match].from],
+			RevisedParent: revisedGraph.refs[added[idx].from],
+			Type:          added[idx].edgeType.String(),
+		})
+
+		removed = slices.Delete(removed, match, match+1)
+		added = slices.Delete(added, idx, idx+1)
+		idx--
+	}
+
+	for _, key := range removed {
+		report.Removed = append(report.Removed, baseGraph.edgeRef(key))
+	}
+
+	for _, key := range added {
+		report.Added = append(report.Added, revisedGraph.edgeRef(key))
+	}
+
+	baseDirect, baseTransitive := baseGraph.dependencyDepths()
+	revisedDirect, _ := revisedGraph.dependencyDepths()
+
+	for _, node := range revised.GetNodeList().GetNodes() {
+		identity := revisedIdentities[node.GetId()]
+
+		if _, ok := revisedDirect[identity]; !ok {
+			continue
+		}
+
+		_, wasDirect := baseDirect[identity]
+		_, wasTransitive := baseTransitive[identity]
+
+		if wasTransitive && !wasDirect {
+			report.Promoted = append(report.Promoted, revisedGraph.refs[identity])
+		}
+	}
+
+	return report
+}
+
+func newEdgeGraph(nodeList *sbom.NodeList, identities map[string]string) *edgeGraph {
+	graph := &edgeGraph{
+		identities: identities,
+		refs:       map[string]NodeRef{},
+		keySet:     map[edgeKey]struct{}{},
+	}
+
+	for _, node := range nodeList.GetNodes() {
+		graph.refs[graph.identity(node.GetId())] = newNodeRef(node)
+	}
+
+	for _, id := range nodeList.GetRootElements() {
+		graph.roots = append(graph.roots, graph.identity(id))
+	}
+
+	for _, edge := range nodeList.GetEdges() {
+		for _, to := range edge.GetTo() {
+			key := edgeKey{from: graph.identity(edge.GetFrom()), to: graph.identity(to), edgeType: edge.GetType()}
+
+			if _, ok := graph.keySet[key]; !ok {
+				graph.keySet[key] = struct{}{}
+				graph.keys = append(graph.keys, key)
+			}
+		}
+	}
+
+	return graph
+}
+
+// identity returns the identity key of a node ID, registering a placeholder reference
+// for edges that point to nodes missing from the node list.
+func (graph *edgeGraph) identity(id string) string {
+	identity, ok := graph.identities[id]
+	if !ok {
+		identity = "?" + id
+		graph.identities[id] = identity
+		graph.refs[identity] = NodeRef{ID: id}
+	}
+
+	return identity
+}
+
+// difference returns the edges of the graph that are not present in other.
+func (graph *edgeGraph) difference(other *edgeGraph) []edgeKey {
+	keys := []edgeKey{}
+
+	for _, key := range graph.keys {
+		if _, ok := other.keySet[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+func (graph *edgeGraph) edgeRef(key edgeKey) EdgeRef {
+	return EdgeRef{From: graph.refs[key.from], To: graph.refs[key.to], Type: key.edgeType.String()}
+}
+
+// dependencyDepths returns the identities of the direct dependencies of the graph's root nodes
+// and of all nodes reachable from the roots through more than one edge.
+func (graph *edgeGraph) dependencyDepths() (direct, transitive map[string]struct{}) {
+	direct, transitive = map[string]struct{}{}, map[string]struct{}{}
+	children := map[string][]string{}
+
+	for _, key := range graph.keys {
+		children[key.from] = append(children[key.from], key.to)
+	}
+
+	queue := []string{}
+
+	for _, root := range graph.roots {
+		for _, child := range children[root] {
+			direct[child] = struct{}{}
+			queue = append(queue, child)
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, child := range children[current] {
+			if _, seen := transitive[child]; seen {
+				continue
+			}
+
+			transitive[child] = struct{}{}
+			queue = append(queue, child)
+		}
+	}
+
+	return direct, transitive
+}
@@ -0,0 +1,112 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/diff/fields.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/sbom"
+)
+
+type nodeField struct {
+	value func(*sbom.Node) string
+	name  string
+}
+
+// Node fields compared when reporting changes, in the order they are reported.
+var nodeFields = []nodeField{ //nolint:gochecknoglobals
+	{name: "name", value: (*sbom.Node).GetName},
+	{name: "version", value: (*sbom.Node).GetVersion},
+	{name: "type", value: func(n *sbom.Node) string { return n.GetType().String() }},
+	{name: "identifiers", value: func(n *sbom.Node) string { return formatIdentifiers(n.GetIdentifiers()) }},
+	{name: "hashes", value: func(n *sbom.Node) string { return formatHashes(n.GetHashes()) }},
+	{name: "file_name", value: (*sbom.Node).GetFileName},
+	{name: "url_home", value: (*sbom.Node).GetUrlHome},
+	{name: "url_download", value: (*sbom.Node).GetUrlDownload},
+	{name: "licenses", value: func(n *sbom.Node) string { return formatSorted(n.GetLicenses()) }},
+	{name: "license_concluded", value: (*sbom.Node).GetLicenseConcluded},
+	{name: "copyright", value: (*sbom.Node).GetCopyright},
+	{name: "suppliers", value: func(n *sbom.Node) string { return formatPersons(n.GetSuppliers()) }},
+	{name: "originators", value: func(n *sbom.Node) string { return formatPersons(n.GetOriginators()) }},
+	{name: "external_references", value: func(n *sbom.Node) string {
+		return formatExternalReferences(n.GetExternalReferences())
+	}},
+}
+
+func compareNodes(base, revised *sbom.Node) []FieldChange {
+	changes := []FieldChange{}
+
+	for _, field := range nodeFields {
+		if baseValue, revisedValue := field.value(base), field.value(revised); baseValue != revisedValue {
+			changes = append(changes, FieldChange{Field: field.name, Base: baseValue, Revised: revisedValue})
+		}
+	}
+
+	return changes
+}
+
+func formatSorted(values []string) string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+
+	return strings.Join(sorted, ", ")
+}
+
+func formatHashes(hashes map[int32]string) string {
+	values := []string{}
+
+	for algorithm, value := range hashes {
+		values = append(values, fmt.Sprintf("%s:%s", sbom.HashAlgorithm(algorithm).String(), value))
+	}
+
+	return formatSorted(values)
+}
+
+func formatIdentifiers(identifiers map[int32]string) string {
+	values := []string{}
+
+	for identifierType, value := range identifiers {
+		values = append(values, fmt.Sprintf("%s:%s", sbom.SoftwareIdentifierType(identifierType).String(), value))
+	}
+
+	return formatSorted(values)
+}
+
+func formatPersons(persons []*sbom.Person) string {
+	values := []string{}
+
+	for _, person := range persons {
+		values = append(values, person.GetName())
+	}
+
+	return formatSorted(values)
+}
+
+func formatExternalReferences(refs []*sbom.ExternalReference) string {
+	values := []string{}
+
+	for _, ref := range refs {
+		values = append(values, fmt.Sprintf("%s:%s", ref.GetType().String(), ref.GetUrl()))
+	}
+
+	return formatSorted(values)
+}
@@ -0,0 +1,167 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/diff/match.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package diff
+
+import (
+	"strings"
+
+	"github.com/protobom/protobom/pkg/sbom"
+)
+
+type (
+	nodePair struct {
+		base    *sbom.Node
+		revised *sbom.Node
+	}
+
+	nodeMatches struct {
+		pairs   []nodePair
+		removed []*sbom.Node
+		added   []*sbom.Node
+	}
+
+	matchKeyFunc func(*sbom.Node) string
+)
+
+// Node identity keys, in order of precedence. Each pass only considers nodes left unmatched by the previous ones.
+var matchKeyFuncs = []matchKeyFunc{purlKey, packageKey, nameVersionKey, idKey} //nolint:gochecknoglobals
+
+// matchNodes pairs each base node with its counterpart in revised. Nodes are matched by purl first (exact,
+// then ignoring version, qualifiers and subpath), then by name and version. Remaining nodes fall back to
+// the node ID, provided their name and package are unchanged, so that a component renamed or replaced
+// under the same ID is reported as removed and added rather than changed.
+func matchNodes(base, revised []*sbom.Node) *nodeMatches {
+	matched := map[*sbom.Node]*sbom.Node{}
+	matchedRevised := map[*sbom.Node]struct{}{}
+
+	for _, keyFunc := range matchKeyFuncs {
+		candidates := map[string][]*sbom.Node{}
+
+		for _, node := range revised {
+			if _, ok := matchedRevised[node]; ok {
+				continue
+			}
+
+			if key := keyFunc(node); key != "" {
+				candidates[key] = append(candidates[key], node)
+			}
+		}
+
+		for _, node := range base {
+			if _, ok := matched[node]; ok {
+				continue
+			}
+
+			key := keyFunc(node)
+			if key == "" || len(candidates[key]) == 0 {
+				continue
+			}
+
+			matched[node] = candidates[key][0]
+			matchedRevised[candidates[key][0]] = struct{}{}
+			candidates[key] = candidates[key][1:]
+		}
+	}
+
+	matches := &nodeMatches{}
+
+	for _, node := range base {
+		if counterpart, ok := matched[node]; ok {
+			matches.pairs = append(matches.pairs, nodePair{base: node, revised: counterpart})
+		} else {
+			matches.removed = append(matches.removed, node)
+		}
+	}
+
+	for _, node := range revised {
+		if _, ok := matchedRevised[node]; !ok {
+			matches.added = append(matches.added, node)
+		}
+	}
+
+	return matches
+}
+
+// identities maps the node IDs of each document to a key shared by matched nodes, so that edges
+// from either document can be compared directly.
+func (matches *nodeMatches) identities() (base, revised map[string]string) {
+	base, revised = map[string]string{}, map[string]string{}
+
+	for _, pair := range matches.pairs {
+		base[pair.base.GetId()] = "=" + pair.base.GetId()
+		revised[pair.revised.GetId()] = "=" + pair.base.GetId()
+	}
+
+	for _, node := range matches.removed {
+		base[node.GetId()] = "-" + node.GetId()
+	}
+
+	for _, node := range matches.added {
+		revised[node.GetId()] = "+" + node.GetId()
+	}
+
+	return base, revised
+}
+
+// idKey returns the node's ID qualified by its name and package, so that nodes only match by ID when they
+// differ in version or other fields.
+func idKey(node *sbom.Node) string {
+	return strings.Join([]string{node.GetId(), node.GetName(), packageKey(node)}, "\x00")
+}
+
+func purlKey(node *sbom.Node) string {
+	return string(node.Purl())
+}
+
+// packageKey returns the node's purl stripped of its version, qualifiers and subpath.
+func packageKey(node *sbom.Node) string {
+	return stripPurl(string(node.Purl()))
+}
+
+func stripPurl(purl string) string {
+	if idx := strings.IndexAny(purl, "?#"); idx != -1 {
+		purl = purl[:idx]
+	}
+
+	if idx := strings.LastIndex(purl, "@"); idx > strings.LastIndex(purl, "/") {
+		purl = purl[:idx]
+	}
+
+	return purl
+}
+
+// purlParts splits a purl into its type, namespace and name.
+func purlParts(purl string) (purlType, namespace, name string) {
+	purlType, name, _ = strings.Cut(strings.TrimPrefix(stripPurl(purl), "pkg:"), "/")
+
+	if idx := strings.LastIndex(name, "/"); idx != -1 {
+		namespace, name = name[:idx], name[idx+1:]
+	}
+
+	return purlType, namespace, name
+}
+
+func nameVersionKey(node *sbom.Node) string {
+	if node.GetName() == "" {
+		return ""
+	}
+
+	return node.GetName() + "@" + node.GetVersion()
+}
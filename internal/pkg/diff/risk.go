@@ -0,0 +1,335 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/diff/risk.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package diff
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/versionutil"
+)
+
+// Severity ranks the risk of a finding.
+type Severity int
+
+const (
+	SeverityNone Severity = iota
+	SeverityLow
+	SeverityMedium
+	SeverityHigh
+)
+
+// Rules applied to the changes between two documents.
+const (
+	RuleDowngrade    = "downgrade"
+	RuleHostChange   = "host-change"
+	RuleNewOrigin    = "new-origin"
+	RuleTyposquat    = "typosquat"
+	RuleUnidentified = "unidentified"
+)
+
+const (
+	// Names of at least this length allow a greater edit distance to be considered a typosquat.
+	typosquatLongName    = 8
+	typosquatMaxDistance = 2
+)
+
+var (
+	errUnknownSeverity = errors.New("unknown severity")
+
+	severityNames = []string{"none", "low", "medium", "high"} //nolint:gochecknoglobals
+)
+
+type (
+	// Finding is a change classified as a potential supply chain risk.
+	Finding struct {
+		Node     NodeRef  `json:"node"`
+		Rule     string   `json:"rule"`
+		Message  string   `json:"message"`
+		Severity Severity `json:"severity"`
+	}
+
+	// RiskSummary holds the number of findings at each severity.
+	RiskSummary struct {
+		High   int `json:"high"`
+		Medium int `json:"medium"`
+		Low    int `json:"low"`
+	}
+)
+
+// SeverityNames returns the names of all severities, from lowest to highest.
+func SeverityNames() []string {
+	return slices.Clone(severityNames)
+}
+
+// ParseSeverity returns the Severity with the given name.
+func ParseSeverity(name string) (Severity, error) {
+	idx := slices.Index(severityNames, strings.ToLower(name))
+	if idx == -1 {
+		return SeverityNone, fmt.Errorf("%w: %s", errUnknownSeverity, name)
+	}
+
+	return Severity(idx), nil
+}
+
+func (s Severity) String() string {
+	if s < SeverityNone || int(s) >= len(severityNames) {
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+
+	return severityNames[s]
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	severity, err := ParseSeverity(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = severity
+
+	return nil
+}
+
+// FindingsAtOrAbove returns the report's findings with a severity of at least threshold.
+// A threshold of SeverityNone matches no findings.
+func (r *Report) FindingsAtOrAbove(threshold Severity) []Finding {
+	findings := []Finding{}
+
+	if threshold == SeverityNone {
+		return findings
+	}
+
+	for _, finding := range r.Findings {
+		if finding.Severity >= threshold {
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// assessRisks classifies changed and added nodes against known supply chain attack patterns:
+// version downgrades, download or repository host changes, names close to an existing
+// package (typosquats), and packages from origins not present in the base document.
+func assessRisks(base []*sbom.Node, matches *nodeMatches) []Finding {
+	findings := []Finding{}
+
+	for _, pair := range matches.pairs {
+		findings = append(findings, assessChangedNode(pair.base, pair.revised)...)
+	}
+
+	origins := map[string]struct{}{}
+
+	for _, node := range base {
+		if purl := node.Purl(); purl != "" {
+			purlType, namespace, _ := purlParts(string(purl))
+			origins[purlType+"/"+namespace] = struct{}{}
+		}
+	}
+
+	for _, node := range matches.added {
+		if finding := assessAddedNode(node, base, origins); finding != nil {
+			findings = append(findings, *finding)
+		}
+	}
+
+	return findings
+}
+
+func assessChangedNode(base, revised *sbom.Node) []Finding {
+	findings := []Finding{}
+
+	if versionutil.IsDowngrade(base.GetVersion(), revised.GetVersion()) {
+		findings = append(findings, Finding{
+			Node:     newNodeRef(revised),
+			Rule:     RuleDowngrade,
+			Severity: SeverityHigh,
+			Message:  fmt.Sprintf("version decreased from %s to %s", base.GetVersion(), revised.GetVersion()),
+		})
+	}
+
+	for _, field := range []struct {
+		hosts func(*sbom.Node) []string
+		name  string
+	}{
+		{name: "download location", hosts: func(n *sbom.Node) []string { return urlHosts(n.GetUrlDownload()) }},
+		{name: "home page", hosts: func(n *sbom.Node) []string { return urlHosts(n.GetUrlHome()) }},
+		{name: "repository URL", hosts: vcsHosts},
+	} {
+		baseHosts, revisedHosts := field.hosts(base), field.hosts(revised)
+
+		if len(baseHosts) == 0 || len(revisedHosts) == 0 || slices.Equal(baseHosts, revisedHosts) {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			Node:     newNodeRef(revised),
+			Rule:     RuleHostChange,
+			Severity: SeverityHigh,
+			Message: fmt.Sprintf("%s host changed from %s to %s",
+				field.name, strings.Join(baseHosts, ", "), strings.Join(revisedHosts, ", ")),
+		})
+	}
+
+	return findings
+}
+
+func assessAddedNode(node *sbom.Node, base []*sbom.Node, origins map[string]struct{}) *Finding {
+	if node.GetType() != sbom.Node_PACKAGE {
+		return nil
+	}
+
+	purl := string(node.Purl())
+	if purl == "" {
+		return &Finding{
+			Node:     newNodeRef(node),
+			Rule:     RuleUnidentified,
+			Severity: SeverityMedium,
+			Message:  "new package has no package URL",
+		}
+	}
+
+	if similar := findSimilarPackage(purl, base); similar != nil {
+		finding := &Finding{
+			Node:     newNodeRef(node),
+			Rule:     RuleTyposquat,
+			Severity: SeverityMedium,
+			Message:  fmt.Sprintf("name is similar to existing package %s", stripPurl(string(similar.Purl()))),
+		}
+
+		nodeHosts, similarHosts := packageHosts(node), packageHosts(similar)
+		if len(nodeHosts) > 0 && len(similarHosts) > 0 && !slices.ContainsFunc(nodeHosts, func(host string) bool {
+			return slices.Contains(similarHosts, host)
+		}) {
+			finding.Severity = SeverityHigh
+			finding.Message += fmt.Sprintf(" but points to %s instead of %s",
+				strings.Join(nodeHosts, ", "), strings.Join(similarHosts, ", "))
+		}
+
+		return finding
+	}
+
+	purlType, namespace, _ := purlParts(purl)
+	if _, ok := origins[purlType+"/"+namespace]; !ok {
+		return &Finding{
+			Node:     newNodeRef(node),
+			Rule:     RuleNewOrigin,
+			Severity: SeverityLow,
+			Message:  fmt.Sprintf("new package from previously unseen origin %s/%s", purlType, namespace),
+		}
+	}
+
+	return nil
+}
+
+// findSimilarPackage returns the first base node of the same purl type whose namespace
+// and name are within a small edit distance of those of the given purl.
+func findSimilarPackage(purl string, base []*sbom.Node) *sbom.Node {
+	purlType, namespace, name := purlParts(purl)
+	fullName := namespace + "/" + name
+
+	maxDistance := 1
+	if len(name) >= typosquatLongName {
+		maxDistance = typosquatMaxDistance
+	}
+
+	for _, node := range base {
+		baseType, baseNamespace, baseName := purlParts(string(node.Purl()))
+		if node.Purl() == "" || baseType != purlType {
+			continue
+		}
+
+		distance := levenshtein(fullName, baseNamespace+"/"+baseName)
+		if distance > 0 && distance <= maxDistance {
+			return node
+		}
+	}
+
+	return nil
+}
+
+func packageHosts(node *sbom.Node) []string {
+	hosts := append(urlHosts(node.GetUrlDownload()), urlHosts(node.GetUrlHome())...)
+	hosts = append(hosts, vcsHosts(node)...)
+
+	slices.Sort(hosts)
+
+	return slices.Compact(hosts)
+}
+
+func vcsHosts(node *sbom.Node) []string {
+	hosts := []string{}
+
+	for _, ref := range node.GetExternalReferences() {
+		if ref.GetType() == sbom.ExternalReference_VCS {
+			hosts = append(hosts, urlHosts(ref.GetUrl())...)
+		}
+	}
+
+	slices.Sort(hosts)
+
+	return slices.Compact(hosts)
+}
+
+func urlHosts(rawURL string) []string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" {
+		return []string{}
+	}
+
+	return []string{strings.ToLower(parsed.Hostname())}
+}
+
+func levenshtein(a, b string) int {
+	runesA, runesB := []rune(a), []rune(b)
+	previous := make([]int, len(runesB)+1)
+	current := make([]int, len(runesB)+1)
+
+	for idx := range previous {
+		previous[idx] = idx
+	}
+
+	for idxA := range runesA {
+		current[0] = idxA + 1
+
+		for idxB := range runesB {
+			cost := 1
+			if runesA[idxA] == runesB[idxB] {
+				cost = 0
+			}
+
+			current[idxB+1] = min(previous[idxB+1]+1, current[idxB]+1, previous[idxB]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(runesB)]
+}
@@ -0,0 +1,270 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/enrich/enrich.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package enrich
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/protobom/protobom/pkg/sbom"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/fetch"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/outpututil"
+	"github.com/bomctl/bomctl/internal/pkg/tea"
+)
+
+const (
+	// PropertyPrefix is the prefix of the names of node properties added by enrichment.
+	PropertyPrefix = "bomctl:tea:"
+
+	// ProductProperty holds the UUID of a TEA product matching the node's purl.
+	ProductProperty = PropertyPrefix + "product"
+
+	// ProductNameProperty holds the name of a TEA product matching the node's purl.
+	ProductNameProperty = PropertyPrefix + "product_name"
+
+	// ReleaseProperty holds the UUID of the TEA release matching the node's version.
+	ReleaseProperty = PropertyPrefix + "release"
+
+	// DocumentProperty holds the ID of a BOM artifact fetched into the cache.
+	DocumentProperty = PropertyPrefix + "document"
+
+	// ArtifactPropertyPrefix is followed by the lowercase artifact type, such as bom or vulnerabilities,
+	// in the names of properties holding the URLs of a release's artifacts.
+	ArtifactPropertyPrefix = PropertyPrefix + "artifact:"
+)
+
+var (
+	errCopyFailed       = errors.New("failed to copy document")
+	errDocumentNotFound = errors.New("document not found")
+	errNothingEnriched  = errors.New("no components of the document were found on the TEA server")
+)
+
+type match struct {
+	collection *tea.Collection
+	product    tea.Product
+	release    tea.Release
+}
+
+// Enrich looks up each node of the document with the specified ID or alias on the TEA server by its purl.
+// Nodes whose version matches a published release are given properties describing the product, release
+// and artifacts. BOM artifacts are fetched into the cache, recorded in the node properties and linked to
+// from the enriched document, which is stored as a new revision of the original. The original document
+// remains unchanged.
+func Enrich(sbomID string, opts *options.EnrichOptions) (*sbom.Document, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	client, err := tea.NewClient(opts.TEAURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	base, err := backend.GetDocumentByIDOrAlias(sbomID)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if base == nil {
+		return nil, fmt.Errorf("%w: %s", errDocumentNotFound, sbomID)
+	}
+
+	tags, err := backend.GetDocumentTags(base.GetMetadata().GetId())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	enriched, ok := proto.Clone(base).(*sbom.Document)
+	if !ok {
+		return nil, errCopyFailed
+	}
+
+	enrichedCount, fetchedIDs := 0, []string{}
+
+	for _, node := range enriched.GetNodeList().GetNodes() {
+		matches, err := lookup(client, node, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(matches) == 0 {
+			opts.Logger.Debug("No TEA release found", "node", node.GetId(), "purl", node.Purl())
+
+			continue
+		}
+
+		setProperties(node, matches)
+		fetchedIDs = append(fetchedIDs, fetchBOMs(node, matches, opts)...)
+
+		enrichedCount++
+	}
+
+	if enrichedCount == 0 {
+		return nil, errNothingEnriched
+	}
+
+	enriched.Metadata.Id = uuid.New().URN()
+	enriched.Metadata.Date = timestamppb.Now()
+
+	backend.Logger.Info("Storing enriched revision", "baseID", base.GetMetadata().GetId(),
+		"id", enriched.GetMetadata().GetId(), "enriched", enrichedCount)
+
+	sourceData, err := outpututil.Serialize(enriched)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if err := backend.StoreDocument(
+		enriched, db.WithRevisedDocumentAnnotations(base), db.WithSourceDataAnnotations(sourceData),
+	); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if err := backend.AddDocumentAnnotations(enriched.GetMetadata().GetId(), db.TagAnnotation, tags...); err != nil {
+		opts.Logger.Warn("Tag(s) could not be set.", "err", err)
+	}
+
+	if err := backend.AddDocumentAnnotations(
+		enriched.GetMetadata().GetId(), db.LinkToAnnotation, fetchedIDs...,
+	); err != nil {
+		return nil, fmt.Errorf("adding document links: %w", err)
+	}
+
+	return enriched, nil
+}
+
+// lookup returns the TEA releases matching the node's purl and version, along with their latest
+// artifact collections. Components of a matching product that the server has no releases for are skipped.
+func lookup(client *tea.Client, node *sbom.Node, opts *options.EnrichOptions) ([]match, error) {
+	ctx := opts.Context()
+
+	purl := string(node.Purl())
+	if purl == "" || node.GetVersion() == "" {
+		return nil, nil
+	}
+
+	products, err := client.SearchProducts(ctx, tea.IdentifierTypePURL, purl)
+	if err != nil && !errors.Is(err, tea.ErrNotFound) {
+		return nil, fmt.Errorf("looking up %s: %w", purl, err)
+	}
+
+	matches := []match{}
+
+	for _, product := range products {
+		for _, ref := range product.Components {
+			releases, err := client.ComponentReleases(ctx, ref.UUID)
+			if errors.Is(err, tea.ErrNotFound) {
+				opts.Logger.Warn("TEA component not found", "node", node.GetId(), "product", product.UUID,
+					"component", ref.UUID)
+
+				continue
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("looking up releases of %s: %w", purl, err)
+			}
+
+			idx := slices.IndexFunc(releases, func(release tea.Release) bool {
+				if ref.Release != "" {
+					return release.UUID == ref.Release
+				}
+
+				return release.Version == node.GetVersion()
+			})
+
+			if idx < 0 || releases[idx].Version != node.GetVersion() {
+				continue
+			}
+
+			collection, err := client.LatestCollection(ctx, releases[idx].UUID)
+			if err != nil {
+				return nil, fmt.Errorf("looking up artifacts of %s: %w", purl, err)
+			}
+
+			matches = append(matches, match{collection: collection, product: product, release: releases[idx]})
+		}
+	}
+
+	return matches, nil
+}
+
+// setProperties replaces the node's TEA properties with those describing the matches.
+func setProperties(node *sbom.Node, matches []match) {
+	node.Properties = slices.DeleteFunc(node.Properties, func(property *sbom.Property) bool {
+		return strings.HasPrefix(property.GetName(), PropertyPrefix)
+	})
+
+	for _, match := range matches {
+		node.Properties = append(node.Properties,
+			&sbom.Property{Name: ProductProperty, Data: match.product.UUID},
+			&sbom.Property{Name: ProductNameProperty, Data: match.product.Name},
+			&sbom.Property{Name: ReleaseProperty, Data: match.release.UUID},
+		)
+
+		for _, artifact := range match.collection.Artifacts {
+			for _, format := range artifact.Formats {
+				node.Properties = append(node.Properties, &sbom.Property{
+					Name: ArtifactPropertyPrefix + strings.ToLower(artifact.Type),
+					Data: format.URL,
+				})
+			}
+		}
+	}
+}
+
+// fetchBOMs fetches the BOM artifacts of the matches into the cache, records their document IDs in the
+// node's properties and returns them. Failures are logged, as the artifact URLs remain recorded.
+func fetchBOMs(node *sbom.Node, matches []match, opts *options.EnrichOptions) []string {
+	fetchOpts := &options.FetchOptions{Options: opts.Options, UseNetRC: opts.UseNetRC}
+	documentIDs := []string{}
+
+	for _, match := range matches {
+		for _, artifact := range match.collection.Artifacts {
+			if artifact.Type != tea.ArtifactTypeBOM || len(artifact.Formats) == 0 {
+				continue
+			}
+
+			document, err := fetch.Fetch(artifact.Formats[0].URL, fetchOpts)
+			if err != nil {
+				opts.Logger.Warn("BOM artifact could not be fetched", "node", node.GetId(), "err", err)
+
+				continue
+			}
+
+			node.Properties = append(node.Properties, &sbom.Property{
+				Name: DocumentProperty,
+				Data: document.GetMetadata().GetId(),
+			})
+
+			documentIDs = append(documentIDs, document.GetMetadata().GetId())
+		}
+	}
+
+	return documentIDs
+}
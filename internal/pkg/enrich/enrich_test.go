@@ -0,0 +1,143 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/enrich/enrich_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package enrich_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/enrich"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+const (
+	testPurl    = "pkg:npm/acme/component@1.0.0"
+	testVersion = "9.0.14"
+)
+
+type enrichSuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	*httptest.Server
+	documentInfo []testutil.DocumentInfo
+}
+
+func (es *enrichSuite) SetupSuite() {
+	var err error
+
+	// The second product of the component references a component the server has no record of, which is skipped.
+	es.Server = testutil.NewTEAServer(
+		testutil.TEARelease{Purl: testPurl, Version: testVersion},
+		testutil.TEARelease{Purl: testPurl, Version: testVersion, MissingComponent: true},
+	)
+
+	es.Backend, err = testutil.NewTestBackend()
+	es.Require().NoError(err, "failed database backend creation")
+
+	es.documentInfo, err = testutil.AddTestDocuments(es.Backend)
+	es.Require().NoError(err, "failed database backend setup")
+
+	es.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, es.Backend))
+}
+
+func (es *enrichSuite) TearDownSuite() {
+	es.Server.Close()
+	es.Backend.CloseClient()
+}
+
+func (es *enrichSuite) TestEnrich() {
+	base := es.documentInfo[0].Document
+	opts := &options.EnrichOptions{Options: es.Options, TEAURL: es.Server.URL + testutil.TEAPath}
+
+	enriched, err := enrich.Enrich("cdx", opts)
+	es.Require().NoError(err)
+
+	es.NotEqual(base.GetMetadata().GetId(), enriched.GetMetadata().GetId())
+
+	stored, err := es.Backend.GetDocumentByID(enriched.GetMetadata().GetId())
+	es.Require().NoError(err)
+	es.Require().NotNil(stored)
+
+	var node *sbom.Node
+
+	for _, candidate := range stored.GetNodeList().GetNodes() {
+		if string(candidate.Purl()) == testPurl {
+			node = candidate
+		}
+	}
+
+	es.Require().NotNil(node)
+
+	properties := map[string]string{}
+	for _, property := range node.GetProperties() {
+		properties[property.GetName()] = property.GetData()
+	}
+
+	es.NotEmpty(properties[enrich.ProductProperty])
+	es.NotEmpty(properties[enrich.ReleaseProperty])
+	es.Equal(testPurl, properties[enrich.ProductNameProperty])
+	es.Equal(es.Server.URL+"/artifacts/0/bom.cdx.json", properties[enrich.ArtifactPropertyPrefix+"bom"])
+	es.Equal(es.Server.URL+"/artifacts/0/vex.json", properties[enrich.ArtifactPropertyPrefix+"vulnerabilities"])
+
+	// The BOM artifact is fetched and linked to from the enriched document.
+	links, err := es.Backend.GetDocumentAnnotations(enriched.GetMetadata().GetId(), db.LinkToAnnotation)
+	es.Require().NoError(err)
+	es.Require().Len(links, 1)
+	es.Equal(properties[enrich.DocumentProperty], links[0].Value)
+
+	fetched, err := es.Backend.GetDocumentByID(links[0].Value)
+	es.Require().NoError(err)
+	es.Require().NotNil(fetched)
+
+	revisions, err := es.Backend.GetDocumentRevisions(enriched.GetMetadata().GetId())
+	es.Require().NoError(err)
+	es.Require().Len(revisions, 2)
+	es.Equal(base.GetMetadata().GetId(), revisions[0].GetMetadata().GetId())
+
+	tags, err := es.Backend.GetDocumentTags(enriched.GetMetadata().GetId())
+	es.Require().NoError(err)
+	es.ElementsMatch([]string{"tag1", "tag2"}, tags)
+}
+
+func (es *enrichSuite) TestEnrichErrors() {
+	opts := &options.EnrichOptions{Options: es.Options, TEAURL: es.Server.URL + testutil.TEAPath}
+
+	// None of the components of the document are published.
+	_, err := enrich.Enrich("spdx", opts)
+	es.Require().Error(err)
+
+	_, err = enrich.Enrich("urn:uuid:nonexistent", opts)
+	es.Require().Error(err)
+
+	_, err = enrich.Enrich("spdx", &options.EnrichOptions{Options: es.Options, TEAURL: "ftp://tea.example.com"})
+	es.Require().Error(err)
+}
+
+func TestEnrichSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(enrichSuite))
+}
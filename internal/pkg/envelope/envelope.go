@@ -0,0 +1,161 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/envelope/envelope.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package envelope
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// APIVersion identifies the schema of structured output. It changes only on breaking changes
+	// to the envelope or to the data of any kind.
+	APIVersion = "bomctl/v1"
+
+	FormatJSON = "json"
+	FormatText = "text"
+	FormatYAML = "yaml"
+
+	KindAliasList            = "AliasList"
+	KindDiffReport           = "DiffReport"
+	KindDocumentList         = "DocumentList"
+	KindError                = "Error"
+	KindExportedDocumentList = "ExportedDocumentList"
+	KindInferredLinkList     = "InferredLinkList"
+	KindLinkList             = "LinkList"
+	KindLinkProblemList      = "LinkProblemList"
+	KindPushedDocumentList   = "PushedDocumentList"
+	KindQueryResults         = "QueryResults"
+	KindRevisionList         = "RevisionList"
+	KindSignatureList        = "SignatureList"
+	KindStoredDocumentList   = "StoredDocumentList"
+	KindTagList              = "TagList"
+	KindVerificationList     = "VerificationList"
+	KindVersion              = "Version"
+
+	yamlIndent = 2
+)
+
+var errUnsupportedFormat = errors.New("unsupported output format")
+
+type (
+	// Envelope wraps the data emitted by a command, or the error that caused it to fail.
+	//
+	//nolint:govet // Field order determines the serialized key order.
+	Envelope struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Data       any    `json:"data,omitempty"`
+		Error      *Error `json:"error,omitempty"`
+	}
+
+	// Error describes a command failure. Source is the component that reported the error,
+	// and Details holds any additional context logged with it.
+	Error struct {
+		Details map[string]any `json:"details,omitempty"`
+		Message string         `json:"message"`
+		Source  string         `json:"source,omitempty"`
+	}
+)
+
+// IsStructured reports whether format is a machine-readable format.
+func IsStructured(format string) bool {
+	return slices.Contains([]string{FormatJSON, FormatYAML}, format)
+}
+
+// New returns an envelope of the given kind wrapping data.
+func New(kind string, data any) *Envelope {
+	return &Envelope{APIVersion: APIVersion, Kind: kind, Data: data}
+}
+
+// NewError returns an envelope describing a command failure.
+func NewError(source, message string, details map[string]any) *Envelope {
+	return &Envelope{
+		APIVersion: APIVersion,
+		Kind:       KindError,
+		Error:      &Error{Source: source, Message: message, Details: details},
+	}
+}
+
+// Write encodes the envelope to out in the given structured format.
+func (e *Envelope) Write(out io.Writer, format string) error {
+	content := &bytes.Buffer{}
+
+	encoder := json.NewEncoder(content)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(e); err != nil {
+		return fmt.Errorf("failed to encode %s output: %w", format, err)
+	}
+
+	switch format {
+	case FormatJSON:
+		if _, err := content.WriteTo(out); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+	case FormatYAML:
+		return writeYAML(out, content.Bytes())
+	default:
+		return fmt.Errorf("%w: %s", errUnsupportedFormat, format)
+	}
+
+	return nil
+}
+
+// writeYAML converts JSON content to YAML, preserving the key order and the JSON key names.
+func writeYAML(out io.Writer, content []byte) error {
+	node := &yaml.Node{}
+	if err := yaml.Unmarshal(content, node); err != nil {
+		return fmt.Errorf("failed to encode yaml output: %w", err)
+	}
+
+	resetStyle(node)
+
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(yamlIndent)
+
+	if err := encoder.Encode(node); err != nil {
+		return fmt.Errorf("failed to encode yaml output: %w", err)
+	}
+
+	if _, err := buf.WriteTo(out); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// resetStyle clears the flow and quoting styles picked up from the JSON source,
+// so that the YAML is emitted in block style.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
@@ -0,0 +1,118 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/envelope/envelope_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package envelope_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
+)
+
+type envelopeSuite struct {
+	suite.Suite
+}
+
+type testData struct {
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags"`
+	Count int      `json:"count"`
+}
+
+func (es *envelopeSuite) TestIsStructured() {
+	es.False(envelope.IsStructured(envelope.FormatText))
+	es.True(envelope.IsStructured(envelope.FormatJSON))
+	es.True(envelope.IsStructured(envelope.FormatYAML))
+	es.False(envelope.IsStructured(""))
+}
+
+func (es *envelopeSuite) TestWrite() {
+	data := testData{Name: "<name>", Tags: []string{"true", "b"}, Count: 2}
+
+	for _, subtest := range []struct {
+		name     string
+		format   string
+		expected string
+	}{
+		{
+			name:   "json",
+			format: envelope.FormatJSON,
+			expected: `{
+  "apiVersion": "bomctl/v1",
+  "kind": "Test",
+  "data": {
+    "name": "<name>",
+    "tags": [
+      "true",
+      "b"
+    ],
+    "count": 2
+  }
+}
+`,
+		},
+		{
+			name:   "yaml",
+			format: envelope.FormatYAML,
+			expected: `apiVersion: bomctl/v1
+kind: Test
+data:
+  name: <name>
+  tags:
+    - "true"
+    - b
+  count: 2
+`,
+		},
+	} {
+		es.Run(subtest.name, func() {
+			out := &bytes.Buffer{}
+
+			es.Require().NoError(envelope.New("Test", data).Write(out, subtest.format))
+			es.Equal(subtest.expected, out.String())
+		})
+	}
+}
+
+func (es *envelopeSuite) TestWriteError() {
+	out := &bytes.Buffer{}
+	env := envelope.NewError("list", "invalid filter", map[string]any{"filter": "nodes >"})
+
+	es.Require().NoError(env.Write(out, envelope.FormatYAML))
+	es.Equal(`apiVersion: bomctl/v1
+kind: Error
+error:
+  details:
+    filter: nodes >
+  message: invalid filter
+  source: list
+`, out.String())
+}
+
+func (es *envelopeSuite) TestWriteUnsupportedFormat() {
+	es.Error(envelope.New("Test", nil).Write(&bytes.Buffer{}, envelope.FormatText))
+}
+
+func TestEnvelopeSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(envelopeSuite))
+}
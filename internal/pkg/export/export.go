@@ -21,11 +21,13 @@ package export
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"slices"
 
 	"github.com/protobom/protobom/pkg/sbom"
 
+	"github.com/bomctl/bomctl/internal/pkg/attest"
 	"github.com/bomctl/bomctl/internal/pkg/db"
 	"github.com/bomctl/bomctl/internal/pkg/options"
 	"github.com/bomctl/bomctl/internal/pkg/outpututil"
@@ -46,6 +48,20 @@ func Export(sbomID string, opts *options.ExportOptions) error {
 
 	cleanupEdges(document)
 
+	if opts.Attest {
+		var out io.Writer = os.Stdout
+		if opts.OutputFile != nil {
+			out = opts.OutputFile
+		}
+
+		// Write the SBOM document wrapped in a DSSE envelope.
+		if err := attest.WriteStream(document, opts.Format, opts.Signer, opts.Options, out); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return nil
+	}
+
 	if opts.OutputFile != nil {
 		// Write the SBOM document bytes to file.
 		if err := outpututil.WriteFile(document, opts.Format, opts.Options, opts.OutputFile.Name()); err != nil {
@@ -0,0 +1,291 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/export/guac.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/outpututil"
+)
+
+// GUACManifestSuffix is appended to the path of a GUAC directory to name the manifest written next to it.
+// The manifest is kept out of the directory, as the GUAC file collector would try to ingest it.
+const GUACManifestSuffix = ".bomctl-manifest.json"
+
+const guacDirMode = 0o755
+
+type (
+	// GUACManifest lists the documents written to a GUAC directory and the links between them.
+	GUACManifest struct {
+		Documents []GUACDocument `json:"documents"`
+		Links     []GUACLink     `json:"links"`
+	}
+
+	// GUACDocument is a document written to a GUAC directory.
+	GUACDocument struct {
+		ID        string `json:"id"`
+		Alias     string `json:"alias,omitempty"`
+		File      string `json:"file"`
+		SourceURL string `json:"sourceUrl,omitempty"`
+	}
+
+	// GUACLink is a link from a document or node to another document written to a GUAC directory. Document
+	// is the ID of the document the link was found in.
+	//
+	//nolint:govet // Field order determines the serialized key order.
+	GUACLink struct {
+		Document string             `json:"document"`
+		From     options.LinkTarget `json:"from"`
+		To       string             `json:"to"`
+	}
+)
+
+// ExportGUAC writes the documents with the specified IDs or aliases to opts.GUACDir, along with every
+// document they link to, directly or indirectly, so the directory can be ingested by the GUAC file
+// collector. Links between the documents are written as BOM external references to the files of the
+// documents they link to. They are also recorded in a manifest file next to the directory, which is
+// returned, for tools reading the directory without resolving external references.
+func ExportGUAC(sbomIDs []string, opts *options.ExportOptions) (*GUACManifest, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if err := os.MkdirAll(opts.GUACDir, guacDirMode); err != nil {
+		return nil, fmt.Errorf("creating GUAC directory: %w", err)
+	}
+
+	documents, err := backend.GetDocumentsByIDOrAlias(sbomIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	manifest := &GUACManifest{Documents: []GUACDocument{}, Links: []GUACLink{}}
+	exported := []*sbom.Document{}
+	seen := map[string]bool{}
+
+	for ; len(documents) > 0; documents = documents[1:] {
+		document := documents[0]
+		id := document.GetMetadata().GetId()
+
+		if seen[id] {
+			continue
+		}
+
+		seen[id] = true
+		exported = append(exported, document)
+		manifest.Documents = append(manifest.Documents, newGUACDocument(backend, document, opts.Format))
+
+		links, err := documentLinks(backend, document)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, link := range links {
+			if link.To == id {
+				continue
+			}
+
+			target, err := backend.GetDocumentByID(link.To)
+			if err != nil || target == nil {
+				opts.Logger.Warn("Linked document not found in cache", "from", link.From.ID, "to", link.To)
+
+				continue
+			}
+
+			manifest.Links = append(manifest.Links, link)
+			documents = append(documents, target)
+		}
+	}
+
+	// The documents are written side by side, so they reference each other by file name.
+	files := map[string]string{}
+	for _, guacDocument := range manifest.Documents {
+		files[guacDocument.ID] = guacDocument.File
+	}
+
+	for idx, document := range exported {
+		if err := writeGUACDocument(backend, document, manifest.Documents[idx].File, files, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeGUACManifest(manifest, opts); err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+func newGUACDocument(backend *db.Backend, document *sbom.Document, format formats.Format) GUACDocument {
+	id := document.GetMetadata().GetId()
+	guacDocument := GUACDocument{
+		ID:    id,
+		Alias: backend.GetDocumentAlias(id),
+		File:  guacFileName(document, format),
+	}
+
+	sourceURL, err := backend.GetDocumentUniqueAnnotation(id, db.SourceURLAnnotation)
+	if err == nil {
+		guacDocument.SourceURL = sourceURL
+	}
+
+	return guacDocument
+}
+
+// writeGUACDocument writes document to file in opts.GUACDir, with its BOM external references pointed to the
+// files in files, which are keyed by the IDs of the referenced documents.
+func writeGUACDocument(
+	backend *db.Backend, document *sbom.Document, file string, files map[string]string, opts *options.ExportOptions,
+) error {
+	opts.Logger.Info("Exporting document", "sbomID", document.GetMetadata().GetId(), "file", file)
+
+	cleanupEdges(document)
+
+	rewritten, err := link.RewriteReferences(backend, document, files)
+	if err != nil {
+		return fmt.Errorf("rewriting references of %s: %w", document.GetMetadata().GetId(), err)
+	}
+
+	path := filepath.Join(opts.GUACDir, file)
+
+	if !rewritten {
+		if err := outpututil.WriteFile(document, opts.Format, opts.Options, path); err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		return nil
+	}
+
+	// The rewritten references are only in the in-memory document, so it is serialized rather than written as
+	// the original content stored with it.
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	defer out.Close()
+
+	if err := outpututil.SerializeStream(document, opts.Format, out); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// documentLinks returns the links from the document and its nodes to other documents.
+func documentLinks(backend *db.Backend, document *sbom.Document) ([]GUACLink, error) {
+	id := document.GetMetadata().GetId()
+
+	annotations, err := backend.GetDocumentAnnotations(id, db.LinkToAnnotation)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	links := []GUACLink{}
+
+	for _, annotation := range annotations {
+		links = append(links, GUACLink{
+			Document: id,
+			From:     options.LinkTarget{ID: id, Type: options.LinkTargetTypeDocument},
+			To:       annotation.Value,
+		})
+	}
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		annotations, err := backend.GetNodeAnnotations(node.GetId(), db.LinkToAnnotation)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		for _, annotation := range annotations {
+			links = append(links, GUACLink{
+				Document: id,
+				From:     options.LinkTarget{ID: node.GetId(), Type: options.LinkTargetTypeNode},
+				To:       annotation.Value,
+			})
+		}
+	}
+
+	return links, nil
+}
+
+func writeGUACManifest(manifest *GUACManifest, opts *options.ExportOptions) error {
+	guacDir, err := filepath.Abs(opts.GUACDir)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	path := guacDir + GUACManifestSuffix
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating GUAC manifest: %w", err)
+	}
+
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("writing GUAC manifest: %w", err)
+	}
+
+	opts.Logger.Info("Wrote GUAC manifest", "file", path,
+		"documents", len(manifest.Documents), "links", len(manifest.Links))
+
+	return nil
+}
+
+// guacFileName returns a file name derived from the document ID, with an extension matching the format
+// the document is written in.
+func guacFileName(document *sbom.Document, format formats.Format) string {
+	if format == db.OriginalFormat {
+		format = formats.Format(document.GetMetadata().GetSourceData().GetFormat())
+	}
+
+	name := regexp.MustCompile(`[^\w.-]+`).ReplaceAllString(document.GetMetadata().GetId(), "-")
+
+	switch {
+	case strings.Contains(string(format), "cyclonedx"):
+		name += ".cdx"
+	case strings.Contains(string(format), "spdx"):
+		name += ".spdx"
+	}
+
+	if strings.Contains(string(format), "+xml") {
+		return name + ".xml"
+	}
+
+	return name + ".json"
+}
@@ -30,12 +30,14 @@ import (
 
 	"github.com/protobom/protobom/pkg/sbom"
 
+	"github.com/bomctl/bomctl/internal/pkg/attest"
 	"github.com/bomctl/bomctl/internal/pkg/client"
 	"github.com/bomctl/bomctl/internal/pkg/client/git"
 	"github.com/bomctl/bomctl/internal/pkg/client/github"
 	"github.com/bomctl/bomctl/internal/pkg/client/http"
 	"github.com/bomctl/bomctl/internal/pkg/client/oci"
 	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
 	"github.com/bomctl/bomctl/internal/pkg/netutil"
 	"github.com/bomctl/bomctl/internal/pkg/options"
 	"github.com/bomctl/bomctl/internal/pkg/sliceutil"
@@ -72,6 +74,15 @@ func Fetch(sbomURL string, opts *options.FetchOptions) (*sbom.Document, error) {
 		return nil, fmt.Errorf("failed to fetch from %s: %w", sbomURL, err)
 	}
 
+	signatures, err := verifySignatures(fetcher, sbomURL, sbomData, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.RequireSignature && signatures.status != SignatureStatusVerified {
+		return nil, fmt.Errorf("%w: %s (%s)", errUnverified, sbomURL, signatures.status)
+	}
+
 	if opts.OutputFile != nil {
 		// Write the SBOM document bytes to file.
 		if _, err = io.Copy(opts.OutputFile, bytes.NewReader(sbomData)); err != nil {
@@ -92,6 +103,10 @@ func Fetch(sbomURL string, opts *options.FetchOptions) (*sbom.Document, error) {
 		)
 	}
 
+	if err := storeSignatures(document.GetMetadata().GetId(), signatures, backend); err != nil {
+		return nil, fmt.Errorf("storing signatures of %s: %w", document.GetMetadata().GetId(), err)
+	}
+
 	// Fetch externally referenced BOMs
 	return document, fetchExternalReferences(document, backend, opts)
 }
@@ -186,8 +201,19 @@ func getRefFile(parentFile *os.File) (*os.File, error) {
 }
 
 func saveDocument(data []byte, backend *db.Backend, opts *options.FetchOptions) (*sbom.Document, error) {
+	sbomData, envelope, err := attest.Unwrap(data)
+	if err != nil {
+		return nil, fmt.Errorf("reading attestation: %w", err)
+	}
+
+	if envelope != nil {
+		opts.Logger.Info("Extracted SBOM from attestation")
+	}
+
 	// Insert fetched document data into database.
-	document, err := backend.AddDocument(data, db.WithSourceDocumentAnnotations(data))
+	document, err := backend.AddDocument(
+		sbomData, db.WithSourceDocumentAnnotations(sbomData), db.WithAttestationAnnotation(envelope),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("adding document: %w", err)
 	}
@@ -204,6 +230,14 @@ func saveDocument(data []byte, backend *db.Backend, opts *options.FetchOptions)
 		opts.Logger.Warn("Tag(s) could not be set.", "err", err)
 	}
 
+	if len(opts.AutoLink) > 0 {
+		linkOpts := &options.LinkOptions{Options: opts.Options, Strategies: opts.AutoLink}
+
+		if _, err := link.AutoLink(backend, document.GetMetadata().GetId(), linkOpts); err != nil {
+			return nil, fmt.Errorf("linking document: %w", err)
+		}
+	}
+
 	// Fetch externally referenced BOMs
 	return document, nil
 }
@@ -0,0 +1,231 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/fetch/signature.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package fetch
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bomctl/bomctl/internal/pkg/client"
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/sign"
+	"github.com/bomctl/bomctl/internal/pkg/verify"
+)
+
+const (
+	// SignatureStatusUnsigned means no signatures were found for a fetched document.
+	SignatureStatusUnsigned = "unsigned"
+	// SignatureStatusUnverified means signatures were found, but none could be verified with a trusted key.
+	SignatureStatusUnverified = "unverified"
+	// SignatureStatusVerified means at least one signature was verified with a trusted key.
+	SignatureStatusVerified = "verified"
+)
+
+var (
+	errUnsupportedBundle = errors.New("bundle holds no message signature")
+	errUnverified        = errors.New("no signature verified with a trusted key")
+)
+
+// signatureResult is the outcome of signature discovery for a fetched document. Bundles holds the verified
+// signatures over the document bytes themselves, which are stored with the document.
+type signatureResult struct {
+	status  string
+	bundles []*sign.Bundle
+}
+
+// verifySignatures discovers the signatures published alongside the SBOM at sbomURL and verifies them with
+// the trusted keys of opts. Discovery only takes place if trusted keys are given or a signature is required.
+func verifySignatures(
+	fetcher client.Fetcher, sbomURL string, sbomData []byte, opts *options.FetchOptions,
+) (*signatureResult, error) {
+	if len(opts.TrustedKeys) == 0 && !opts.RequireSignature {
+		return nil, nil
+	}
+
+	publicKeys := []crypto.PublicKey{}
+
+	for _, keyPath := range opts.TrustedKeys {
+		publicKey, err := sign.LoadPublicKey(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("loading trusted key %s: %w", keyPath, err)
+		}
+
+		publicKeys = append(publicKeys, publicKey)
+	}
+
+	result := &signatureResult{status: SignatureStatusUnsigned}
+
+	signatureFetcher, ok := fetcher.(client.SignatureFetcher)
+	if !ok {
+		opts.Logger.Warn(fmt.Sprintf("Signature discovery is not supported for %s URLs", fetcher.Name()))
+
+		return result, nil
+	}
+
+	signatures, err := signatureFetcher.FetchSignatures(sbomURL, opts)
+	if err != nil {
+		opts.Logger.Warn("Signature discovery failed", "url", sbomURL, "err", err)
+
+		return result, nil
+	}
+
+	for _, signature := range signatures {
+		if result.status == SignatureStatusUnsigned {
+			result.status = SignatureStatusUnverified
+		}
+
+		bundle, err := toBundle(signature)
+		if err != nil {
+			opts.Logger.Warn("Skipping malformed signature", "source", signature.Source, "err", err)
+
+			continue
+		}
+
+		payload := signature.Payload
+		if payload == nil {
+			payload = sbomData
+		}
+
+		check := verify.CheckSignature(bundle, payload, certifiedKeys(signature, publicKeys, opts))
+		if check.Status != verify.StatusPass {
+			opts.Logger.Warn("Signature not verified", "source", signature.Source, "reason", check.Message)
+
+			continue
+		}
+
+		opts.Logger.Info("Verified signature", "source", signature.Source)
+
+		result.status = SignatureStatusVerified
+
+		if signature.Payload == nil {
+			digest := sha256.Sum256(sbomData)
+
+			bundle.MediaType = sign.BundleMediaType
+			bundle.MessageSignature.MessageDigest = sign.MessageDigest{Algorithm: "SHA2_256", Digest: digest[:]}
+			result.bundles = append(result.bundles, bundle)
+		}
+	}
+
+	return result, nil
+}
+
+// storeSignatures records the signature status of document, along with its verified signatures of the stored
+// SBOM so that they can be checked again with the verify command.
+func storeSignatures(documentID string, result *signatureResult, backend *db.Backend) error {
+	if result == nil {
+		return nil
+	}
+
+	if err := backend.SetDocumentUniqueAnnotation(documentID, db.SignatureStatusAnnotation, result.status); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	sourceHash, err := backend.GetDocumentUniqueAnnotation(documentID, db.SourceHashAnnotation)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	values := []string{}
+
+	for _, bundle := range result.bundles {
+		// Signatures of an attestation cover its envelope rather than the SBOM extracted from it.
+		if string(bundle.MessageSignature.MessageDigest.Digest) != sourceHash {
+			continue
+		}
+
+		data, err := json.Marshal(bundle)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		values = append(values, string(data))
+	}
+
+	if err := backend.AddDocumentAnnotations(documentID, db.SignatureAnnotation, values...); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// certifiedKeys returns the trusted keys matching the certificate or public key published with signature.
+// A published key carries no trust of its own, so it only narrows down the trusted keys to check.
+func certifiedKeys(
+	signature *client.Signature, publicKeys []crypto.PublicKey, opts *options.FetchOptions,
+) []crypto.PublicKey {
+	if len(signature.Certificate) == 0 {
+		return publicKeys
+	}
+
+	certified, err := sign.ParsePublicKey(signature.Certificate)
+	if err != nil {
+		opts.Logger.Debug("Ignoring unsupported certificate", "source", signature.Source, "err", err)
+
+		return publicKeys
+	}
+
+	certifiedHint, err := sign.KeyHint(certified)
+	if err != nil {
+		return publicKeys
+	}
+
+	matching := []crypto.PublicKey{}
+
+	for _, publicKey := range publicKeys {
+		if hint, err := sign.KeyHint(publicKey); err == nil && hint == certifiedHint {
+			matching = append(matching, publicKey)
+		}
+	}
+
+	if len(matching) == 0 {
+		opts.Logger.Warn("Signing key is not trusted", "source", signature.Source, "keyHint", certifiedHint)
+	}
+
+	return matching
+}
+
+func toBundle(signature *client.Signature) (*sign.Bundle, error) {
+	if signature.Bundle != nil {
+		bundle := &sign.Bundle{}
+		if err := json.Unmarshal(signature.Bundle, bundle); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		if len(bundle.MessageSignature.Signature) == 0 {
+			return nil, errUnsupportedBundle
+		}
+
+		return bundle, nil
+	}
+
+	// Detached signatures written by cosign are base64 encoded, but raw signatures are accepted too.
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature.Signature)))
+	if err != nil {
+		decoded = signature.Signature
+	}
+
+	return &sign.Bundle{MessageSignature: sign.MessageSignature{Signature: decoded}}, nil
+}
@@ -24,39 +24,62 @@ import (
 	"io"
 	"os"
 
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/attest"
 	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
 	"github.com/bomctl/bomctl/internal/pkg/options"
 )
 
-func Import(opts *options.ImportOptions) error {
+// Import stores the documents read from opts.InputFiles and returns them.
+func Import(opts *options.ImportOptions) ([]*sbom.Document, error) {
 	backend, err := db.BackendFromContext(opts.Context())
 	if err != nil {
-		return fmt.Errorf("%w", err)
+		return nil, fmt.Errorf("%w", err)
 	}
 
+	documents := []*sbom.Document{}
+
 	for idx := range opts.InputFiles {
 		alias := ""
 		if idx < len(opts.Alias) {
 			alias = opts.Alias[idx]
 		}
 
-		if err := saveDocument(backend, opts.InputFiles[idx], alias, opts); err != nil {
-			return fmt.Errorf("importing document: %w", err)
+		document, err := saveDocument(backend, opts.InputFiles[idx], alias, opts)
+		if err != nil {
+			return nil, fmt.Errorf("importing document: %w", err)
 		}
+
+		documents = append(documents, document)
 	}
 
-	return nil
+	return documents, nil
 }
 
-func saveDocument(backend *db.Backend, documentFile *os.File, alias string, opts *options.ImportOptions) error {
+func saveDocument(
+	backend *db.Backend, documentFile *os.File, alias string, opts *options.ImportOptions,
+) (*sbom.Document, error) {
 	data, err := io.ReadAll(documentFile)
 	if err != nil {
-		return fmt.Errorf("failed to read from %s: %w", documentFile.Name(), err)
+		return nil, fmt.Errorf("failed to read from %s: %w", documentFile.Name(), err)
+	}
+
+	sbomData, envelope, err := attest.Unwrap(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation from %s: %w", documentFile.Name(), err)
+	}
+
+	if envelope != nil {
+		opts.Logger.Info("Extracted SBOM from attestation", "file", documentFile.Name())
 	}
 
-	document, err := backend.AddDocument(data, db.WithSourceDocumentAnnotations(data))
+	document, err := backend.AddDocument(
+		sbomData, db.WithSourceDocumentAnnotations(sbomData), db.WithAttestationAnnotation(envelope),
+	)
 	if err != nil {
-		return fmt.Errorf("failed to store document: %w", err)
+		return nil, fmt.Errorf("failed to store document: %w", err)
 	}
 
 	if alias != "" {
@@ -71,5 +94,17 @@ func saveDocument(backend *db.Backend, documentFile *os.File, alias string, opts
 		opts.Logger.Warn("Tag(s) could not be set.", "err", err)
 	}
 
-	return nil
+	linkOpts := &options.LinkOptions{Options: opts.Options, Strategies: opts.AutoLink}
+
+	if err := link.ResolveDocumentReferences(backend, document.GetMetadata().GetId(), linkOpts); err != nil {
+		return nil, fmt.Errorf("failed to resolve document references: %w", err)
+	}
+
+	if len(opts.AutoLink) > 0 {
+		if _, err := link.AutoLink(backend, document.GetMetadata().GetId(), linkOpts); err != nil {
+			return nil, fmt.Errorf("failed to link document: %w", err)
+		}
+	}
+
+	return document, nil
 }
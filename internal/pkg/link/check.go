@@ -0,0 +1,404 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/link/check.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package link
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/protobom/storage/backends/ent"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/sliceutil"
+)
+
+// Problems reported by CheckLinks.
+const (
+	ProblemCycle     = "cycle"
+	ProblemDangling  = "dangling"
+	ProblemDuplicate = "duplicate"
+	ProblemRevision  = "revision"
+)
+
+type (
+	// LinkProblem is a problem with a link found by CheckLinks, along with a detail describing it and whether
+	// it was fixed.
+	LinkProblem struct {
+		Problem string             `json:"problem"`
+		Detail  string             `json:"detail,omitempty"`
+		From    options.LinkTarget `json:"from"`
+		To      options.LinkTarget `json:"to"`
+		Fixed   bool               `json:"fixed"`
+	}
+
+	// sourceLinks are the links of a document or node, along with the documents the link source belongs to.
+	sourceLinks struct {
+		from        options.LinkTarget
+		documentIDs []string
+		targets     []string
+	}
+)
+
+// CheckLinks walks every document and node link in the cache and reports links to documents missing from the
+// cache, links to superseded document revisions, links to more than one revision of the same document and
+// cycles between documents, where a node link is treated as a link from each document the node belongs to. If
+// opts.Fix is set, dangling and duplicate links are removed and links to superseded revisions are repointed to
+// the latest revision. Cycles are only reported, as there is no telling which of their links is wrong, and so
+// are links held more than once.
+func CheckLinks(backend *db.Backend, opts *options.LinkOptions) ([]LinkProblem, error) {
+	links, err := collectLinks(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := []LinkProblem{}
+
+	for _, source := range links {
+		sourceProblems, err := checkSourceLinks(backend, source, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		problems = append(problems, sourceProblems...)
+	}
+
+	if opts.Fix && len(problems) > 0 {
+		// Look for cycles among the links as they are after fixing.
+		if links, err = collectLinks(backend); err != nil {
+			return nil, err
+		}
+	}
+
+	problems = append(problems, findCycles(backend, links)...)
+
+	fixed := sliceutil.Filter(problems, func(problem LinkProblem) bool { return problem.Fixed })
+	opts.Logger.Info("Checked links", "problems", len(problems), "fixed", len(fixed))
+
+	return problems, nil
+}
+
+// collectLinks returns the links of every document in the cache and of their nodes.
+func collectLinks(backend *db.Backend) ([]sourceLinks, error) {
+	documents, err := backend.GetDocumentsByIDOrAlias()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	links := []sourceLinks{}
+	nodeLinks := map[string]*sourceLinks{}
+
+	for _, document := range documents {
+		documentID := document.GetMetadata().GetId()
+
+		annotations, err := backend.GetDocumentAnnotations(documentID, db.LinkToAnnotation)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		if len(annotations) > 0 {
+			links = append(links, sourceLinks{
+				from: options.LinkTarget{
+					ID:    documentID,
+					Alias: backend.GetDocumentAlias(documentID),
+					Type:  options.LinkTargetTypeDocument,
+				},
+				documentIDs: []string{documentID},
+				targets:     annotationValues(annotations),
+			})
+		}
+
+		if err := collectNodeLinks(backend, document, nodeLinks); err != nil {
+			return nil, err
+		}
+	}
+
+	nodeIDs := make([]string, 0, len(nodeLinks))
+	for nodeID := range nodeLinks {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+
+	slices.Sort(nodeIDs)
+
+	for _, nodeID := range nodeIDs {
+		links = append(links, *nodeLinks[nodeID])
+	}
+
+	return links, nil
+}
+
+// collectNodeLinks adds the links of the nodes of document to nodeLinks, keyed by node ID, as a node may belong
+// to more than one document.
+func collectNodeLinks(backend *db.Backend, document *sbom.Document, nodeLinks map[string]*sourceLinks) error {
+	documentID := document.GetMetadata().GetId()
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		if source, ok := nodeLinks[node.GetId()]; ok {
+			source.documentIDs = append(source.documentIDs, documentID)
+
+			continue
+		}
+
+		annotations, err := backend.GetNodeAnnotations(node.GetId(), db.LinkToAnnotation)
+		if err != nil {
+			return fmt.Errorf("%w", err)
+		}
+
+		if len(annotations) == 0 {
+			continue
+		}
+
+		nodeLinks[node.GetId()] = &sourceLinks{
+			from:        options.LinkTarget{ID: node.GetId(), Type: options.LinkTargetTypeNode},
+			documentIDs: []string{documentID},
+			targets:     annotationValues(annotations),
+		}
+	}
+
+	return nil
+}
+
+// checkSourceLinks reports the dangling, stale and duplicate links of a single document or node. A link to a
+// superseded revision of a document that is also linked to at its latest revision is a duplicate, as is a link
+// to the same document held more than once.
+func checkSourceLinks(backend *db.Backend, source sourceLinks, opts *options.LinkOptions) ([]LinkProblem, error) {
+	type checkedLink struct {
+		problem       *LinkProblem
+		replacementID string
+	}
+
+	checked := map[string]checkedLink{}
+	linked := map[string]bool{}
+	counts := map[string]int{}
+
+	for _, targetID := range source.targets {
+		if counts[targetID]++; counts[targetID] > 1 {
+			continue
+		}
+
+		problem, replacementID, err := checkTarget(backend, targetID)
+		if err != nil {
+			return nil, err
+		}
+
+		checked[targetID] = checkedLink{problem: problem, replacementID: replacementID}
+		linked[targetID] = problem == nil
+	}
+
+	problems := []LinkProblem{}
+
+	for _, targetID := range slices.Sorted(maps.Keys(checked)) {
+		problem, replacementID := checked[targetID].problem, checked[targetID].replacementID
+		repeated := problem == nil && counts[targetID] > 1
+
+		switch {
+		case repeated:
+			problem = &LinkProblem{Problem: ProblemDuplicate, Detail: fmt.Sprintf("linked %d times", counts[targetID])}
+		case problem == nil:
+			continue
+		case replacementID != "":
+			if linked[replacementID] {
+				problem.Problem, problem.Detail = ProblemDuplicate, "also linked to latest revision "+replacementID
+			}
+
+			linked[replacementID] = true
+		}
+
+		// A link held more than once, by nodes or documents sharing an ID, cannot be removed from only some of
+		// them, so it is only reported.
+		if opts.Fix && !repeated {
+			if err := repointLink(backend, source.from, targetID, replacementID); err != nil {
+				return nil, err
+			}
+
+			problem.Fixed = true
+		}
+
+		problem.From = source.from
+		problem.To = options.LinkTarget{
+			ID:    targetID,
+			Alias: backend.GetDocumentAlias(targetID),
+			Type:  options.LinkTargetTypeDocument,
+		}
+
+		opts.Logger.Debug("Found link problem", "problem", problem.Problem,
+			"from", problem.From.String(), "to", problem.To.String(), "detail", problem.Detail, "fixed", problem.Fixed,
+		)
+
+		problems = append(problems, *problem)
+	}
+
+	return problems, nil
+}
+
+// checkTarget returns the problem with linking to the document with the specified ID, if any, along with the ID
+// of the document to link to instead, which is empty if the link should be removed.
+func checkTarget(backend *db.Backend, targetID string) (*LinkProblem, string, error) {
+	target, err := backend.GetDocumentByID(targetID)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w", err)
+	}
+
+	if target == nil {
+		return &LinkProblem{Problem: ProblemDangling, Detail: "document not found"}, "", nil
+	}
+
+	latestID, err := latestRevision(backend, targetID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if latestID != targetID {
+		return &LinkProblem{Problem: ProblemRevision, Detail: "latest revision is " + latestID}, latestID, nil
+	}
+
+	return nil, targetID, nil
+}
+
+// repointLink replaces the link from source to targetID with a link to replacementID, unless source already
+// links to it or replacementID is empty.
+func repointLink(backend *db.Backend, source options.LinkTarget, targetID, replacementID string) error {
+	get, remove, add := backend.GetDocumentAnnotations, backend.RemoveDocumentAnnotations, backend.AddDocumentAnnotations
+	if source.Type == options.LinkTargetTypeNode {
+		get, remove, add = backend.GetNodeAnnotations, backend.RemoveNodeAnnotations, backend.AddNodeAnnotations
+	}
+
+	if err := remove(source.ID, db.LinkToAnnotation, targetID); err != nil {
+		return fmt.Errorf("removing link: %w", err)
+	}
+
+	if replacementID == "" {
+		return nil
+	}
+
+	annotations, err := get(source.ID, db.LinkToAnnotation)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if slices.Contains(annotationValues(annotations), replacementID) {
+		return nil
+	}
+
+	if err := add(source.ID, db.LinkToAnnotation, replacementID); err != nil {
+		return fmt.Errorf("adding link: %w", err)
+	}
+
+	return nil
+}
+
+// findCycles reports each cycle of links between documents once, as the link closing it.
+func findCycles(backend *db.Backend, links []sourceLinks) []LinkProblem {
+	type edge struct {
+		to   string
+		from options.LinkTarget
+	}
+
+	graph := map[string][]edge{}
+
+	for _, source := range links {
+		for _, documentID := range source.documentIDs {
+			for _, targetID := range source.targets {
+				// Links from the nodes a document shares with the document they link to are not a cycle.
+				if targetID == documentID {
+					continue
+				}
+
+				graph[documentID] = append(graph[documentID], edge{from: source.from, to: targetID})
+			}
+		}
+	}
+
+	documentIDs := make([]string, 0, len(graph))
+	for documentID := range graph {
+		documentIDs = append(documentIDs, documentID)
+	}
+
+	slices.Sort(documentIDs)
+
+	problems := []LinkProblem{}
+	visited, onPath := map[string]bool{}, map[string]bool{}
+	path := []string{}
+
+	var visit func(documentID string)
+
+	visit = func(documentID string) {
+		visited[documentID], onPath[documentID] = true, true
+		path = append(path, documentID)
+
+		for _, next := range graph[documentID] {
+			switch {
+			case onPath[next.to]:
+				cycle := slices.Concat(path[slices.Index(path, next.to):], []string{next.to})
+
+				problems = append(problems, LinkProblem{
+					Problem: ProblemCycle,
+					Detail:  strings.Join(cycle, " -> "),
+					From:    next.from,
+					To: options.LinkTarget{
+						ID:    next.to,
+						Alias: backend.GetDocumentAlias(next.to),
+						Type:  options.LinkTargetTypeDocument,
+					},
+				})
+			case !visited[next.to]:
+				visit(next.to)
+			}
+		}
+
+		path = path[:len(path)-1]
+		onPath[documentID] = false
+	}
+
+	for _, documentID := range documentIDs {
+		if !visited[documentID] {
+			visit(documentID)
+		}
+	}
+
+	return problems
+}
+
+// latestRevision returns the ID of the latest revision of the document with the specified ID, which is the ID
+// itself if the document was never revised or is not in the cache.
+func latestRevision(backend *db.Backend, documentID string) (string, error) {
+	revised, err := backend.GetDocumentAnnotations(documentID, db.RevisedDocumentAnnotation)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	if len(revised) == 0 {
+		return documentID, nil
+	}
+
+	latest, err := backend.GetLatestRevision(documentID)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	return latest.GetMetadata().GetId(), nil
+}
+
+func annotationValues(annotations ent.Annotations) []string {
+	return sliceutil.Extract(annotations, func(a *ent.Annotation) string { return a.Value })
+}
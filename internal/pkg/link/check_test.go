@@ -0,0 +1,161 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/link/check_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package link_test
+
+import (
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
+	"github.com/bomctl/bomctl/internal/pkg/logger"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+func (ls *linkSuite) TestCheckLinks() {
+	opts := &options.LinkOptions{Options: options.New().WithLogger(logger.New("link_check_test"))}
+
+	ls.Run("check", func() {
+		appID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a00"
+		libID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a01"
+		revisedLibID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a02"
+		utilID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a03"
+		missingID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a04"
+
+		ls.storeDocument(appID, &sbom.Node{Id: "app"}, &sbom.Node{Id: "app-lib"})
+		ls.storeDocument(libID, &sbom.Node{Id: "lib"})
+		ls.storeDocument(utilID, &sbom.Node{Id: "util"})
+
+		base, err := ls.Backend.GetDocumentByID(libID)
+		ls.Require().NoError(err)
+
+		revised := sbom.NewDocument()
+		revised.Metadata.Id = revisedLibID
+		revised.NodeList.AddRootNode(&sbom.Node{Id: "revised-lib"})
+
+		ls.Require().NoError(ls.Backend.StoreDocument(revised, db.WithRevisedDocumentAnnotations(base)))
+
+		ls.Require().NoError(ls.Backend.AddDocumentAnnotations(appID, db.LinkToAnnotation, missingID, utilID))
+		ls.Require().NoError(ls.Backend.AddNodeAnnotations("app-lib", db.LinkToAnnotation, libID))
+		ls.Require().NoError(ls.Backend.AddNodeAnnotations("util", db.LinkToAnnotation, appID, libID, revisedLibID))
+
+		appTarget := options.LinkTarget{ID: appID, Type: options.LinkTargetTypeDocument}
+		libTarget := options.LinkTarget{ID: libID, Type: options.LinkTargetTypeDocument}
+		missingTarget := options.LinkTarget{ID: missingID, Type: options.LinkTargetTypeDocument}
+		appLibNode := options.LinkTarget{ID: "app-lib", Type: options.LinkTargetTypeNode}
+		utilNode := options.LinkTarget{ID: "util", Type: options.LinkTargetTypeNode}
+
+		problems, err := link.CheckLinks(ls.Backend, opts)
+		ls.Require().NoError(err)
+
+		ls.ElementsMatch([]link.LinkProblem{
+			{Problem: link.ProblemDangling, Detail: "document not found", From: appTarget, To: missingTarget},
+			{
+				Problem: link.ProblemRevision,
+				Detail:  "latest revision is " + revisedLibID,
+				From:    appLibNode,
+				To:      libTarget,
+			},
+			{
+				Problem: link.ProblemDuplicate,
+				Detail:  "also linked to latest revision " + revisedLibID,
+				From:    utilNode,
+				To:      libTarget,
+			},
+			{
+				Problem: link.ProblemCycle,
+				Detail:  appID + " -> " + utilID + " -> " + appID,
+				From:    utilNode,
+				To:      appTarget,
+			},
+		}, problems)
+
+		opts.Fix = true
+
+		problems, err = link.CheckLinks(ls.Backend, opts)
+		ls.Require().NoError(err)
+		ls.Len(problems, 4)
+
+		for _, problem := range problems {
+			ls.Equal(problem.Problem != link.ProblemCycle, problem.Fixed, problem.Problem)
+		}
+
+		annotations, err := ls.Backend.GetDocumentAnnotations(appID, db.LinkToAnnotation)
+		ls.Require().NoError(err)
+		ls.Require().Len(annotations, 1)
+		ls.Equal(utilID, annotations[0].Value)
+
+		annotations, err = ls.Backend.GetNodeAnnotations("app-lib", db.LinkToAnnotation)
+		ls.Require().NoError(err)
+		ls.Require().Len(annotations, 1)
+		ls.Equal(revisedLibID, annotations[0].Value)
+
+		annotations, err = ls.Backend.GetNodeAnnotations("util", db.LinkToAnnotation)
+		ls.Require().NoError(err)
+		ls.Require().Len(annotations, 2)
+		ls.ElementsMatch([]string{appID, revisedLibID}, []string{annotations[0].Value, annotations[1].Value})
+
+		// Only the cycle remains once fixed.
+		opts.Fix = false
+
+		problems, err = link.CheckLinks(ls.Backend, opts)
+		ls.Require().NoError(err)
+		ls.Require().Len(problems, 1)
+		ls.Equal(link.ProblemCycle, problems[0].Problem)
+	})
+
+	ls.Run("shared nodes", func() {
+		appID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a00"
+		libID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a01"
+		utilID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a02"
+		toolID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a03"
+
+		// A node shared by a document with the document it links to does not make a cycle.
+		ls.storeDocument(appID, &sbom.Node{Id: "app"}, &sbom.Node{Id: "lib"})
+		ls.storeDocument(libID, &sbom.Node{Id: "lib"})
+		ls.Require().NoError(ls.Backend.AddNodeAnnotations("lib", db.LinkToAnnotation, libID))
+
+		// Nodes of the same ID in different documents each hold the link.
+		ls.storeDocument(utilID, &sbom.Node{Id: "util"}, &sbom.Node{Id: "dep", Name: "util-dep"})
+		ls.storeDocument(toolID, &sbom.Node{Id: "tool"}, &sbom.Node{Id: "dep", Name: "tool-dep"})
+		ls.Require().NoError(ls.Backend.AddAnnotationToNodes(db.LinkToAnnotation, libID, "dep"))
+
+		expected := []link.LinkProblem{{
+			Problem: link.ProblemDuplicate,
+			Detail:  "linked 2 times",
+			From:    options.LinkTarget{ID: "dep", Type: options.LinkTargetTypeNode},
+			To:      options.LinkTarget{ID: libID, Type: options.LinkTargetTypeDocument},
+		}}
+
+		problems, err := link.CheckLinks(ls.Backend, opts)
+		ls.Require().NoError(err)
+		ls.Equal(expected, problems)
+
+		// Links held more than once are only reported.
+		opts.Fix = true
+
+		problems, err = link.CheckLinks(ls.Backend, opts)
+		ls.Require().NoError(err)
+		ls.Equal(expected, problems)
+
+		annotations, err := ls.Backend.GetNodeAnnotations("dep", db.LinkToAnnotation)
+		ls.Require().NoError(err)
+		ls.Len(annotations, 2)
+	})
+}
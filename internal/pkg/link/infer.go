@@ -0,0 +1,355 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/link/infer.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package link
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/sliceutil"
+)
+
+// Strategies of link inference, naming what a node has in common with the document it is linked to.
+const (
+	StrategyNameVersion = "name-version"
+	StrategyPURL        = "purl"
+	StrategySerial      = "serial"
+)
+
+const (
+	bomLinkPrefix = "urn:cdx:"
+	serialPrefix  = "urn:uuid:"
+)
+
+var errDocumentNotFound = errors.New("document not found")
+
+type (
+	// InferredLink is a link from a node to a document created by InferLinks, along with the strategy that
+	// matched them and the value they had in common.
+	InferredLink struct {
+		Match string             `json:"match"`
+		Value string             `json:"value"`
+		From  options.LinkTarget `json:"from"`
+		To    options.LinkTarget `json:"to"`
+	}
+
+	// rootIndex maps the identifying values of root nodes to the IDs of the documents they describe.
+	rootIndex struct {
+		nameVersions map[string][]string
+		purls        map[string][]string
+		serials      map[string][]string
+		strategies   []string
+	}
+)
+
+// InferLinks links nodes of the documents with the specified IDs or aliases, or of every document in the
+// cache if none are specified, to the documents whose root component they identify. A node identifies a
+// document if its purl or its name and version match a root node of the document, or if it references the
+// CycloneDX serial number of the document in a BOM external reference. Only the strategies of opts are
+// used, or all of them if none are set. Superseded document revisions are never linked to. The links
+// created are returned, existing links are left as they are.
+func InferLinks(backend *db.Backend, sbomIDs []string, opts *options.LinkOptions) ([]InferredLink, error) {
+	documents, err := backend.GetDocumentsByIDOrAlias()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	sources := documents
+
+	if len(sbomIDs) > 0 {
+		sources = []*sbom.Document{}
+
+		for _, sbomID := range sbomIDs {
+			document, err := backend.GetDocumentByIDOrAlias(sbomID)
+			if err != nil {
+				return nil, fmt.Errorf("%w", err)
+			}
+
+			if document == nil {
+				return nil, fmt.Errorf("%w: %s", errDocumentNotFound, sbomID)
+			}
+
+			sources = append(sources, document)
+		}
+	}
+
+	existing, err := existingLinks(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	inferred, err := inferLinks(backend, sources, documents, existing, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	opts.Logger.Info("Inferred links", "count", len(inferred))
+
+	return inferred, nil
+}
+
+// AutoLink infers links as InferLinks does in both directions between the document with the specified ID
+// and every other document in the cache, so that newly added documents are linked as they arrive.
+func AutoLink(backend *db.Backend, documentID string, opts *options.LinkOptions) ([]InferredLink, error) {
+	documents, err := backend.GetDocumentsByIDOrAlias()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	document, err := backend.GetDocumentByID(documentID)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	existing, err := existingLinks(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	outgoing, err := inferLinks(backend, []*sbom.Document{document}, documents, existing, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	incoming, err := inferLinks(backend, documents, []*sbom.Document{document}, existing, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	inferred := slices.Concat(outgoing, incoming)
+
+	if len(inferred) > 0 {
+		opts.Logger.Info("Inferred links", "id", documentID, "count", len(inferred))
+	}
+
+	return inferred, nil
+}
+
+// Strategies returns the names of all link inference strategies.
+func Strategies() []string {
+	return []string{StrategyNameVersion, StrategyPURL, StrategySerial}
+}
+
+// existingLinks returns the IDs of the documents each node in the cache links to, keyed by node ID.
+func existingLinks(backend *db.Backend) (map[string]map[string]bool, error) {
+	nodes, err := backend.GetNodesByAnnotation(db.LinkToAnnotation)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	existing := map[string]map[string]bool{}
+
+	for _, node := range nodes {
+		if _, ok := existing[node.GetId()]; ok {
+			continue
+		}
+
+		annotations, err := backend.GetNodeAnnotations(node.GetId(), db.LinkToAnnotation)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		existing[node.GetId()] = map[string]bool{}
+		for _, annotation := range annotations {
+			existing[node.GetId()][annotation.Value] = true
+		}
+	}
+
+	return existing, nil
+}
+
+func inferLinks(
+	backend *db.Backend, sources, targets []*sbom.Document, existing map[string]map[string]bool,
+	opts *options.LinkOptions,
+) ([]InferredLink, error) {
+	index, err := newRootIndex(backend, targets, opts.Strategies)
+	if err != nil {
+		return nil, err
+	}
+
+	inferred := []InferredLink{}
+
+	for _, document := range sources {
+		links, err := inferDocumentLinks(backend, document, index, existing, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		inferred = append(inferred, links...)
+	}
+
+	return inferred, nil
+}
+
+func inferDocumentLinks(
+	backend *db.Backend, document *sbom.Document, index *rootIndex, existing map[string]map[string]bool,
+	opts *options.LinkOptions,
+) ([]InferredLink, error) {
+	documentID := document.GetMetadata().GetId()
+	rootIDs := document.GetNodeList().GetRootElements()
+	inferred := []InferredLink{}
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		// Root nodes describe their own document rather than a dependency of it, so they are never linked.
+		if sliceutil.Any(rootIDs, func(id string) bool { return id == node.GetId() }) {
+			continue
+		}
+
+		linked := existing[node.GetId()]
+
+		for _, link := range index.match(node) {
+			if link.To.ID == documentID || linked[link.To.ID] {
+				continue
+			}
+
+			if err := backend.AddNodeAnnotations(node.GetId(), db.LinkToAnnotation, link.To.ID); err != nil {
+				return nil, fmt.Errorf("adding node link: %w", err)
+			}
+
+			if linked == nil {
+				linked = map[string]bool{}
+				existing[node.GetId()] = linked
+			}
+
+			linked[link.To.ID] = true
+
+			link.To.Alias = backend.GetDocumentAlias(link.To.ID)
+			inferred = append(inferred, link)
+
+			opts.Logger.Debug("Inferred node link",
+				"from", link.From.String(), "to", link.To.String(), "match", link.Match, "value", link.Value,
+			)
+		}
+	}
+
+	return inferred, nil
+}
+
+func newRootIndex(backend *db.Backend, documents []*sbom.Document, strategies []string) (*rootIndex, error) {
+	if len(strategies) == 0 {
+		strategies = Strategies()
+	}
+
+	index := &rootIndex{
+		nameVersions: map[string][]string{},
+		purls:        map[string][]string{},
+		serials:      map[string][]string{},
+		strategies:   strategies,
+	}
+
+	for _, document := range documents {
+		documentID := document.GetMetadata().GetId()
+
+		revised, err := backend.GetDocumentAnnotations(documentID, db.RevisedDocumentAnnotation)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		if len(revised) > 0 {
+			continue
+		}
+
+		if strings.HasPrefix(documentID, serialPrefix) {
+			index.serials[documentID] = append(index.serials[documentID], documentID)
+		}
+
+		for _, root := range document.GetNodeList().GetRootNodes() {
+			if purl := string(root.Purl()); purl != "" {
+				index.purls[purl] = append(index.purls[purl], documentID)
+			}
+
+			if nameVersion := nameVersionKey(root); nameVersion != "" {
+				index.nameVersions[nameVersion] = append(index.nameVersions[nameVersion], documentID)
+			}
+		}
+	}
+
+	return index, nil
+}
+
+// match returns a link from node to each indexed document it identifies, in order of match precedence.
+func (index *rootIndex) match(node *sbom.Node) []InferredLink {
+	links := []InferredLink{}
+
+	addLinks := func(strategy, value string, documentIDs []string) {
+		if !slices.Contains(index.strategies, strategy) {
+			return
+		}
+
+		for _, documentID := range documentIDs {
+			links = append(links, InferredLink{
+				Match: strategy,
+				Value: value,
+				From:  options.LinkTarget{ID: node.GetId(), Type: options.LinkTargetTypeNode},
+				To:    options.LinkTarget{ID: documentID, Type: options.LinkTargetTypeDocument},
+			})
+		}
+	}
+
+	if purl := string(node.Purl()); purl != "" {
+		addLinks(StrategyPURL, purl, index.purls[purl])
+	}
+
+	for _, ref := range node.GetExternalReferences() {
+		if ref.GetType() != sbom.ExternalReference_BOM {
+			continue
+		}
+
+		if serial := serialNumber(ref.GetUrl()); serial != "" {
+			addLinks(StrategySerial, serial, index.serials[serial])
+		}
+	}
+
+	if nameVersion := nameVersionKey(node); nameVersion != "" {
+		addLinks(StrategyNameVersion, nameVersion, index.nameVersions[nameVersion])
+	}
+
+	return links
+}
+
+func nameVersionKey(node *sbom.Node) string {
+	if node.GetName() == "" || node.GetVersion() == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s@%s", node.GetName(), node.GetVersion())
+}
+
+// serialNumber returns the CycloneDX serial number referenced by url, which is either the serial number itself
+// or a BOM-Link of the form urn:cdx:serial-number/version#bom-ref.
+func serialNumber(url string) string {
+	switch {
+	case strings.HasPrefix(url, serialPrefix):
+		return url
+	case strings.HasPrefix(url, bomLinkPrefix):
+		serial, _, _ := strings.Cut(strings.TrimPrefix(url, bomLinkPrefix), "/")
+		serial, _, _ = strings.Cut(serial, "#")
+
+		return serialPrefix + serial
+	default:
+		return ""
+	}
+}
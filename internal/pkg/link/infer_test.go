@@ -0,0 +1,157 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/link/infer_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package link_test
+
+import (
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
+	"github.com/bomctl/bomctl/internal/pkg/logger"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+func (ls *linkSuite) storeDocument(id string, root *sbom.Node, nodes ...*sbom.Node) {
+	document := sbom.NewDocument()
+	document.Metadata.Id = id
+	document.NodeList.AddRootNode(root)
+
+	for _, node := range nodes {
+		document.NodeList.AddNode(node)
+	}
+
+	ls.Require().NoError(ls.Backend.StoreDocument(document))
+}
+
+func (ls *linkSuite) TestInferLinks() {
+	opts := &options.LinkOptions{Options: options.New().WithLogger(logger.New("link_infer_test"))}
+
+	ls.Run("infer", func() {
+		libID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a01"
+		toolID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a02"
+		utilID := "https://example.com/util.spdx.json"
+		oldID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a03"
+
+		libPurl := map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): "pkg:npm/lib@1.0.0"}
+		oldPurl := map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): "pkg:npm/old@1.0.0"}
+
+		ls.storeDocument(libID, &sbom.Node{Id: "lib", Name: "lib", Version: "1.0.0", Identifiers: libPurl})
+		ls.storeDocument(toolID, &sbom.Node{Id: "tool", Name: "tool"})
+		ls.storeDocument(utilID, &sbom.Node{Id: "util", Name: "util", Version: "2.0.0"})
+		ls.storeDocument(oldID, &sbom.Node{Id: "old", Name: "old", Version: "1.0.0", Identifiers: oldPurl})
+
+		// Superseded revisions are not linked to.
+		ls.Require().NoError(ls.Backend.AddDocumentAnnotations(oldID, db.RevisedDocumentAnnotation, "true"))
+
+		ls.storeDocument("urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a00",
+			&sbom.Node{Id: "app", Name: "lib", Version: "1.0.0", Identifiers: libPurl},
+			&sbom.Node{Id: "app-lib", Name: "lib", Version: "1.0.0", Identifiers: libPurl},
+			&sbom.Node{Id: "app-tool", Name: "tool", ExternalReferences: []*sbom.ExternalReference{
+				{Type: sbom.ExternalReference_BOM, Url: "urn:cdx:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a02/1#tool"},
+			}},
+			&sbom.Node{Id: "app-util", Name: "util", Version: "2.0.0"},
+			&sbom.Node{Id: "app-old", Name: "old", Version: "1.0.0", Identifiers: oldPurl},
+		)
+
+		inferred, err := link.InferLinks(ls.Backend, nil, opts)
+		ls.Require().NoError(err)
+
+		// Links are only inferred once for each node, by the match of highest precedence.
+		ls.ElementsMatch([]link.InferredLink{
+			{
+				Match: link.StrategyPURL,
+				Value: "pkg:npm/lib@1.0.0",
+				From:  options.LinkTarget{ID: "app-lib", Type: options.LinkTargetTypeNode},
+				To:    options.LinkTarget{ID: libID, Type: options.LinkTargetTypeDocument},
+			},
+			{
+				Match: link.StrategySerial,
+				Value: toolID,
+				From:  options.LinkTarget{ID: "app-tool", Type: options.LinkTargetTypeNode},
+				To:    options.LinkTarget{ID: toolID, Type: options.LinkTargetTypeDocument},
+			},
+			{
+				Match: link.StrategyNameVersion,
+				Value: "util@2.0.0",
+				From:  options.LinkTarget{ID: "app-util", Type: options.LinkTargetTypeNode},
+				To:    options.LinkTarget{ID: utilID, Type: options.LinkTargetTypeDocument},
+			},
+		}, inferred)
+
+		annotations, err := ls.Backend.GetNodeAnnotations("app-lib", db.LinkToAnnotation)
+		ls.Require().NoError(err)
+		ls.Require().Len(annotations, 1)
+		ls.Equal(libID, annotations[0].Value)
+
+		// Existing links are left as they are.
+		inferred, err = link.InferLinks(ls.Backend, []string{"urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a00"}, opts)
+		ls.Require().NoError(err)
+		ls.Empty(inferred)
+
+		_, err = link.InferLinks(ls.Backend, []string{"urn:uuid:nonexistent"}, opts)
+		ls.Require().Error(err)
+	})
+}
+
+func (ls *linkSuite) TestAutoLink() {
+	opts := &options.LinkOptions{
+		Options:    options.New().WithLogger(logger.New("link_auto_test")),
+		Strategies: []string{link.StrategyPURL},
+	}
+
+	ls.Run("auto", func() {
+		appID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a00"
+		libID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a01"
+		utilID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a02"
+		purlLibID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a03"
+
+		libPurl := map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): "pkg:npm/lib@1.0.0"}
+		utilPurl := map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): "pkg:npm/util@2.0.0"}
+
+		ls.storeDocument(utilID, &sbom.Node{Id: "util", Name: "util", Version: "2.0.0", Identifiers: utilPurl})
+		ls.storeDocument(appID,
+			&sbom.Node{Id: "app", Name: "app"},
+			&sbom.Node{Id: "app-lib", Name: "lib", Version: "1.0.0", Identifiers: libPurl},
+			&sbom.Node{Id: "app-util", Name: "util", Version: "2.0.0", Identifiers: utilPurl},
+		)
+
+		// Nodes of the added document are linked to existing documents.
+		inferred, err := link.AutoLink(ls.Backend, appID, opts)
+		ls.Require().NoError(err)
+		ls.Require().Len(inferred, 1)
+		ls.Equal("app-util", inferred[0].From.ID)
+		ls.Equal(utilID, inferred[0].To.ID)
+
+		// Nodes of existing documents are linked to the added document.
+		ls.storeDocument(libID, &sbom.Node{Id: "lib", Name: "lib", Version: "1.0.0"})
+
+		inferred, err = link.AutoLink(ls.Backend, libID, opts)
+		ls.Require().NoError(err)
+		ls.Empty(inferred, "name-version strategy is not enabled")
+
+		ls.storeDocument(purlLibID, &sbom.Node{Id: "purl-lib", Name: "lib", Version: "1.0.0", Identifiers: libPurl})
+
+		inferred, err = link.AutoLink(ls.Backend, purlLibID, opts)
+		ls.Require().NoError(err)
+		ls.Require().Len(inferred, 1)
+		ls.Equal("app-lib", inferred[0].From.ID)
+		ls.Equal(purlLibID, inferred[0].To.ID)
+	})
+}
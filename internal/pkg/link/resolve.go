@@ -0,0 +1,282 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/link/resolve.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package link
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+const spdxIDPrefix = "SPDXRef-"
+
+type (
+	// documentReference is a reference from a node to another document, either a CycloneDX BOM-Link or the
+	// namespace of an SPDX external document reference.
+	documentReference struct {
+		nodeID string
+		ref    string
+	}
+
+	// referenceIndex maps the IDs of cached documents, and the SPDX namespaces they were created with, to the
+	// ID of the latest revision of each document.
+	referenceIndex map[string]string
+
+	// spdxDocumentRefs are the parts of an SPDX JSON document that reference other documents.
+	spdxDocumentRefs struct {
+		ExternalDocumentRefs []struct {
+			ExternalDocumentID string `json:"externalDocumentId"`
+			SPDXDocument       string `json:"spdxDocument"`
+		} `json:"externalDocumentRefs"`
+		Relationships []struct {
+			SPDXElementID      string `json:"spdxElementId"`
+			RelatedSPDXElement string `json:"relatedSpdxElement"`
+		} `json:"relationships"`
+	}
+)
+
+// ResolveDocumentReferences links the nodes of the document with the specified ID to the cached documents
+// referenced by CycloneDX BOM-Links in their BOM external references, or by SPDX external document references.
+// An SPDX external document reference links the nodes in a relationship with an element of the referenced
+// document, or the root nodes if there are none. References to documents not in the cache are recorded on their
+// nodes, and references recorded on the nodes of other documents are resolved once the cache holds the document.
+func ResolveDocumentReferences(backend *db.Backend, documentID string, opts *options.LinkOptions) error {
+	document, err := backend.GetDocumentByID(documentID)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if document == nil {
+		return fmt.Errorf("%w: %s", errDocumentNotFound, documentID)
+	}
+
+	index, err := newReferenceIndex(backend)
+	if err != nil {
+		return err
+	}
+
+	pending, err := pendingReferences(backend)
+	if err != nil {
+		return err
+	}
+
+	references, err := documentReferences(backend, document)
+	if err != nil {
+		return err
+	}
+
+	resolved, unresolved := 0, 0
+
+	for _, reference := range pending {
+		if targetID, ok := index[referencedID(reference.ref)]; ok {
+			if err := linkReference(backend, reference, targetID, opts); err != nil {
+				return err
+			}
+
+			resolved++
+		}
+	}
+
+	for _, reference := range references {
+		switch targetID, ok := index[referencedID(reference.ref)]; {
+		case !ok:
+			if err := backend.AddNodeAnnotations(
+				reference.nodeID, db.UnresolvedLinkAnnotation, reference.ref,
+			); err != nil {
+				return fmt.Errorf("recording unresolved reference: %w", err)
+			}
+
+			unresolved++
+		case targetID != documentID:
+			// References of a document to its own components are not links.
+			if err := linkReference(backend, reference, targetID, opts); err != nil {
+				return err
+			}
+
+			resolved++
+		}
+	}
+
+	if resolved > 0 || unresolved > 0 {
+		opts.Logger.Info("Resolved document references",
+			"id", documentID, "resolved", resolved, "unresolved", unresolved,
+		)
+	}
+
+	return nil
+}
+
+// linkReference links the node of reference to the document with the specified ID, replacing the unresolved
+// reference recorded on the node if there is one.
+func linkReference(backend *db.Backend, reference documentReference, targetID string, opts *options.LinkOptions) error {
+	if err := backend.AddNodeAnnotations(reference.nodeID, db.LinkToAnnotation, targetID); err != nil {
+		return fmt.Errorf("adding node link: %w", err)
+	}
+
+	if err := backend.RemoveNodeAnnotations(reference.nodeID, db.UnresolvedLinkAnnotation, reference.ref); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	opts.Logger.Debug("Resolved document reference", "node", reference.nodeID, "ref", reference.ref, "to", targetID)
+
+	return nil
+}
+
+func newReferenceIndex(backend *db.Backend) (referenceIndex, error) {
+	documents, err := backend.GetDocumentsByIDOrAlias()
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	index := referenceIndex{}
+
+	for _, document := range documents {
+		documentID := document.GetMetadata().GetId()
+
+		latestID, err := latestRevision(backend, documentID)
+		if err != nil {
+			return nil, err
+		}
+
+		index[documentID] = latestID
+
+		// SPDX document IDs are the document namespace followed by the SPDX identifier of the document.
+		if namespace, _, found := strings.Cut(documentID, "#"); found {
+			index[namespace] = latestID
+		}
+	}
+
+	return index, nil
+}
+
+// pendingReferences returns the references recorded on nodes that could not be resolved when they were added.
+func pendingReferences(backend *db.Backend) ([]documentReference, error) {
+	nodes, err := backend.GetNodesByAnnotation(db.UnresolvedLinkAnnotation)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	pending := []documentReference{}
+
+	for _, node := range nodes {
+		annotations, err := backend.GetNodeAnnotations(node.GetId(), db.UnresolvedLinkAnnotation)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		for _, annotation := range annotations {
+			pending = append(pending, documentReference{nodeID: node.GetId(), ref: annotation.Value})
+		}
+	}
+
+	return pending, nil
+}
+
+// documentReferences returns the CycloneDX BOM-Links and SPDX external document references of document.
+func documentReferences(backend *db.Backend, document *sbom.Document) ([]documentReference, error) {
+	references := []documentReference{}
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		for _, ref := range node.GetExternalReferences() {
+			if ref.GetType() == sbom.ExternalReference_BOM && strings.HasPrefix(ref.GetUrl(), bomLinkPrefix) {
+				references = append(references, documentReference{nodeID: node.GetId(), ref: ref.GetUrl()})
+			}
+		}
+	}
+
+	spdxReferences, err := spdxDocumentReferences(backend, document)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(references, spdxReferences...), nil
+}
+
+// spdxDocumentReferences returns the external document references of an SPDX JSON document. They are not kept
+// by protobom, so they are read from the source data of the document.
+func spdxDocumentReferences(backend *db.Backend, document *sbom.Document) ([]documentReference, error) {
+	format := formats.Format(document.GetMetadata().GetSourceData().GetFormat())
+	if format.Type() != formats.SPDXFORMAT || format.Encoding() != formats.JSON {
+		return nil, nil
+	}
+
+	sourceData, err := backend.GetDocumentUniqueAnnotation(document.GetMetadata().GetId(), db.SourceDataAnnotation)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if sourceData == "" {
+		return nil, nil
+	}
+
+	spdxRefs := &spdxDocumentRefs{}
+	if err := json.Unmarshal([]byte(sourceData), spdxRefs); err != nil {
+		return nil, fmt.Errorf("parsing external document references: %w", err)
+	}
+
+	references := []documentReference{}
+
+	for _, externalRef := range spdxRefs.ExternalDocumentRefs {
+		nodeIDs := []string{}
+
+		for _, relationship := range spdxRefs.Relationships {
+			elements := []string{relationship.SPDXElementID, relationship.RelatedSPDXElement}
+
+			for idx, element := range elements {
+				// Node IDs are SPDX identifiers without their prefix.
+				if strings.HasPrefix(element, externalRef.ExternalDocumentID+":") {
+					nodeIDs = append(nodeIDs, strings.TrimPrefix(elements[1-idx], spdxIDPrefix))
+				}
+			}
+		}
+
+		// Only elements of the document that are nodes can be linked, the document itself is linked by its roots.
+		nodeIDs = slices.DeleteFunc(nodeIDs, func(nodeID string) bool {
+			return document.GetNodeList().GetNodeByID(nodeID) == nil
+		})
+
+		if len(nodeIDs) == 0 {
+			nodeIDs = document.GetNodeList().GetRootElements()
+		}
+
+		for _, nodeID := range slices.Compact(slices.Sorted(slices.Values(nodeIDs))) {
+			references = append(references, documentReference{nodeID: nodeID, ref: externalRef.SPDXDocument})
+		}
+	}
+
+	return references, nil
+}
+
+// referencedID returns the ID of the document referenced by ref, which is either a CycloneDX BOM-Link or an SPDX
+// document namespace, as a key of referenceIndex.
+func referencedID(ref string) string {
+	if strings.HasPrefix(ref, bomLinkPrefix) {
+		return serialNumber(ref)
+	}
+
+	return ref
+}
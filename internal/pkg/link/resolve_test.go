@@ -0,0 +1,77 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/link/resolve_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package link_test
+
+import (
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
+	"github.com/bomctl/bomctl/internal/pkg/logger"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+func (ls *linkSuite) TestResolveDocumentReferences() {
+	opts := &options.LinkOptions{Options: options.New().WithLogger(logger.New("link_resolve_test"))}
+
+	ls.Run("resolve", func() {
+		appID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a00"
+		libID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a01"
+		bomLink := "urn:cdx:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a01/1#lib"
+
+		ls.storeDocument(appID,
+			&sbom.Node{Id: "app"},
+			&sbom.Node{Id: "app-lib", ExternalReferences: []*sbom.ExternalReference{
+				{Type: sbom.ExternalReference_BOM, Url: bomLink},
+			}},
+			&sbom.Node{Id: "app-self", ExternalReferences: []*sbom.ExternalReference{
+				{Type: sbom.ExternalReference_BOM, Url: "urn:cdx:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a00/1#app"},
+			}},
+		)
+
+		// References to documents missing from the cache are recorded.
+		ls.Require().NoError(link.ResolveDocumentReferences(ls.Backend, appID, opts))
+
+		annotations, err := ls.Backend.GetNodeAnnotations("app-lib", db.UnresolvedLinkAnnotation)
+		ls.Require().NoError(err)
+		ls.Require().Len(annotations, 1)
+		ls.Equal(bomLink, annotations[0].Value)
+
+		// Recorded references are resolved once the referenced document is added.
+		ls.storeDocument(libID, &sbom.Node{Id: "lib"})
+		ls.Require().NoError(link.ResolveDocumentReferences(ls.Backend, libID, opts))
+
+		annotations, err = ls.Backend.GetNodeAnnotations("app-lib", db.UnresolvedLinkAnnotation)
+		ls.Require().NoError(err)
+		ls.Empty(annotations)
+
+		annotations, err = ls.Backend.GetNodeAnnotations("app-lib", db.LinkToAnnotation)
+		ls.Require().NoError(err)
+		ls.Require().Len(annotations, 1)
+		ls.Equal(libID, annotations[0].Value)
+
+		// References of a document to itself are not links.
+		annotations, err = ls.Backend.GetNodeAnnotations("app-self", db.LinkToAnnotation)
+		ls.Require().NoError(err)
+		ls.Empty(annotations)
+
+		ls.Require().Error(link.ResolveDocumentReferences(ls.Backend, "urn:uuid:missing", opts))
+	})
+}
@@ -0,0 +1,114 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/link/rewrite.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package link
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+)
+
+// RewriteReferences points the BOM external references of document to the URLs in urls, which are keyed by
+// the IDs of the referenced documents. External references are matched to documents by their ID, their
+// CycloneDX serial number or the URL they were fetched from. Nodes linked to a document in urls, and the root
+// nodes of a document linked to one, are given a BOM external reference to its URL if they lack one. Only the
+// in-memory document is changed, and the return value reports whether it was.
+func RewriteReferences(backend *db.Backend, document *sbom.Document, urls map[string]string) (bool, error) {
+	if len(urls) == 0 {
+		return false, nil
+	}
+
+	rewritten := false
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		changed, err := rewriteNodeReferences(backend, node, urls)
+		if err != nil {
+			return false, err
+		}
+
+		annotations, err := backend.GetNodeAnnotations(node.GetId(), db.LinkToAnnotation)
+		if err != nil {
+			return false, fmt.Errorf("%w", err)
+		}
+
+		for _, annotation := range annotations {
+			changed = addReference(node, urls[annotation.Value]) || changed
+		}
+
+		rewritten = rewritten || changed
+	}
+
+	annotations, err := backend.GetDocumentAnnotations(document.GetMetadata().GetId(), db.LinkToAnnotation)
+	if err != nil {
+		return false, fmt.Errorf("%w", err)
+	}
+
+	for _, annotation := range annotations {
+		for _, root := range document.GetNodeList().GetRootNodes() {
+			rewritten = addReference(root, urls[annotation.Value]) || rewritten
+		}
+	}
+
+	return rewritten, nil
+}
+
+func rewriteNodeReferences(backend *db.Backend, node *sbom.Node, urls map[string]string) (bool, error) {
+	rewritten := false
+
+	for _, ref := range node.GetExternalReferences() {
+		if ref.GetType() != sbom.ExternalReference_BOM {
+			continue
+		}
+
+		_, err := resolveReference(backend, ref.GetUrl(), func(documentID string) (bool, error) {
+			url, ok := urls[documentID]
+			if ok && url != ref.GetUrl() {
+				ref.Url = url
+				rewritten = true
+			}
+
+			return ok, nil
+		})
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return rewritten, nil
+}
+
+// addReference adds a BOM external reference to url to node, unless url is empty or already referenced.
+func addReference(node *sbom.Node, url string) bool {
+	if url == "" || slices.ContainsFunc(node.GetExternalReferences(), func(ref *sbom.ExternalReference) bool {
+		return ref.GetType() == sbom.ExternalReference_BOM && ref.GetUrl() == url
+	}) {
+		return false
+	}
+
+	node.ExternalReferences = append(node.ExternalReferences, &sbom.ExternalReference{
+		Url:  url,
+		Type: sbom.ExternalReference_BOM,
+	})
+
+	return true
+}
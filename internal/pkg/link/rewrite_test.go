@@ -0,0 +1,89 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/link/rewrite_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package link_test
+
+import (
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
+)
+
+func (ls *linkSuite) TestRewriteReferences() {
+	ls.Run("rewrite", func() {
+		fetchedID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a01"
+		serialID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a02"
+		linkedID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a03"
+		appID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a00"
+
+		ls.storeDocument(fetchedID, &sbom.Node{Id: "fetched"})
+		ls.Require().NoError(ls.Backend.SetDocumentUniqueAnnotation(
+			fetchedID, db.SourceURLAnnotation, "https://example.com/fetched.cdx.json",
+		))
+
+		ls.storeDocument(appID,
+			&sbom.Node{Id: "app"},
+			&sbom.Node{Id: "app-fetched", ExternalReferences: []*sbom.ExternalReference{
+				{Type: sbom.ExternalReference_BOM, Url: "https://example.com/fetched.cdx.json"},
+				{Type: sbom.ExternalReference_WEBSITE, Url: "https://example.com"},
+			}},
+			&sbom.Node{Id: "app-serial", ExternalReferences: []*sbom.ExternalReference{
+				{Type: sbom.ExternalReference_BOM, Url: "urn:cdx:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a02/1#serial"},
+			}},
+			&sbom.Node{Id: "app-linked"},
+		)
+
+		ls.Require().NoError(ls.Backend.AddNodeAnnotations("app-linked", db.LinkToAnnotation, linkedID))
+		ls.Require().NoError(ls.Backend.AddDocumentAnnotations(appID, db.LinkToAnnotation, fetchedID))
+
+		urls := map[string]string{
+			fetchedID: "oci://registry.example.com/fetched.cdx.json",
+			serialID:  "oci://registry.example.com/serial.cdx.json",
+			linkedID:  "oci://registry.example.com/linked.cdx.json",
+		}
+
+		document, err := ls.Backend.GetDocumentByID(appID)
+		ls.Require().NoError(err)
+
+		rewritten, err := link.RewriteReferences(ls.Backend, document, urls)
+		ls.Require().NoError(err)
+		ls.True(rewritten)
+
+		references := map[string][]string{}
+
+		for _, node := range document.GetNodeList().GetNodes() {
+			for _, ref := range node.GetExternalReferences() {
+				references[node.GetId()] = append(references[node.GetId()], ref.GetUrl())
+			}
+		}
+
+		ls.Equal(map[string][]string{
+			"app":         {urls[fetchedID]},
+			"app-fetched": {urls[fetchedID], "https://example.com"},
+			"app-serial":  {urls[serialID]},
+			"app-linked":  {urls[linkedID]},
+		}, references)
+
+		// Rewriting again leaves the document as it is.
+		rewritten, err = link.RewriteReferences(ls.Backend, document, urls)
+		ls.Require().NoError(err)
+		ls.False(rewritten)
+	})
+}
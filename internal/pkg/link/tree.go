@@ -0,0 +1,148 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/link/tree.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package link
+
+import (
+	"fmt"
+
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+// LinkedDocuments returns the cached documents that document links to, through its document links, the links
+// of its nodes and the BOM external references of its nodes that resolve to a cached document. The URLs of BOM
+// external references that do not resolve to any cached document are returned as well. Links to documents
+// missing from the cache are skipped with a warning.
+func LinkedDocuments(
+	backend *db.Backend, document *sbom.Document, opts *options.LinkOptions,
+) ([]*sbom.Document, []string, error) {
+	linked, unresolved := []*sbom.Document{}, []string{}
+	seen := map[string]bool{document.GetMetadata().GetId(): true}
+
+	addLinked := func(documentID string) (bool, error) {
+		if seen[documentID] {
+			return true, nil
+		}
+
+		target, err := backend.GetDocumentByID(documentID)
+		if err != nil {
+			return false, fmt.Errorf("%w", err)
+		}
+
+		if target == nil {
+			return false, nil
+		}
+
+		seen[documentID] = true
+		linked = append(linked, target)
+
+		return true, nil
+	}
+
+	documentIDs, err := linkTargets(backend, document)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, documentID := range documentIDs {
+		found, err := addLinked(documentID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if !found {
+			opts.Logger.Warn("Linked document not found in cache", "from", document.GetMetadata().GetId(),
+				"to", documentID)
+		}
+	}
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		for _, ref := range node.GetExternalReferences() {
+			if ref.GetType() != sbom.ExternalReference_BOM {
+				continue
+			}
+
+			resolved, err := resolveReference(backend, ref.GetUrl(), addLinked)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if !resolved {
+				unresolved = append(unresolved, ref.GetUrl())
+			}
+		}
+	}
+
+	return linked, unresolved, nil
+}
+
+// linkTargets returns the IDs of the documents linked to by document and by its nodes.
+func linkTargets(backend *db.Backend, document *sbom.Document) ([]string, error) {
+	annotations, err := backend.GetDocumentAnnotations(document.GetMetadata().GetId(), db.LinkToAnnotation)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		nodeAnnotations, err := backend.GetNodeAnnotations(node.GetId(), db.LinkToAnnotation)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		annotations = append(annotations, nodeAnnotations...)
+	}
+
+	documentIDs := []string{}
+	for _, annotation := range annotations {
+		documentIDs = append(documentIDs, annotation.Value)
+	}
+
+	return documentIDs, nil
+}
+
+// resolveReference calls resolve with the IDs of the documents a BOM external reference URL may refer to, in
+// order of precedence, until one of them resolves. Those are the URL itself, the CycloneDX serial number it
+// references and the IDs of the documents fetched from it.
+func resolveReference(backend *db.Backend, url string, resolve func(documentID string) (bool, error)) (bool, error) {
+	documentIDs := []string{url}
+
+	if serial := serialNumber(url); serial != "" {
+		documentIDs = append(documentIDs, serial)
+	}
+
+	fetched, err := backend.GetDocumentsByAnnotation(db.SourceURLAnnotation, url)
+	if err != nil {
+		return false, fmt.Errorf("%w", err)
+	}
+
+	for _, document := range fetched {
+		documentIDs = append(documentIDs, document.GetMetadata().GetId())
+	}
+
+	for _, documentID := range documentIDs {
+		if resolved, err := resolve(documentID); err != nil || resolved {
+			return resolved, err
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,82 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/link/tree_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package link_test
+
+import (
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
+	"github.com/bomctl/bomctl/internal/pkg/logger"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+func (ls *linkSuite) TestLinkedDocuments() {
+	opts := &options.LinkOptions{Options: options.New().WithLogger(logger.New("link_tree_test"))}
+
+	ls.Run("linked", func() {
+		appID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a00"
+		libID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a01"
+		toolID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a02"
+		utilID := "urn:uuid:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a03"
+
+		ls.storeDocument(libID, &sbom.Node{Id: "lib"})
+		ls.storeDocument(toolID, &sbom.Node{Id: "tool"})
+		ls.storeDocument(utilID, &sbom.Node{Id: "util"})
+		ls.storeDocument(appID,
+			&sbom.Node{Id: "app"},
+			&sbom.Node{Id: "app-lib"},
+			&sbom.Node{Id: "app-tool", ExternalReferences: []*sbom.ExternalReference{
+				{Type: sbom.ExternalReference_BOM, Url: "urn:cdx:6c3f0f0e-0e5b-4bd4-9a5e-1c1a6f0b1a02/1#tool"},
+				{Type: sbom.ExternalReference_BOM, Url: "https://example.com/missing.cdx.json"},
+			}},
+		)
+
+		ls.Require().NoError(ls.Backend.AddDocumentAnnotations(appID, db.LinkToAnnotation, utilID))
+		ls.Require().NoError(ls.Backend.AddNodeAnnotations("app-lib", db.LinkToAnnotation, libID, utilID))
+
+		document, err := ls.Backend.GetDocumentByID(appID)
+		ls.Require().NoError(err)
+
+		linked, unresolved, err := link.LinkedDocuments(ls.Backend, document, opts)
+		ls.Require().NoError(err)
+
+		ls.Equal([]string{utilID, libID, toolID}, documentIDs(linked))
+		ls.Equal([]string{"https://example.com/missing.cdx.json"}, unresolved)
+
+		// Links to documents missing from the cache are skipped.
+		ls.Require().NoError(ls.Backend.AddNodeAnnotations("app-lib", db.LinkToAnnotation, "urn:uuid:missing"))
+
+		linked, unresolved, err = link.LinkedDocuments(ls.Backend, document, opts)
+		ls.Require().NoError(err)
+
+		ls.Equal([]string{utilID, libID, toolID}, documentIDs(linked))
+		ls.Equal([]string{"https://example.com/missing.cdx.json"}, unresolved)
+	})
+}
+
+func documentIDs(documents []*sbom.Document) []string {
+	ids := []string{}
+	for _, document := range documents {
+		ids = append(ids, document.GetMetadata().GetId())
+	}
+
+	return ids
+}
@@ -0,0 +1,337 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/list/list.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package list
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+const (
+	RevisionStatusLatest     = "latest"
+	RevisionStatusSuperseded = "superseded"
+)
+
+var (
+	errNotBool       = errors.New("filter must evaluate to a bool")
+	errUnknownColumn = errors.New("unknown column")
+
+	columns = []column{ //nolint:gochecknoglobals
+		{name: "id", header: "ID", value: func(r *Record) string { return r.ID }},
+		{name: "alias", header: "Alias", value: func(r *Record) string { return r.Alias }},
+		{name: "name", header: "Name", value: func(r *Record) string { return r.Name }},
+		{name: "version", header: "Version", value: func(r *Record) string { return r.Version }},
+		{name: "nodes", header: "# Nodes", value: func(r *Record) string { return strconv.Itoa(r.Nodes) }},
+		{name: "source_url", header: "Source URL", value: func(r *Record) string { return r.SourceURL }},
+		{name: "source_format", header: "Source Format", value: func(r *Record) string { return r.SourceFormat }},
+		{name: "tags", header: "Tags", value: func(r *Record) string { return strings.Join(r.Tags, ", ") }},
+		{name: "date", header: "Date", value: func(r *Record) string { return formatDate(r.Date) }},
+		{name: "revision_status", header: "Revision Status", value: func(r *Record) string { return r.RevisionStatus }},
+	}
+)
+
+type (
+	// Record holds the metadata and annotations of a document available to list filters, columns and templates.
+	Record struct {
+		Date           time.Time           `json:"date"`
+		Annotations    map[string][]string `json:"annotations"`
+		ID             string              `json:"id"`
+		Alias          string              `json:"alias"`
+		Name           string              `json:"name"`
+		Version        string              `json:"version"`
+		SourceURL      string              `json:"sourceUrl"`
+		SourceFormat   string              `json:"sourceFormat"`
+		RevisionStatus string              `json:"revisionStatus"`
+		Tags           []string            `json:"tags"`
+		Nodes          int                 `json:"nodes"`
+	}
+
+	column struct {
+		value  func(*Record) string
+		name   string
+		header string
+	}
+)
+
+// List returns the records of the documents with the specified IDs or aliases, or of all documents
+// if none are specified, filtered by opts.Tags and opts.Filter.
+func List(sbomIDs []string, opts *options.ListOptions) ([]*Record, error) {
+	var (
+		program cel.Program
+		err     error
+	)
+
+	if opts.Filter != "" {
+		if program, err = compileFilter(opts.Filter); err != nil {
+			return nil, err
+		}
+	}
+
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	documents, err := backend.GetDocumentsByIDOrAlias(sbomIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+
+	if len(opts.Tags) > 0 {
+		if documents, err = backend.FilterDocumentsByTag(documents, opts.Tags...); err != nil {
+			return nil, fmt.Errorf("failed to get documents: %w", err)
+		}
+	}
+
+	records := []*Record{}
+
+	for _, document := range documents {
+		record, err := NewRecord(document, backend)
+		if err != nil {
+			return nil, err
+		}
+
+		if program != nil {
+			matched, err := evalFilter(program, record)
+			if err != nil {
+				// Filters commonly fail on documents missing an annotation, which is treated as no match.
+				opts.Logger.Debug("Filter evaluation failed", "err", err)
+
+				continue
+			}
+
+			if !matched {
+				continue
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// NewRecord collects the metadata and annotations of a document.
+func NewRecord(document *sbom.Document, backend *db.Backend) (*Record, error) {
+	id := document.GetMetadata().GetId()
+
+	annotations, err := backend.GetDocumentAnnotations(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get annotations of %s: %w", id, err)
+	}
+
+	record := &Record{
+		ID:           id,
+		Name:         document.GetMetadata().GetName(),
+		Version:      document.GetMetadata().GetVersion(),
+		Nodes:        len(document.GetNodeList().GetNodes()),
+		SourceFormat: document.GetMetadata().GetSourceData().GetFormat(),
+		Tags:         []string{},
+		Annotations:  map[string][]string{},
+	}
+
+	if date := document.GetMetadata().GetDate(); date != nil && date.AsTime().Unix() > 0 {
+		record.Date = date.AsTime().UTC()
+	}
+
+	for _, annotation := range annotations {
+		// Skip annotations holding raw document content.
+		if annotation.Name == db.SourceDataAnnotation || annotation.Name == db.SourceHashAnnotation {
+			continue
+		}
+
+		record.Annotations[annotation.Name] = append(record.Annotations[annotation.Name], annotation.Value)
+
+		switch annotation.Name {
+		case db.AliasAnnotation:
+			record.Alias = annotation.Value
+		case db.SourceURLAnnotation:
+			record.SourceURL = annotation.Value
+		case db.TagAnnotation:
+			record.Tags = append(record.Tags, annotation.Value)
+		case db.LatestRevisionAnnotation:
+			record.RevisionStatus = RevisionStatusLatest
+		case db.RevisedDocumentAnnotation:
+			if record.RevisionStatus == "" {
+				record.RevisionStatus = RevisionStatusSuperseded
+			}
+		}
+	}
+
+	return record, nil
+}
+
+// ColumnNames returns the names of the columns that can be selected with Headers and Values.
+func ColumnNames() []string {
+	names := make([]string, len(columns))
+	for idx := range columns {
+		names[idx] = columns[idx].name
+	}
+
+	return names
+}
+
+// Headers returns the table headers of the named columns.
+func Headers(names ...string) ([]string, error) {
+	headers := []string{}
+
+	for _, name := range names {
+		col, err := findColumn(name)
+		if err != nil {
+			return nil, err
+		}
+
+		headers = append(headers, col.header)
+	}
+
+	return headers, nil
+}
+
+// Values returns the values of the named columns for the record.
+func (r *Record) Values(names ...string) ([]string, error) {
+	values := []string{}
+
+	for _, name := range names {
+		col, err := findColumn(name)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, col.value(r))
+	}
+
+	return values, nil
+}
+
+// WriteTemplate executes the Go template text once for each record. A newline is appended to the
+// output of each record if the template does not end with one.
+func WriteTemplate(out io.Writer, records []*Record, text string) error {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	tmpl, err := template.New("list").Funcs(template.FuncMap{
+		"join": func(values []string, sep string) string { return strings.Join(values, sep) },
+		"date": formatDate,
+	}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+
+	for _, record := range records {
+		if err := tmpl.Execute(out, record); err != nil {
+			return fmt.Errorf("failed to execute template: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func findColumn(name string) (*column, error) {
+	idx := slices.IndexFunc(columns, func(col column) bool { return col.name == strings.ToLower(name) })
+	if idx == -1 {
+		return nil, fmt.Errorf("%w: %s (expected one of %s)", errUnknownColumn, name, strings.Join(ColumnNames(), ", "))
+	}
+
+	return &columns[idx], nil
+}
+
+func compileFilter(expression string) (cel.Program, error) {
+	env, err := cel.NewEnv(
+		ext.Strings(),
+		cel.Variable("id", cel.StringType),
+		cel.Variable("alias", cel.StringType),
+		cel.Variable("name", cel.StringType),
+		cel.Variable("version", cel.StringType),
+		cel.Variable("nodes", cel.IntType),
+		cel.Variable("source_url", cel.StringType),
+		cel.Variable("source_format", cel.StringType),
+		cel.Variable("tags", cel.ListType(cel.StringType)),
+		cel.Variable("date", cel.TimestampType),
+		cel.Variable("revision_status", cel.StringType),
+		cel.Variable("annotations", cel.MapType(cel.StringType, cel.ListType(cel.StringType))),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create filter environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid filter: %w", issues.Err())
+	}
+
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("%w, got %s", errNotBool, ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	return program, nil
+}
+
+func evalFilter(program cel.Program, record *Record) (bool, error) {
+	out, _, err := program.Eval(map[string]any{
+		"id":              record.ID,
+		"alias":           record.Alias,
+		"name":            record.Name,
+		"version":         record.Version,
+		"nodes":           record.Nodes,
+		"source_url":      record.SourceURL,
+		"source_format":   record.SourceFormat,
+		"tags":            record.Tags,
+		"date":            record.Date,
+		"revision_status": record.RevisionStatus,
+		"annotations":     record.Annotations,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate filter for %s: %w", record.ID, err)
+	}
+
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("%w, got %s", errNotBool, out.Type())
+	}
+
+	return matched, nil
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format(time.RFC3339)
+}
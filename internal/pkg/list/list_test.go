@@ -0,0 +1,160 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/list/list_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package list_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/list"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+type listSuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	documentInfo []testutil.DocumentInfo
+}
+
+func (ls *listSuite) SetupSuite() {
+	var err error
+
+	ls.Backend, err = testutil.NewTestBackend()
+	ls.Require().NoError(err, "failed database backend creation")
+
+	ls.documentInfo, err = testutil.AddTestDocuments(ls.Backend)
+	ls.Require().NoError(err, "failed database backend setup")
+
+	ls.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, ls.Backend))
+}
+
+func (ls *listSuite) TearDownSuite() {
+	ls.Backend.CloseClient()
+}
+
+func (ls *listSuite) TestList() {
+	cdxID := ls.documentInfo[0].Document.GetMetadata().GetId()
+	spdxID := ls.documentInfo[1].Document.GetMetadata().GetId()
+
+	for _, subtest := range []struct {
+		name     string
+		filter   string
+		err      string
+		ids      []string
+		tags     []string
+		expected []string
+	}{
+		{name: "all", expected: []string{cdxID, spdxID}},
+		{name: "ids", ids: []string{"spdx"}, expected: []string{spdxID}},
+		{name: "tags", tags: []string{"tag1"}, expected: []string{cdxID}},
+		{name: "filter alias", filter: `alias == "cdx"`, expected: []string{cdxID}},
+		{name: "filter tags", filter: `"tag3" in tags`, expected: []string{spdxID}},
+		{name: "filter format", filter: `source_format.contains("spdx")`, expected: []string{spdxID}},
+		{name: "filter revision status", filter: `revision_status == "latest"`, expected: []string{cdxID, spdxID}},
+		{
+			name:     "filter annotations",
+			filter:   `annotations["bomctl_annotation_alias"] == ["spdx"]`,
+			expected: []string{spdxID},
+		},
+		{name: "filter nodes", filter: `nodes > 1000`, expected: []string{}},
+		{name: "filter combined with tags", filter: `nodes > 0`, tags: []string{"tag2"}, expected: []string{cdxID, spdxID}},
+		{name: "missing map key", filter: `annotations["missing"][0] == ""`, expected: []string{}},
+		{name: "not bool", filter: `alias`, err: "filter must evaluate to a bool, got string"},
+		{name: "invalid", filter: `alias ==`, err: "invalid filter: "},
+	} {
+		ls.Run(subtest.name, func() {
+			records, err := list.List(subtest.ids, &options.ListOptions{
+				Options: ls.Options,
+				Filter:  subtest.filter,
+				Tags:    subtest.tags,
+			})
+
+			if subtest.err != "" {
+				ls.Require().ErrorContains(err, subtest.err)
+
+				return
+			}
+
+			ls.Require().NoError(err)
+
+			ids := []string{}
+			for _, record := range records {
+				ids = append(ids, record.ID)
+			}
+
+			ls.Equal(subtest.expected, ids)
+		})
+	}
+}
+
+func (ls *listSuite) TestNewRecord() {
+	record, err := list.NewRecord(ls.documentInfo[0].Document, ls.Backend)
+	ls.Require().NoError(err)
+
+	ls.Equal("cdx", record.Alias)
+	ls.Equal([]string{"tag1", "tag2"}, record.Tags)
+	ls.Equal(list.RevisionStatusLatest, record.RevisionStatus)
+	ls.Equal(len(ls.documentInfo[0].Document.GetNodeList().GetNodes()), record.Nodes)
+	ls.Contains(record.SourceFormat, "cyclonedx")
+	ls.NotContains(record.Annotations, db.SourceDataAnnotation)
+	ls.NotContains(record.Annotations, db.SourceHashAnnotation)
+}
+
+func (ls *listSuite) TestColumns() {
+	headers, err := list.Headers("id", "Tags", "revision_status")
+	ls.Require().NoError(err)
+	ls.Equal([]string{"ID", "Tags", "Revision Status"}, headers)
+
+	_, err = list.Headers("bogus")
+	ls.Require().ErrorContains(err, "unknown column: bogus (expected one of id, alias, name")
+
+	record := &list.Record{ID: "id1", Alias: "one", Nodes: 3, Tags: []string{"a", "b"}}
+
+	values, err := record.Values("alias", "nodes", "tags", "date")
+	ls.Require().NoError(err)
+	ls.Equal([]string{"one", "3", "a, b", ""}, values)
+
+	ls.Len(list.ColumnNames(), 10)
+}
+
+func (ls *listSuite) TestWriteTemplate() {
+	records := []*list.Record{
+		{ID: "id1", Alias: "one", Tags: []string{"a", "b"}},
+		{ID: "id2", Tags: []string{}},
+	}
+
+	out := &bytes.Buffer{}
+	ls.Require().NoError(list.WriteTemplate(out, records, `{{.ID}} {{.Alias}} [{{join .Tags ","}}]{{date .Date}}`))
+	ls.Equal("id1 one [a,b]\nid2  []\n", out.String())
+
+	ls.Require().ErrorContains(list.WriteTemplate(out, records, `{{.ID`), "invalid template: ")
+	ls.Require().ErrorContains(list.WriteTemplate(out, records, `{{.Missing}}`), "failed to execute template: ")
+}
+
+func TestListSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(listSuite))
+}
@@ -23,11 +23,30 @@ import (
 	"os"
 
 	"github.com/charmbracelet/log"
+
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
 )
 
 const levelWidth = 5
 
+//nolint:gochecknoglobals
+var outputFormat = envelope.FormatText
+
+// SetOutputFormat sets the output format of loggers subsequently created by New. With a structured
+// format, log records are written to stderr as JSON lines and fatal errors to stdout as error envelopes.
+func SetOutputFormat(format string) {
+	outputFormat = format
+}
+
 func New(prefix string) *log.Logger {
+	if envelope.IsStructured(outputFormat) {
+		return log.NewWithOptions(&structuredWriter{format: outputFormat}, log.Options{
+			Prefix:    prefix,
+			Level:     log.GetLevel(),
+			Formatter: log.JSONFormatter,
+		})
+	}
+
 	// Set displayed width of log level in messages to show full level name
 	styles := log.DefaultStyles()
 	for _, level := range []log.Level{log.DebugLevel, log.ErrorLevel, log.FatalLevel, log.InfoLevel, log.WarnLevel} {
@@ -0,0 +1,66 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/logger/structured.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/bomctl/bomctl/internal/pkg/envelope"
+)
+
+// structuredWriter receives JSON-formatted log records. Fatal records are converted
+// to error envelopes on stdout; all other records are passed through to stderr.
+type structuredWriter struct {
+	format string
+}
+
+func (sw *structuredWriter) Write(record []byte) (int, error) {
+	fields := map[string]any{}
+
+	if err := json.Unmarshal(record, &fields); err != nil || fields[log.LevelKey] != log.FatalLevel.String() {
+		written, err := os.Stderr.Write(record)
+		if err != nil {
+			return written, fmt.Errorf("%w", err)
+		}
+
+		return written, nil
+	}
+
+	message, _ := fields[log.MessageKey].(string) //nolint:errcheck
+	source, _ := fields[log.PrefixKey].(string)   //nolint:errcheck
+
+	for _, key := range []string{log.TimestampKey, log.LevelKey, log.PrefixKey, log.MessageKey} {
+		delete(fields, key)
+	}
+
+	if len(fields) == 0 {
+		fields = nil
+	}
+
+	if err := envelope.NewError(source, message, fields).Write(os.Stdout, sw.format); err != nil {
+		return 0, fmt.Errorf("%w", err)
+	}
+
+	return len(record), nil
+}
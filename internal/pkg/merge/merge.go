@@ -141,6 +141,13 @@ func performTopLevelMerge(sbomIDs []string, documentMap map[string]*sbom.Documen
 		}
 	}
 
+	// The merged document has no original content, and is serialized in the format of the first document.
+	if merged.GetMetadata().GetSourceData().GetFormat() == "" && len(sbomIDs) > 0 {
+		merged.Metadata.SourceData = &sbom.SourceData{
+			Format: documentMap[sbomIDs[0]].GetMetadata().GetSourceData().GetFormat(),
+		}
+	}
+
 	return merged, nil
 }
 
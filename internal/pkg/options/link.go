@@ -30,8 +30,9 @@ type (
 	LinkTargetType uint8
 
 	LinkTarget struct {
-		ID, Alias string
-		Type      LinkTargetType
+		ID    string         `json:"id"`
+		Alias string         `json:"alias,omitempty"`
+		Type  LinkTargetType `json:"type"`
 	}
 
 	Link struct {
@@ -49,6 +50,10 @@ func (lt *LinkTarget) String() string {
 	return str
 }
 
+func (lt LinkTargetType) MarshalText() ([]byte, error) {
+	return []byte(lt.String()), nil
+}
+
 func (lt LinkTargetType) String() string {
 	switch lt {
 	case LinkTargetTypeDocument:
@@ -21,6 +21,7 @@ package options //revive:disable:max-public-structs
 
 import (
 	"context"
+	"crypto"
 	"os"
 
 	"github.com/charmbracelet/log"
@@ -31,11 +32,12 @@ import (
 
 type (
 	Options struct {
-		Logger     *log.Logger
-		ctx        context.Context
-		CacheDir   string
-		ConfigFile string
-		Verbosity  int
+		Logger       *log.Logger
+		ctx          context.Context
+		CacheDir     string
+		ConfigFile   string
+		OutputFormat string
+		Verbosity    int
 	}
 
 	Option func(*Options)
@@ -45,18 +47,42 @@ type (
 		Force bool
 	}
 
+	BrowseOptions struct {
+		*Options
+		Tags []string
+	}
+
+	DiffOptions struct {
+		*Options
+		OutputFile *os.File
+		Format     string
+		Revisions  []int
+	}
+
+	EnrichOptions struct {
+		*Options
+		TEAURL   string
+		UseNetRC bool
+	}
+
 	ExportOptions struct {
 		*Options
 		OutputFile *os.File
+		Signer     crypto.Signer
 		Format     formats.Format
+		GUACDir    string
+		Attest     bool
 	}
 
 	FetchOptions struct {
 		*Options
-		OutputFile *os.File
-		Alias      string
-		Tags       []string
-		UseNetRC   bool
+		OutputFile       *os.File
+		Alias            string
+		Tags             []string
+		TrustedKeys      []string
+		AutoLink         []string
+		RequireSignature bool
+		UseNetRC         bool
 	}
 
 	ImportOptions struct {
@@ -64,11 +90,22 @@ type (
 		InputFiles []*os.File
 		Alias      []string
 		Tags       []string
+		AutoLink   []string
 	}
 
 	LinkOptions struct {
 		*Options
-		Links []Link
+		Links      []Link
+		Strategies []string
+		Fix        bool
+	}
+
+	ListOptions struct {
+		*Options
+		Filter   string
+		Template string
+		Columns  []string
+		Tags     []string
 	}
 
 	MergeOptions struct {
@@ -80,9 +117,70 @@ type (
 
 	PushOptions struct {
 		*Options
-		Format   formats.Format
-		UseTree  bool
-		UseNetRC bool
+		Signer        crypto.Signer
+		ReferenceURLs map[string]string
+		Format        formats.Format
+		Attest        bool
+		UseTree       bool
+		UseNetRC      bool
+	}
+
+	QueryOptions struct {
+		*Options
+		OutputFile *os.File
+		Format     string
+		Tags       []string
+	}
+
+	RedactOptions struct {
+		*Options
+		Replacement string
+		Fields      []string
+		Patterns    []string
+	}
+
+	ServeOptions struct {
+		*Options
+		Host string
+		Port int
+		UI   bool
+	}
+
+	SignOptions struct {
+		*Options
+		KeyPath string
+		Bundle  bool
+	}
+
+	SplitOptions struct {
+		*Options
+		PurlTypes []string
+		Selectors []string
+		Tags      []string
+		TopLevel  bool
+	}
+
+	TrimOptions struct {
+		*Options
+		NodeIDs   []string
+		PurlTypes []string
+		MaxDepth  int
+	}
+
+	VerifyOptions struct {
+		*Options
+		BundlePath    string
+		SignaturePath string
+		KeyPaths      []string
+	}
+
+	VisualizeOptions struct {
+		*Options
+		OutputFile    *os.File
+		Format        string
+		CollapseTypes []string
+		Highlight     []string
+		MaxDepth      int
 	}
 )
 
@@ -127,6 +225,12 @@ func (o *Options) WithLogger(l *log.Logger) *Options {
 	return o
 }
 
+func (o *Options) WithOutputFormat(format string) *Options {
+	o.OutputFormat = format
+
+	return o
+}
+
 func (o *Options) WithVerbosity(level int) *Options {
 	o.Verbosity = level
 
@@ -157,6 +261,12 @@ func WithLogger(l *log.Logger) Option {
 	}
 }
 
+func WithOutputFormat(format string) Option {
+	return func(o *Options) {
+		o.WithOutputFormat(format)
+	}
+}
+
 func WithVerbosity(level int) Option {
 	return func(o *Options) {
 		o.WithVerbosity(level)
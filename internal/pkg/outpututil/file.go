@@ -20,6 +20,7 @@
 package outpututil
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"os"
@@ -32,6 +33,16 @@ import (
 	"github.com/bomctl/bomctl/internal/pkg/options"
 )
 
+// BufferWriter is a bytes.Buffer that can be written to as an io.WriteCloser.
+type BufferWriter struct {
+	*bytes.Buffer
+}
+
+// Close does nothing, as there is nothing to release.
+func (*BufferWriter) Close() error {
+	return nil
+}
+
 func checkIfModified(document *sbom.Document, backend *db.Backend) (bool, error) {
 	sourceContent, err := backend.GetDocumentUniqueAnnotation(document.GetMetadata().GetId(), db.SourceDataAnnotation)
 	if err != nil {
@@ -69,20 +80,47 @@ func WriteStream(document *sbom.Document, format formats.Format, opts *options.O
 		return fmt.Errorf("%w", err)
 	}
 
+	// Documents without source data, such as merged documents, are written in their source format.
+	if sourceFormat := document.GetMetadata().GetSourceData().GetFormat(); format == db.OriginalFormat && modified &&
+		sourceFormat != "" {
+		format = formats.Format(sourceFormat)
+	}
+
 	if format == db.OriginalFormat || (!modified && matchesOriginFormat(document, format)) {
 		return writeOriginStream(document, backend, stream)
 	}
 
+	return SerializeStream(document, format, stream)
+}
+
+// SerializeStream writes document to stream serialized in format, rather than as the original content stored
+// with it, for documents changed after they were read from the cache. The original format is resolved to the
+// source format of document.
+func SerializeStream(document *sbom.Document, format formats.Format, stream io.WriteCloser) error {
+	if format == db.OriginalFormat {
+		format = formats.Format(document.GetMetadata().GetSourceData().GetFormat())
+	}
+
 	wrtr := writer.New(writer.WithFormat(format))
 
-	err = wrtr.WriteStream(document, stream)
-	if err != nil {
+	if err := wrtr.WriteStream(document, stream); err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
 	return nil
 }
 
+// Serialize returns document serialized in its source format.
+func Serialize(document *sbom.Document) ([]byte, error) {
+	out := &BufferWriter{Buffer: &bytes.Buffer{}}
+
+	if err := SerializeStream(document, db.OriginalFormat, out); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
 func WriteFile(document *sbom.Document, format formats.Format, opts *options.Options, filePath string) error {
 	file, err := os.Create(filePath)
 	if err != nil {
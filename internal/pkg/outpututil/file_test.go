@@ -227,6 +227,25 @@ func (fs *fileSuite) TestWriteStream() {
 	}
 }
 
+func (fs *fileSuite) TestWriteStreamModifiedOriginal() {
+	tempbe, err := testutil.NewTestBackend()
+	fs.Require().NoError(err, "failed database backend creation")
+
+	tempdocs, err := testutil.AddTestDocuments(tempbe)
+	fs.Require().NoError(err, "failed database backend setup")
+
+	defer tempbe.CloseClient()
+
+	document := tempdocs[0].Document
+	fs.Require().NoError(tempbe.ClearDocumentAnnotations(document.GetMetadata().GetId()))
+
+	opts := options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, tempbe))
+	stream := testutil.TestWriter{Buffer: &bytes.Buffer{}}
+
+	fs.Require().NoError(outpututil.WriteStream(document, db.OriginalFormat, opts, &stream))
+	fs.Contains(stream.String(), `"bomFormat": "CycloneDX"`)
+}
+
 func (fs *fileSuite) TestWriteFile() {
 	for _, data := range []struct {
 		name string
@@ -22,23 +22,14 @@ package outpututil
 import (
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	lgtable "github.com/charmbracelet/lipgloss/table"
-	"github.com/protobom/protobom/pkg/sbom"
 	"golang.org/x/term"
-
-	"github.com/bomctl/bomctl/internal/pkg/db"
 )
 
 const (
-	columnIdxID = iota
-	columnIdxAlias
-	columnIdxVersion
-	columnIdxNumNodes
-
 	columnNameID       = "ID"
 	columnNameAlias    = "Alias"
 	columnNameVersion  = "Version"
@@ -57,7 +48,8 @@ const (
 	rowHeaderIdx = -1
 	rowMaxHeight = 1
 
-	totalColumnCount = 4
+	// Minimum width of the field names in list format.
+	listNameWidth = 8
 )
 
 type (
@@ -66,21 +58,18 @@ type (
 		width int
 	}
 
-	rowData struct {
-		id, alias, version, numNodes string
-	}
-
 	Table struct {
 		columns []columnDefinition
-		rows    []rowData
+		rows    [][]string
 		asList  bool
 	}
 
 	TableOption func(*Table)
 )
 
-func (t *Table) AddRow(doc *sbom.Document, backend *db.Backend) {
-	t.rows = append(t.rows, newRow(doc, backend))
+// AddRow adds a row with a value for each column of the table.
+func (t *Table) AddRow(values ...string) {
+	t.rows = append(t.rows, values)
 }
 
 func (t *Table) String() string {
@@ -96,26 +85,30 @@ func (t *Table) canFit() bool {
 	padding := paddingHorizontal * cellSideCount
 
 	for _, row := range t.rows {
-		t.columns[columnIdxID].width = max(t.columns[columnIdxID].width, len(row.id)+padding)
-		t.columns[columnIdxAlias].width = max(t.columns[columnIdxAlias].width, len(row.alias)+padding)
-		t.columns[columnIdxVersion].width = max(t.columns[columnIdxVersion].width, len(row.version)+padding)
-		t.columns[columnIdxNumNodes].width = max(t.columns[columnIdxNumNodes].width, len(row.numNodes)+padding)
+		for idx := range t.columns {
+			t.columns[idx].width = max(t.columns[idx].width, len(row[idx])+padding)
+		}
 	}
 
 	return t.getTableWidth() < terminalWidth
 }
 
 func (t *Table) formatList() string {
+	nameWidth := listNameWidth
+	for _, column := range t.columns {
+		nameWidth = max(nameWidth, len(column.name)+1)
+	}
+
 	output := ""
+
 	for _, row := range t.rows {
-		output = strings.Join([]string{
-			output,
-			fmt.Sprintf("%-8s: %s", columnNameID, row.id),
-			fmt.Sprintf("%-8s: %s", columnNameAlias, row.alias),
-			fmt.Sprintf("%-8s: %s", columnNameVersion, row.version),
-			fmt.Sprintf("%-8s: %s", columnNameNumNodes, row.numNodes),
-			"",
-		}, "\n")
+		lines := []string{output}
+
+		for idx, column := range t.columns {
+			lines = append(lines, fmt.Sprintf("%-*s: %s", nameWidth, column.name, row[idx]))
+		}
+
+		output = strings.Join(append(lines, ""), "\n")
 	}
 
 	return output
@@ -128,7 +121,7 @@ func (t *Table) formatTable() string {
 
 	return lgtable.New().
 		Headers(t.getHeaders()...).
-		Rows(t.getRows()...).
+		Rows(t.rows...).
 		BorderTop(false).
 		BorderBottom(false).
 		BorderLeft(false).
@@ -136,25 +129,13 @@ func (t *Table) formatTable() string {
 		BorderHeader(true).
 		StyleFunc(func(row, col int) lipgloss.Style {
 			align := lipgloss.Center
-			width := 0
-			columns := t.columns
-
-			switch col {
-			case columnIdxID:
-				width = columns[columnIdxID].width
-
-				if row != rowHeaderIdx {
-					align = lipgloss.Left
-				}
-			case columnIdxAlias:
-				width = columns[columnIdxAlias].width
-			case columnIdxVersion:
-				width = columns[columnIdxVersion].width
-			case columnIdxNumNodes:
-				width = columns[columnIdxNumNodes].width
+
+			// The first column holds document IDs, which are left aligned.
+			if col == 0 && row != rowHeaderIdx {
+				align = lipgloss.Left
 			}
 
-			return commonStyle.Width(width).AlignHorizontal(align)
+			return commonStyle.Width(t.columns[col].width).AlignHorizontal(align)
 		}).
 		Render()
 }
@@ -162,51 +143,33 @@ func (t *Table) formatTable() string {
 func (t *Table) getHeaders() []string {
 	headers := []string{}
 
-	for c := range totalColumnCount {
-		headers = append(headers, t.columns[c].name)
+	for _, column := range t.columns {
+		headers = append(headers, column.name)
 	}
 
 	return headers
 }
 
-func (t *Table) getRows() [][]string {
-	rows := [][]string{}
-
-	for _, row := range t.rows {
-		rows = append(rows, []string{row.id, row.alias, row.version, row.numNodes})
-	}
-
-	return rows
-}
-
 func (t *Table) getTableWidth() int {
-	totalWidth := totalColumnCount * (paddingHorizontal * cellSideCount)
+	totalWidth := len(t.columns) * (paddingHorizontal * cellSideCount)
 
-	for c := range totalColumnCount {
-		totalWidth += t.columns[c].width
+	for _, column := range t.columns {
+		totalWidth += column.width
 	}
 
 	return totalWidth
 }
 
+// NewTable creates a Table with the ID, Alias, Version and # Nodes columns, unless
+// other columns are specified with WithColumns.
 func NewTable(opts ...TableOption) *Table {
-	table := &Table{}
-
-	for idx := range totalColumnCount {
-		var column columnDefinition
-
-		switch idx {
-		case columnIdxID:
-			column.name, column.width = columnNameID, columnWidthID
-		case columnIdxAlias:
-			column.name, column.width = columnNameAlias, columnWidthAlias
-		case columnIdxVersion:
-			column.name, column.width = columnNameVersion, columnWidthVersion
-		case columnIdxNumNodes:
-			column.name, column.width = columnNameNumNodes, columnWidthNumNodes
-		}
-
-		table.columns = append(table.columns, column)
+	table := &Table{
+		columns: []columnDefinition{
+			{name: columnNameID, width: columnWidthID},
+			{name: columnNameAlias, width: columnWidthAlias},
+			{name: columnNameVersion, width: columnWidthVersion},
+			{name: columnNameNumNodes, width: columnWidthNumNodes},
+		},
 	}
 
 	for _, opt := range opts {
@@ -216,22 +179,6 @@ func NewTable(opts ...TableOption) *Table {
 	return table
 }
 
-func newRow(doc *sbom.Document, backend *db.Backend) rowData {
-	id := doc.GetMetadata().GetId()
-
-	alias, err := backend.GetDocumentUniqueAnnotation(id, db.AliasAnnotation)
-	if err != nil {
-		backend.Logger.Fatalf("failed to get alias: %v", err)
-	}
-
-	return rowData{
-		id:       id,
-		alias:    alias,
-		version:  doc.GetMetadata().GetVersion(),
-		numNodes: strconv.Itoa(len(doc.GetNodeList().GetNodes())),
-	}
-}
-
 func termInfo() int {
 	fd := int(os.Stdout.Fd())
 
@@ -243,6 +190,17 @@ func termInfo() int {
 	return width
 }
 
+// WithColumns sets the column headers of the table.
+func WithColumns(names ...string) TableOption {
+	return func(t *Table) {
+		t.columns = make([]columnDefinition, len(names))
+
+		for idx, name := range names {
+			t.columns[idx] = columnDefinition{name: name, width: len(name) + paddingHorizontal*cellSideCount}
+		}
+	}
+}
+
 func WithListFormat() TableOption {
 	return func(t *Table) {
 		t.asList = true
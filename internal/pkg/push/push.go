@@ -20,6 +20,7 @@
 package push
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -34,10 +35,13 @@ import (
 	"github.com/bomctl/bomctl/internal/pkg/client/oci"
 	"github.com/bomctl/bomctl/internal/pkg/db"
 	"github.com/bomctl/bomctl/internal/pkg/fetch"
+	"github.com/bomctl/bomctl/internal/pkg/link"
 	"github.com/bomctl/bomctl/internal/pkg/options"
 	"github.com/bomctl/bomctl/internal/pkg/sliceutil"
 )
 
+var errDocumentNotFound = errors.New("document not found")
+
 func NewPusher(url string) (client.Pusher, error) {
 	clients := []client.Pusher{&gitlab.Client{}, &github.Client{}, &git.Client{}, &http.Client{}, &oci.Client{}}
 
@@ -64,93 +68,109 @@ func Push(sbomID, pushURL string, opts *options.PushOptions) error {
 		return fmt.Errorf("%w", err)
 	}
 
-	if err := pushClient.AddFile(pushURL, sbomID, opts); err != nil {
-		return fmt.Errorf("%w", err)
-	}
+	// Recurse the SBOM tree, so that references to the documents in it are rewritten as they are added.
+	tree := []*sbom.Document{}
 
-	// Recurse the SBOM tree and push all.
 	if opts.UseTree {
-		if err := addExternalReferenceFiles(sbomID, pushURL, pushClient, opts); err != nil {
+		if tree, err = resolveTree(sbomID, pushURL, opts); err != nil {
 			return err
 		}
 	}
 
-	// Finalize and push.
-	if err := pushClient.Push(pushURL, opts); err != nil {
+	if err := pushClient.AddFile(pushURL, sbomID, opts); err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
-	return nil
-}
+	for _, document := range tree {
+		id := document.GetMetadata().GetId()
 
-func addExternalReferenceFiles(sbomID, pushURL string, pushClient client.Pusher, opts *options.PushOptions) error {
-	extRefs, err := getExternalReferences(sbomID, opts)
-	if err != nil {
-		return fmt.Errorf("%w", err)
+		if err := pushClient.AddFile(opts.ReferenceURLs[id], id, opts); err != nil {
+			return fmt.Errorf("%w", err)
+		}
 	}
 
-	extRefDocs, err := resolveExternalReferences(extRefs, opts)
-	if err != nil {
+	// Finalize and push.
+	if err := pushClient.Push(pushURL, opts); err != nil {
 		return fmt.Errorf("%w", err)
 	}
 
-	for _, document := range extRefDocs {
-		extRefURL := getExtRefPath(pushURL, document.GetMetadata().GetId(), document.GetMetadata().GetName(), opts)
-
-		if err := pushClient.AddFile(extRefURL, document.GetMetadata().GetId(), opts); err != nil {
-			return fmt.Errorf("%w", err)
-		}
-	}
-
 	return nil
 }
 
-func getExternalReferences(sbomID string, opts *options.PushOptions) ([]*sbom.ExternalReference, error) {
-	opts.Logger.Info("Fetching external reference SBOMs", "id", sbomID)
+// resolveTree returns the documents in the tree linked from the document with the specified ID, and records the
+// destination URL of each in opts. The tree is traversed through the document and node links in the cache and
+// the BOM external references resolving to cached documents. Only documents referenced by a BOM external
+// reference but missing from the cache are fetched.
+func resolveTree(sbomID, pushURL string, opts *options.PushOptions) ([]*sbom.Document, error) {
+	opts.Logger.Info("Resolving linked SBOMs", "id", sbomID)
 
 	backend, err := db.BackendFromContext(opts.Context())
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
 
-	extRefs, err := backend.GetExternalReferencesByDocumentID(sbomID, "BOM")
+	root, err := backend.GetDocumentByID(sbomID)
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
 
-	return extRefs, nil
-}
+	if root == nil {
+		return nil, fmt.Errorf("%w: %s", errDocumentNotFound, sbomID)
+	}
 
-func resolveExternalReferences(refs []*sbom.ExternalReference, opts *options.PushOptions) ([]*sbom.Document, error) {
-	documents := []*sbom.Document{}
+	opts.ReferenceURLs = map[string]string{}
 
-	fetchOpts := &options.FetchOptions{
-		UseNetRC: opts.UseNetRC,
-		Options:  opts.Options,
-	}
+	tree := []*sbom.Document{}
+	visited := map[string]bool{sbomID: true}
 
-	for _, ref := range refs {
-		document, err := fetch.Fetch(ref.GetUrl(), fetchOpts)
+	for queue := []*sbom.Document{root}; len(queue) > 0; queue = queue[1:] {
+		linked, err := linkedDocuments(backend, queue[0], opts)
 		if err != nil {
-			return nil, fmt.Errorf("fetching external reference document: %w", err)
+			return nil, err
 		}
 
-		documents = append(documents, document)
+		for _, document := range linked {
+			id := document.GetMetadata().GetId()
+			if visited[id] {
+				continue
+			}
 
-		extRefs, err := getExternalReferences(document.GetMetadata().GetId(), opts)
-		if err != nil {
-			return nil, fmt.Errorf("%w", err)
+			visited[id] = true
+			tree = append(tree, document)
+			queue = append(queue, document)
+
+			opts.ReferenceURLs[id] = getExtRefPath(pushURL, id, document.GetMetadata().GetName(), opts)
 		}
+	}
+
+	return tree, nil
+}
 
-		extRefDocs, err := resolveExternalReferences(extRefs, opts)
+func linkedDocuments(
+	backend *db.Backend, document *sbom.Document, opts *options.PushOptions,
+) ([]*sbom.Document, error) {
+	linked, unresolved, err := link.LinkedDocuments(backend, document, &options.LinkOptions{Options: opts.Options})
+	if err != nil {
+		return nil, fmt.Errorf("resolving documents linked from %s: %w", document.GetMetadata().GetId(), err)
+	}
+
+	fetchOpts := &options.FetchOptions{
+		UseNetRC: opts.UseNetRC,
+		Options:  opts.Options,
+	}
+
+	for _, url := range unresolved {
+		opts.Logger.Info("Fetching external reference SBOM", "url", url)
+
+		fetched, err := fetch.Fetch(url, fetchOpts)
 		if err != nil {
-			return nil, fmt.Errorf("%w", err)
+			return nil, fmt.Errorf("fetching external reference document: %w", err)
 		}
 
-		documents = append(documents, extRefDocs...)
+		linked = append(linked, fetched)
 	}
 
-	return documents, nil
+	return linked, nil
 }
 
 // generate destination path to push to based on what
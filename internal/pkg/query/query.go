@@ -0,0 +1,255 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/query/query.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/ext"
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/sliceutil"
+	"github.com/bomctl/bomctl/internal/pkg/versionutil"
+)
+
+var errNotBool = errors.New("expression must evaluate to a bool")
+
+type (
+	// Result is a node that matched a query expression.
+	Result struct {
+		DocumentID    string   `json:"documentId"`
+		DocumentAlias string   `json:"documentAlias,omitempty"`
+		NodeID        string   `json:"nodeId"`
+		Name          string   `json:"name"`
+		Version       string   `json:"version"`
+		Purl          string   `json:"purl"`
+		Licenses      []string `json:"licenses"`
+	}
+
+	// Program is a compiled query expression.
+	Program struct {
+		program cel.Program
+	}
+
+	documentVars struct {
+		id, name, alias string
+		tags            []string
+	}
+)
+
+// Query evaluates the expression against every node of the documents with the specified IDs or aliases,
+// or of all documents in the cache if none are specified, and returns the matching nodes.
+func Query(expression string, sbomIDs []string, opts *options.QueryOptions) ([]Result, error) {
+	program, err := Compile(expression)
+	if err != nil {
+		return nil, err
+	}
+
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	documents, err := backend.GetDocumentsByIDOrAlias(sbomIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if len(opts.Tags) > 0 {
+		if documents, err = backend.FilterDocumentsByTag(documents, opts.Tags...); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	results := []Result{}
+
+	for _, document := range documents {
+		id := document.GetMetadata().GetId()
+
+		tags, err := backend.GetDocumentTags(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		docVars := &documentVars{
+			id:    id,
+			name:  document.GetMetadata().GetName(),
+			alias: backend.GetDocumentAlias(id),
+			tags:  tags,
+		}
+
+		for _, node := range document.GetNodeList().GetNodes() {
+			matched, err := program.match(node, docVars)
+			if err != nil {
+				// Expressions commonly fail on nodes missing a map key or field, which is treated as no match.
+				opts.Logger.Debug("Expression evaluation failed", "document", id, "node", node.GetId(), "err", err)
+
+				continue
+			}
+
+			if matched {
+				results = append(results, Result{
+					DocumentID:    id,
+					DocumentAlias: docVars.alias,
+					NodeID:        node.GetId(),
+					Name:          node.GetName(),
+					Version:       node.GetVersion(),
+					Purl:          string(node.Purl()),
+					Licenses:      append([]string{}, node.GetLicenses()...),
+				})
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// Compile parses and checks a CEL expression. The expression must evaluate to a bool and may reference
+// the node fields id, name, version, node_type, purl, description, licenses, license_concluded, suppliers,
+// originators, url_home, url_download, hashes and identifiers, and the document fields document_id,
+// document_name, document_alias and document_tags. The function compareVersions(a, b) compares two
+// version strings, returning -1, 0 or 1.
+func Compile(expression string) (*Program, error) {
+	env, err := cel.NewEnv(
+		ext.Strings(),
+		cel.Variable("id", cel.StringType),
+		cel.Variable("name", cel.StringType),
+		cel.Variable("version", cel.StringType),
+		cel.Variable("node_type", cel.StringType),
+		cel.Variable("purl", cel.StringType),
+		cel.Variable("description", cel.StringType),
+		cel.Variable("licenses", cel.ListType(cel.StringType)),
+		cel.Variable("license_concluded", cel.StringType),
+		cel.Variable("suppliers", cel.ListType(cel.StringType)),
+		cel.Variable("originators", cel.ListType(cel.StringType)),
+		cel.Variable("url_home", cel.StringType),
+		cel.Variable("url_download", cel.StringType),
+		cel.Variable("hashes", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("identifiers", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("document_id", cel.StringType),
+		cel.Variable("document_name", cel.StringType),
+		cel.Variable("document_alias", cel.StringType),
+		cel.Variable("document_tags", cel.ListType(cel.StringType)),
+		cel.Function("compareVersions",
+			cel.Overload("compareVersions_string_string", []*cel.Type{cel.StringType, cel.StringType}, cel.IntType,
+				cel.BinaryBinding(compareVersions),
+			),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create expression environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("invalid expression: %w", issues.Err())
+	}
+
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("%w, got %s", errNotBool, ast.OutputType())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression: %w", err)
+	}
+
+	return &Program{program: program}, nil
+}
+
+// Match reports whether the node matches the compiled expression.
+func (p *Program) Match(node *sbom.Node) (bool, error) {
+	return p.match(node, &documentVars{})
+}
+
+func (p *Program) match(node *sbom.Node, document *documentVars) (bool, error) {
+	out, _, err := p.program.Eval(map[string]any{
+		"id":                node.GetId(),
+		"name":              node.GetName(),
+		"version":           node.GetVersion(),
+		"node_type":         strings.ToLower(node.GetType().String()),
+		"purl":              string(node.Purl()),
+		"description":       node.GetDescription(),
+		"licenses":          nonNil(node.GetLicenses()),
+		"license_concluded": node.GetLicenseConcluded(),
+		"suppliers":         sliceutil.Extract(node.GetSuppliers(), (*sbom.Person).GetName),
+		"originators":       sliceutil.Extract(node.GetOriginators(), (*sbom.Person).GetName),
+		"url_home":          node.GetUrlHome(),
+		"url_download":      node.GetUrlDownload(),
+		"hashes": keyNames(node.GetHashes(), func(key int32) string {
+			return sbom.HashAlgorithm(key).String()
+		}),
+		"identifiers": keyNames(node.GetIdentifiers(), func(key int32) string {
+			return sbom.SoftwareIdentifierType(key).String()
+		}),
+		"document_id":    document.id,
+		"document_name":  document.name,
+		"document_alias": document.alias,
+		"document_tags":  nonNil(document.tags),
+	})
+	if err != nil {
+		return false, fmt.Errorf("%w", err)
+	}
+
+	matched, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("%w, got %s", errNotBool, out.Type())
+	}
+
+	return matched, nil
+}
+
+// compareVersions implements the compareVersions CEL function. The type checker guarantees both arguments are strings.
+func compareVersions(a, b ref.Val) ref.Val {
+	versionA, okA := a.Value().(string)
+	versionB, okB := b.Value().(string)
+
+	if !okA || !okB {
+		return types.NewErr("compareVersions: expected string arguments")
+	}
+
+	return types.Int(versionutil.Compare(versionA, versionB))
+}
+
+// keyNames converts a protobom enum-keyed map to one keyed by lowercase enum names.
+func keyNames(values map[int32]string, name func(int32) string) map[string]string {
+	named := map[string]string{}
+
+	for key, value := range values {
+		named[strings.ToLower(name(key))] = value
+	}
+
+	return named
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+
+	return values
+}
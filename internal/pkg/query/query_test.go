@@ -0,0 +1,173 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/query/query_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package query_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/query"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+type querySuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	documentInfo []testutil.DocumentInfo
+}
+
+func (qs *querySuite) SetupSuite() {
+	var err error
+
+	qs.Backend, err = testutil.NewTestBackend()
+	qs.Require().NoError(err, "failed database backend creation")
+
+	qs.documentInfo, err = testutil.AddTestDocuments(qs.Backend)
+	qs.Require().NoError(err, "failed database backend setup")
+
+	qs.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, qs.Backend))
+}
+
+func (qs *querySuite) TearDownSuite() {
+	qs.Backend.CloseClient()
+}
+
+func (qs *querySuite) TestCompile() {
+	for _, subtest := range []struct {
+		expression string
+		err        string
+	}{
+		{expression: `name == "log4j-core"`},
+		{expression: `compareVersions(version, "2.17.0") < 0 && "MIT" in licenses`},
+		{expression: `name`, err: "expression must evaluate to a bool, got string"},
+		{expression: `nme == ""`, err: "invalid expression: ERROR: <input>:1:1: undeclared reference to 'nme'"},
+	} {
+		qs.Run(subtest.expression, func() {
+			_, err := query.Compile(subtest.expression)
+			if subtest.err == "" {
+				qs.Require().NoError(err)
+			} else {
+				qs.Require().ErrorContains(err, subtest.err)
+			}
+		})
+	}
+}
+
+func (qs *querySuite) TestProgram_Match() {
+	node := &sbom.Node{
+		Id:        "log4j",
+		Type:      sbom.Node_PACKAGE,
+		Name:      "log4j-core",
+		Version:   "2.14.1",
+		Licenses:  []string{"Apache-2.0"},
+		Suppliers: []*sbom.Person{{Name: "Apache Software Foundation"}},
+		Identifiers: map[int32]string{
+			int32(sbom.SoftwareIdentifierType_PURL): "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+		},
+		Hashes: map[int32]string{int32(sbom.HashAlgorithm_SHA256): "abc123"},
+	}
+
+	for _, subtest := range []struct {
+		expression string
+		expected   bool
+	}{
+		{expression: `name == "log4j-core" && compareVersions(version, "2.17.0") < 0`, expected: true},
+		{expression: `name == "log4j-core" && compareVersions(version, "2.14.1") > 0`, expected: false},
+		{expression: `purl.startsWith("pkg:maven/org.apache.logging.log4j/")`, expected: true},
+		{expression: `identifiers["purl"] == purl`, expected: true},
+		{expression: `"Apache-2.0" in licenses && node_type == "package"`, expected: true},
+		{expression: `suppliers.exists(s, s.lowerAscii().contains("apache"))`, expected: true},
+		{expression: `hashes["sha256"] == "abc123" && !("md5" in hashes)`, expected: true},
+	} {
+		qs.Run(subtest.expression, func() {
+			program, err := query.Compile(subtest.expression)
+			qs.Require().NoError(err)
+
+			matched, err := program.Match(node)
+			qs.Require().NoError(err)
+			qs.Equal(subtest.expected, matched)
+		})
+	}
+
+	program, err := query.Compile(`hashes["md5"] == ""`)
+	qs.Require().NoError(err)
+
+	_, err = program.Match(node)
+	qs.Require().Error(err)
+}
+
+func (qs *querySuite) TestQuery() {
+	opts := &options.QueryOptions{Options: qs.Options}
+
+	results, err := query.Query(`purl != ""`, nil, opts)
+	qs.Require().NoError(err)
+
+	expected := 0
+
+	for _, info := range qs.documentInfo {
+		for _, node := range info.Document.GetNodeList().GetNodes() {
+			if node.Purl() != "" {
+				expected++
+			}
+		}
+	}
+
+	qs.Len(results, expected)
+
+	// Nodes failing evaluation are not matched.
+	results, err = query.Query(`hashes["unknown"] != ""`, nil, opts)
+	qs.Require().NoError(err)
+	qs.Empty(results)
+
+	results, err = query.Query(`document_alias == "spdx" && "tag3" in document_tags`, []string{"cdx", "spdx"}, opts)
+	qs.Require().NoError(err)
+	qs.Len(results, len(qs.documentInfo[1].Document.GetNodeList().GetNodes()))
+
+	for _, result := range results {
+		qs.Equal(qs.documentInfo[1].Document.GetMetadata().GetId(), result.DocumentID)
+		qs.Equal("spdx", result.DocumentAlias)
+	}
+
+	results, err = query.Query(fmt.Sprintf("document_id == %q", qs.documentInfo[0].Document.GetMetadata().GetId()),
+		nil, opts)
+	qs.Require().NoError(err)
+	qs.Len(results, len(qs.documentInfo[0].Document.GetNodeList().GetNodes()))
+
+	opts.Tags = []string{"tag1"}
+
+	results, err = query.Query(`true`, nil, opts)
+	qs.Require().NoError(err)
+	qs.Len(results, len(qs.documentInfo[0].Document.GetNodeList().GetNodes()))
+
+	_, err = query.Query(`name`, nil, opts)
+	qs.Require().EqualError(err, "expression must evaluate to a bool, got string")
+}
+
+func TestQuerySuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(querySuite))
+}
@@ -0,0 +1,420 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/redact/redact.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package redact
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/protobom/protobom/pkg/sbom"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/outpututil"
+)
+
+const (
+	// DefaultReplacement is the text that redacted values are replaced with.
+	DefaultReplacement = "REDACTED"
+
+	// RedactedFromProperty is the name of the property added to the root nodes of a redacted document,
+	// holding the ID of the original document.
+	RedactedFromProperty = "bomctl:redacted_from"
+
+	metadataPrefix = "metadata"
+	nodePrefix     = "node"
+	idField        = "id"
+	idHashLength   = 8
+)
+
+var (
+	errCopyFailed       = errors.New("failed to copy document")
+	errDocumentNotFound = errors.New("document not found")
+	errInvalidField     = errors.New("invalid field")
+	errInvalidPattern   = errors.New("invalid pattern")
+	errMissingFieldName = errors.New("missing field name")
+	errNoRootNodes      = errors.New("documents without root nodes can only be redacted if their nodes change")
+	errNoRules          = errors.New("at least one field or pattern must be specified")
+	errNoSubfields      = errors.New("field has no subfields")
+	errNotRedactable    = errors.New("field cannot be redacted")
+	errUnknownField     = errors.New("unknown field")
+)
+
+type (
+	// Rules describes what to redact from a document.
+	Rules struct {
+		fields      map[string][][]string
+		replacement string
+		patterns    []*regexp.Regexp
+	}
+
+	replaceFunc func(string) string
+)
+
+// Redact applies the redaction rules of opts to the document with the specified ID or alias, and stores
+// the result as a new revision of it. The original document remains unchanged and is referenced as the
+// base document of the revision.
+func Redact(sbomID string, opts *options.RedactOptions) (*sbom.Document, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	rules, err := NewRules(opts.Fields, opts.Patterns, opts.Replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := backend.GetDocumentByIDOrAlias(sbomID)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if base == nil {
+		return nil, fmt.Errorf("%w: %s", errDocumentNotFound, sbomID)
+	}
+
+	tags, err := backend.GetDocumentTags(base.GetMetadata().GetId())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	redacted, err := rules.Apply(base)
+	if err != nil {
+		return nil, err
+	}
+
+	redacted.Metadata.Id = uuid.New().URN()
+	redacted.Metadata.Date = timestamppb.Now()
+
+	// Mark the root nodes with the original document ID. Besides keeping traceability in exported
+	// documents, this keeps the node list distinct from the original's, as storage cannot share them.
+	for _, root := range redacted.GetNodeList().GetRootElements() {
+		if node := redacted.GetNodeList().GetNodeByID(root); node != nil {
+			node.Properties = append(node.Properties, &sbom.Property{
+				Name: RedactedFromProperty,
+				Data: base.GetMetadata().GetId(),
+			})
+		}
+	}
+
+	if proto.Equal(redacted.GetNodeList(), base.GetNodeList()) {
+		return nil, errNoRootNodes
+	}
+
+	backend.Logger.Info("Storing redacted revision", "baseID", base.GetMetadata().GetId(),
+		"id", redacted.GetMetadata().GetId())
+
+	sourceData, err := outpututil.Serialize(redacted)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if err := backend.StoreDocument(
+		redacted, db.WithRevisedDocumentAnnotations(base), db.WithSourceDataAnnotations(sourceData),
+	); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if err := backend.AddDocumentAnnotations(redacted.GetMetadata().GetId(), db.TagAnnotation, tags...); err != nil {
+		opts.Logger.Warn("Tag(s) could not be set.", "err", err)
+	}
+
+	return redacted, nil
+}
+
+// FieldNames returns the paths of the fields that can be redacted.
+func FieldNames() []string {
+	names := []string{}
+
+	for prefix, descriptor := range map[string]protoreflect.MessageDescriptor{
+		metadataPrefix: (&sbom.Metadata{}).ProtoReflect().Descriptor(),
+		nodePrefix:     (&sbom.Node{}).ProtoReflect().Descriptor(),
+	} {
+		for idx := range descriptor.Fields().Len() {
+			if field := descriptor.Fields().Get(idx); redactable(field) {
+				names = append(names, prefix+"."+string(field.Name()))
+			}
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// NewRules validates the field paths and compiles the patterns of a set of redaction rules. Field paths
+// start with either "node" or "metadata", followed by the names of nested fields, such as
+// "node.suppliers.email". Patterns are regular expressions matched against every text field.
+func NewRules(fields, patterns []string, replacement string) (*Rules, error) {
+	if len(fields) == 0 && len(patterns) == 0 {
+		return nil, errNoRules
+	}
+
+	rules := &Rules{fields: map[string][][]string{}, replacement: replacement}
+
+	for _, field := range fields {
+		path := strings.Split(field, ".")
+
+		var descriptor protoreflect.MessageDescriptor
+
+		switch path[0] {
+		case metadataPrefix:
+			descriptor = (&sbom.Metadata{}).ProtoReflect().Descriptor()
+		case nodePrefix:
+			descriptor = (&sbom.Node{}).ProtoReflect().Descriptor()
+		default:
+			return nil, fmt.Errorf("%w %s: must start with %q or %q", errInvalidField, field, nodePrefix, metadataPrefix)
+		}
+
+		if err := validatePath(descriptor, path[1:]); err != nil {
+			return nil, fmt.Errorf("%w %s: %w", errInvalidField, field, err)
+		}
+
+		rules.fields[path[0]] = append(rules.fields[path[0]], path[1:])
+	}
+
+	for _, pattern := range patterns {
+		expr, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidPattern, err)
+		}
+
+		rules.patterns = append(rules.patterns, expr)
+	}
+
+	return rules, nil
+}
+
+// Apply returns a copy of the document with the redaction rules applied. Node identifiers matching
+// a pattern are replaced with opaque identifiers, keeping the edges between nodes intact.
+func (rules *Rules) Apply(document *sbom.Document) (*sbom.Document, error) {
+	redacted, ok := proto.Clone(document).(*sbom.Document)
+	if !ok {
+		return nil, errCopyFailed
+	}
+
+	if redacted.Metadata == nil {
+		redacted.Metadata = &sbom.Metadata{}
+	}
+
+	messages := []protoreflect.Message{redacted.GetMetadata().ProtoReflect()}
+	for _, node := range redacted.GetNodeList().GetNodes() {
+		messages = append(messages, node.ProtoReflect())
+	}
+
+	for _, msg := range messages {
+		prefix := nodePrefix
+		if _, isMetadata := msg.Interface().(*sbom.Metadata); isMetadata {
+			prefix = metadataPrefix
+		}
+
+		for _, path := range rules.fields[prefix] {
+			redactPath(msg, path, rules.replaceAll)
+		}
+
+		if len(rules.patterns) > 0 {
+			rewrite(msg, rules.replaceMatches, true)
+		}
+	}
+
+	rules.redactNodeIDs(redacted.GetNodeList())
+
+	return redacted, nil
+}
+
+// redactNodeIDs replaces node identifiers that match any pattern with identifiers derived from
+// their hash, and updates the edges and root elements referring to them.
+func (rules *Rules) redactNodeIDs(nodeList *sbom.NodeList) {
+	ids := map[string]string{}
+
+	for _, node := range nodeList.GetNodes() {
+		if rules.replaceMatches(node.GetId()) != node.GetId() {
+			hash := sha256.Sum256([]byte(node.GetId()))
+			ids[node.GetId()] = fmt.Sprintf("%s-%x", rules.replacement, hash[:idHashLength])
+			node.Id = ids[node.GetId()]
+		}
+	}
+
+	replaceID := func(id string) string {
+		if redactedID, ok := ids[id]; ok {
+			return redactedID
+		}
+
+		return id
+	}
+
+	for _, edge := range nodeList.GetEdges() {
+		edge.From = replaceID(edge.GetFrom())
+
+		for idx := range edge.GetTo() {
+			edge.To[idx] = replaceID(edge.GetTo()[idx])
+		}
+	}
+
+	for idx := range nodeList.GetRootElements() {
+		nodeList.RootElements[idx] = replaceID(nodeList.GetRootElements()[idx])
+	}
+}
+
+func (rules *Rules) replaceAll(value string) string {
+	if value == "" {
+		return value
+	}
+
+	return rules.replacement
+}
+
+func (rules *Rules) replaceMatches(value string) string {
+	for _, pattern := range rules.patterns {
+		value = pattern.ReplaceAllString(value, rules.replacement)
+	}
+
+	return value
+}
+
+// redactable reports whether a field contains text that can be redacted.
+func redactable(field protoreflect.FieldDescriptor) bool {
+	if field.Name() == idField {
+		return false
+	}
+
+	if field.IsMap() {
+		field = field.MapValue()
+	}
+
+	switch field.Kind() { //nolint:exhaustive
+	case protoreflect.StringKind:
+		return true
+	case protoreflect.MessageKind:
+		return field.Message().FullName() != (&timestamppb.Timestamp{}).ProtoReflect().Descriptor().FullName()
+	default:
+		return false
+	}
+}
+
+func validatePath(descriptor protoreflect.MessageDescriptor, path []string) error {
+	if len(path) == 0 || path[0] == "" {
+		return errMissingFieldName
+	}
+
+	field := descriptor.Fields().ByName(protoreflect.Name(path[0]))
+
+	switch {
+	case field == nil:
+		return fmt.Errorf("%w %q of %s", errUnknownField, path[0], descriptor.Name())
+	case !redactable(field):
+		return fmt.Errorf("%w: %s.%s", errNotRedactable, descriptor.Name(), path[0])
+	case len(path) == 1:
+		return nil
+	case field.Kind() != protoreflect.MessageKind || field.IsMap():
+		return fmt.Errorf("%w: %s.%s", errNoSubfields, descriptor.Name(), path[0])
+	}
+
+	return validatePath(field.Message(), path[1:])
+}
+
+// redactPath replaces all text of the field at path in msg.
+func redactPath(msg protoreflect.Message, path []string, replace replaceFunc) {
+	field := msg.Descriptor().Fields().ByName(protoreflect.Name(path[0]))
+	if !msg.Has(field) {
+		return
+	}
+
+	if len(path) == 1 {
+		rewriteField(msg, field, replace)
+
+		return
+	}
+
+	if field.IsList() {
+		list := msg.Mutable(field).List()
+		for idx := range list.Len() {
+			redactPath(list.Get(idx).Message(), path[1:], replace)
+		}
+
+		return
+	}
+
+	redactPath(msg.Mutable(field).Message(), path[1:], replace)
+}
+
+// rewrite replaces the text of every populated field of msg, recursing into nested messages.
+func rewrite(msg protoreflect.Message, replace replaceFunc, skipID bool) {
+	fields := []protoreflect.FieldDescriptor{}
+
+	msg.Range(func(field protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !skipID || field.Name() != idField {
+			fields = append(fields, field)
+		}
+
+		return true
+	})
+
+	for _, field := range fields {
+		rewriteField(msg, field, replace)
+	}
+}
+
+func rewriteField(msg protoreflect.Message, field protoreflect.FieldDescriptor, replace replaceFunc) {
+	switch {
+	case field.IsMap():
+		values := msg.Mutable(field).Map()
+		keys := []protoreflect.MapKey{}
+
+		values.Range(func(key protoreflect.MapKey, _ protoreflect.Value) bool {
+			keys = append(keys, key)
+
+			return true
+		})
+
+		for _, key := range keys {
+			switch field.MapValue().Kind() { //nolint:exhaustive
+			case protoreflect.StringKind:
+				values.Set(key, protoreflect.ValueOfString(replace(values.Get(key).String())))
+			case protoreflect.MessageKind:
+				rewrite(values.Mutable(key).Message(), replace, false)
+			}
+		}
+	case field.IsList():
+		list := msg.Mutable(field).List()
+
+		for idx := range list.Len() {
+			switch field.Kind() { //nolint:exhaustive
+			case protoreflect.StringKind:
+				list.Set(idx, protoreflect.ValueOfString(replace(list.Get(idx).String())))
+			case protoreflect.MessageKind:
+				rewrite(list.Get(idx).Message(), replace, false)
+			}
+		}
+	case field.Kind() == protoreflect.StringKind:
+		msg.Set(field, protoreflect.ValueOfString(replace(msg.Get(field).String())))
+	case field.Kind() == protoreflect.MessageKind:
+		rewrite(msg.Mutable(field).Message(), replace, false)
+	}
+}
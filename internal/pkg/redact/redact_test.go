@@ -0,0 +1,213 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/redact/redact_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package redact_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/redact"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+type redactSuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	documentInfo []testutil.DocumentInfo
+}
+
+func (rs *redactSuite) SetupSuite() {
+	var err error
+
+	rs.Backend, err = testutil.NewTestBackend()
+	rs.Require().NoError(err, "failed database backend creation")
+
+	rs.documentInfo, err = testutil.AddTestDocuments(rs.Backend)
+	rs.Require().NoError(err, "failed database backend setup")
+
+	rs.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, rs.Backend))
+}
+
+func (rs *redactSuite) TearDownSuite() {
+	rs.Backend.CloseClient()
+}
+
+func newTestDocument() *sbom.Document {
+	document := sbom.NewDocument()
+	document.Metadata.Id = "urn:uuid:test"
+	document.Metadata.Authors = []*sbom.Person{{Name: "Jane Doe", Email: "jane.doe@corp.example.com"}}
+
+	document.NodeList.AddNode(&sbom.Node{
+		Id:        "pkg:generic/app@1.0.0?repository_url=git.corp.example.com",
+		Name:      "app",
+		Version:   "1.0.0",
+		UrlHome:   "https://git.corp.example.com/app",
+		Suppliers: []*sbom.Person{{Name: "Example Corp", Email: "oss@corp.example.com"}},
+		Identifiers: map[int32]string{
+			int32(sbom.SoftwareIdentifierType_PURL): "pkg:generic/app@1.0.0?repository_url=git.corp.example.com",
+		},
+	})
+
+	document.NodeList.AddNode(&sbom.Node{Id: "lib", Name: "lib", Version: "2.0.0"})
+	document.NodeList.RootElements = []string{"pkg:generic/app@1.0.0?repository_url=git.corp.example.com"}
+	document.NodeList.AddEdge(&sbom.Edge{
+		Type: sbom.Edge_dependsOn,
+		From: "pkg:generic/app@1.0.0?repository_url=git.corp.example.com",
+		To:   []string{"lib"},
+	})
+
+	return document
+}
+
+func (rs *redactSuite) TestNewRules() {
+	for _, subtest := range []struct {
+		name     string
+		fields   []string
+		patterns []string
+		wantErr  bool
+	}{
+		{name: "no rules", wantErr: true},
+		{name: "field", fields: []string{"node.suppliers"}},
+		{name: "nested field", fields: []string{"node.suppliers.email", "metadata.authors.email"}},
+		{name: "pattern", patterns: []string{`[\w.]+@example\.com`}},
+		{name: "unknown prefix", fields: []string{"edge.from"}, wantErr: true},
+		{name: "missing field name", fields: []string{"node"}, wantErr: true},
+		{name: "unknown field", fields: []string{"node.nonexistent"}, wantErr: true},
+		{name: "identifier", fields: []string{"node.id"}, wantErr: true},
+		{name: "field without subfields", fields: []string{"node.name.first"}, wantErr: true},
+		{name: "invalid pattern", patterns: []string{"("}, wantErr: true},
+	} {
+		rs.Run(subtest.name, func() {
+			_, err := redact.NewRules(subtest.fields, subtest.patterns, redact.DefaultReplacement)
+			if subtest.wantErr {
+				rs.Require().Error(err)
+			} else {
+				rs.Require().NoError(err)
+			}
+		})
+	}
+}
+
+func (rs *redactSuite) TestApplyFields() {
+	rules, err := redact.NewRules([]string{"node.suppliers.email", "node.url_home", "metadata.authors"}, nil, "***")
+	rs.Require().NoError(err)
+
+	document := newTestDocument()
+
+	redacted, err := rules.Apply(document)
+	rs.Require().NoError(err)
+
+	app := redacted.GetNodeList().GetNodes()[0]
+	rs.Equal("***", app.GetUrlHome())
+	rs.Equal("Example Corp", app.GetSuppliers()[0].GetName())
+	rs.Equal("***", app.GetSuppliers()[0].GetEmail())
+	rs.Equal("***", redacted.GetMetadata().GetAuthors()[0].GetName())
+	rs.Equal("***", redacted.GetMetadata().GetAuthors()[0].GetEmail())
+
+	// Node identifiers are only redacted by patterns.
+	rs.Equal(document.GetNodeList().GetNodes()[0].GetId(), app.GetId())
+
+	// The original document is unchanged.
+	rs.Equal("https://git.corp.example.com/app", document.GetNodeList().GetNodes()[0].GetUrlHome())
+}
+
+func (rs *redactSuite) TestApplyPatterns() {
+	rules, err := redact.NewRules(nil, []string{`([\w-]+\.)*corp\.example\.com`}, redact.DefaultReplacement)
+	rs.Require().NoError(err)
+
+	redacted, err := rules.Apply(newTestDocument())
+	rs.Require().NoError(err)
+
+	nodeList := redacted.GetNodeList()
+	app := nodeList.GetNodes()[0]
+
+	rs.Equal("https://REDACTED/app", app.GetUrlHome())
+	rs.Equal("oss@REDACTED", app.GetSuppliers()[0].GetEmail())
+	rs.Equal("jane.doe@REDACTED", redacted.GetMetadata().GetAuthors()[0].GetEmail())
+	rs.Equal("pkg:generic/app@1.0.0?repository_url=REDACTED",
+		app.GetIdentifiers()[int32(sbom.SoftwareIdentifierType_PURL)])
+	rs.Equal("urn:uuid:test", redacted.GetMetadata().GetId())
+
+	// Matching node identifiers are replaced consistently across the node graph.
+	rs.True(strings.HasPrefix(app.GetId(), "REDACTED-"))
+	rs.Equal([]string{app.GetId()}, nodeList.GetRootElements())
+	rs.Equal(app.GetId(), nodeList.GetEdges()[0].GetFrom())
+	rs.Equal([]string{"lib"}, nodeList.GetEdges()[0].GetTo())
+	rs.Equal("lib", nodeList.GetNodes()[1].GetId())
+}
+
+func (rs *redactSuite) TestRedact() {
+	base := rs.documentInfo[1].Document
+	opts := &options.RedactOptions{
+		Options:     rs.Options,
+		Fields:      []string{"node.file_name"},
+		Replacement: redact.DefaultReplacement,
+	}
+
+	redacted, err := redact.Redact("spdx", opts)
+	rs.Require().NoError(err)
+
+	rs.NotEqual(base.GetMetadata().GetId(), redacted.GetMetadata().GetId())
+
+	stored, err := rs.Backend.GetDocumentByID(redacted.GetMetadata().GetId())
+	rs.Require().NoError(err)
+	rs.Require().NotNil(stored)
+
+	for _, node := range stored.GetNodeList().GetNodes() {
+		if node.GetFileName() != "" {
+			rs.Equal(redact.DefaultReplacement, node.GetFileName())
+		}
+	}
+
+	for _, root := range stored.GetNodeList().GetRootElements() {
+		rs.Contains(stored.GetNodeList().GetNodeByID(root).GetProperties(), &sbom.Property{
+			Name: redact.RedactedFromProperty,
+			Data: base.GetMetadata().GetId(),
+		})
+	}
+
+	revisions, err := rs.Backend.GetDocumentRevisions(redacted.GetMetadata().GetId())
+	rs.Require().NoError(err)
+	rs.Require().Len(revisions, 2)
+	rs.Equal(base.GetMetadata().GetId(), revisions[0].GetMetadata().GetId())
+	rs.Equal(redacted.GetMetadata().GetId(), revisions[1].GetMetadata().GetId())
+
+	// The alias moves to the redacted revision, and its tags are copied.
+	rs.Equal("spdx", rs.Backend.GetDocumentAlias(redacted.GetMetadata().GetId()))
+
+	tags, err := rs.Backend.GetDocumentTags(redacted.GetMetadata().GetId())
+	rs.Require().NoError(err)
+	rs.ElementsMatch([]string{"tag2", "tag3"}, tags)
+
+	_, err = redact.Redact("urn:uuid:nonexistent", opts)
+	rs.Require().Error(err)
+}
+
+func TestRedactSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(redactSuite))
+}
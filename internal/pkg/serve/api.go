@@ -0,0 +1,268 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/serve/api.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package serve
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+type (
+	apiHandler struct {
+		backend *db.Backend
+		opts    *options.ServeOptions
+	}
+
+	// DocumentSummary describes a document in the document list.
+	DocumentSummary struct {
+		ID        string   `json:"id"`
+		Alias     string   `json:"alias"`
+		Name      string   `json:"name"`
+		Tags      []string `json:"tags"`
+		NodeCount int      `json:"nodeCount"`
+		EdgeCount int      `json:"edgeCount"`
+	}
+
+	// Node is a node of a document's graph.
+	Node struct {
+		ID      string `json:"id"`
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Purl    string `json:"purl"`
+		Type    string `json:"type"`
+	}
+
+	// Edge is a relationship from one node to another.
+	Edge struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+		Type string `json:"type"`
+	}
+
+	// Link is a LinkToAnnotation between a document or node and another document.
+	Link struct {
+		From      string `json:"from"`
+		FromType  string `json:"fromType"`
+		FromAlias string `json:"fromAlias,omitempty"`
+		To        string `json:"to"`
+		ToAlias   string `json:"toAlias,omitempty"`
+	}
+
+	// Links holds the links from and to a document.
+	Links struct {
+		Outgoing []Link `json:"outgoing"`
+		Incoming []Link `json:"incoming"`
+	}
+
+	// DocumentDetail describes a document along with its graph and links.
+	DocumentDetail struct {
+		Links Links    `json:"links"`
+		Roots []string `json:"roots"`
+		Nodes []Node   `json:"nodes"`
+		Edges []Edge   `json:"edges"`
+		DocumentSummary
+	}
+)
+
+func (api *apiHandler) listDocuments(w http.ResponseWriter, _ *http.Request) {
+	documents, err := api.backend.GetDocumentsByIDOrAlias()
+	if err != nil {
+		api.writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	summaries := []DocumentSummary{}
+
+	for _, document := range documents {
+		summary, err := api.summarize(document)
+		if err != nil {
+			api.writeError(w, http.StatusInternalServerError, err)
+
+			return
+		}
+
+		summaries = append(summaries, summary)
+	}
+
+	api.writeJSON(w, http.StatusOK, summaries)
+}
+
+func (api *apiHandler) getDocument(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+
+	document, err := api.backend.GetDocumentByIDOrAlias(id)
+	if err != nil {
+		api.writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	if document == nil {
+		api.writeError(w, http.StatusNotFound, fmt.Errorf("%w: %s", errDocumentNotFound, id))
+
+		return
+	}
+
+	summary, err := api.summarize(document)
+	if err != nil {
+		api.writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	detail := &DocumentDetail{
+		DocumentSummary: summary,
+		Roots:           document.GetNodeList().GetRootElements(),
+		Nodes:           []Node{},
+		Edges:           []Edge{},
+	}
+
+	if detail.Roots == nil {
+		detail.Roots = []string{}
+	}
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		detail.Nodes = append(detail.Nodes, Node{
+			ID:      node.GetId(),
+			Name:    node.GetName(),
+			Version: node.GetVersion(),
+			Purl:    string(node.Purl()),
+			Type:    node.GetType().String(),
+		})
+	}
+
+	for _, edge := range document.GetNodeList().GetEdges() {
+		for _, to := range edge.GetTo() {
+			detail.Edges = append(detail.Edges, Edge{From: edge.GetFrom(), To: to, Type: edge.GetType().String()})
+		}
+	}
+
+	if detail.Links, err = api.links(document); err != nil {
+		api.writeError(w, http.StatusInternalServerError, err)
+
+		return
+	}
+
+	api.writeJSON(w, http.StatusOK, detail)
+}
+
+func (api *apiHandler) summarize(document *sbom.Document) (DocumentSummary, error) {
+	id := document.GetMetadata().GetId()
+
+	tags, err := api.backend.GetDocumentTags(id)
+	if err != nil {
+		return DocumentSummary{}, fmt.Errorf("%w", err)
+	}
+
+	edges := 0
+	for _, edge := range document.GetNodeList().GetEdges() {
+		edges += len(edge.GetTo())
+	}
+
+	return DocumentSummary{
+		ID:        id,
+		Alias:     api.backend.GetDocumentAlias(id),
+		Name:      document.GetMetadata().GetName(),
+		Tags:      tags,
+		NodeCount: len(document.GetNodeList().GetNodes()),
+		EdgeCount: edges,
+	}, nil
+}
+
+// links collects the outgoing links of the document and its nodes, and the incoming
+// links from other documents and nodes.
+func (api *apiHandler) links(document *sbom.Document) (Links, error) {
+	id := document.GetMetadata().GetId()
+	links := Links{Outgoing: []Link{}, Incoming: []Link{}}
+
+	annotations, err := api.backend.GetDocumentAnnotations(id, db.LinkToAnnotation)
+	if err != nil {
+		return links, fmt.Errorf("%w", err)
+	}
+
+	for _, annotation := range annotations {
+		links.Outgoing = append(links.Outgoing, api.newLink(id, options.LinkTargetTypeDocument, annotation.Value))
+	}
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		annotations, err := api.backend.GetNodeAnnotations(node.GetId(), db.LinkToAnnotation)
+		if err != nil {
+			return links, fmt.Errorf("%w", err)
+		}
+
+		for _, annotation := range annotations {
+			links.Outgoing = append(links.Outgoing, api.newLink(node.GetId(), options.LinkTargetTypeNode, annotation.Value))
+		}
+	}
+
+	documents, err := api.backend.GetDocumentsByAnnotation(db.LinkToAnnotation, id)
+	if err != nil {
+		return links, fmt.Errorf("%w", err)
+	}
+
+	for _, source := range documents {
+		links.Incoming = append(links.Incoming,
+			api.newLink(source.GetMetadata().GetId(), options.LinkTargetTypeDocument, id))
+	}
+
+	nodes, err := api.backend.GetNodesByAnnotation(db.LinkToAnnotation, id)
+	if err != nil {
+		return links, fmt.Errorf("%w", err)
+	}
+
+	for _, source := range nodes {
+		links.Incoming = append(links.Incoming, api.newLink(source.GetId(), options.LinkTargetTypeNode, id))
+	}
+
+	return links, nil
+}
+
+func (api *apiHandler) newLink(from string, fromType options.LinkTargetType, to string) Link {
+	link := Link{From: from, FromType: fromType.String(), To: to, ToAlias: api.backend.GetDocumentAlias(to)}
+
+	if fromType == options.LinkTargetTypeDocument {
+		link.FromAlias = api.backend.GetDocumentAlias(from)
+	}
+
+	return link
+}
+
+func (api *apiHandler) writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(value); err != nil {
+		api.opts.Logger.Error("failed to write response", "err", err)
+	}
+}
+
+func (api *apiHandler) writeError(w http.ResponseWriter, status int, err error) {
+	api.writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,103 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/serve/serve.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package serve
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+//go:embed ui
+var uiFiles embed.FS
+
+var errDocumentNotFound = errors.New("document not found")
+
+// Serve starts an HTTP server on the configured host and port, serving the JSON API and,
+// if enabled, the web viewer. It blocks until the options context is canceled.
+func Serve(opts *options.ServeOptions) error {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port)))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	server := &http.Server{
+		Handler:           NewHandler(backend, opts),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	opts.Logger.Info("Serving local cache", "url", "http://"+listener.Addr().String())
+
+	errChan := make(chan error, 1)
+
+	go func() { errChan <- server.Serve(listener) }()
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("%w", err)
+	case <-opts.Context().Done():
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(ctx); err != nil {
+			return fmt.Errorf("failed to shut down server: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// NewHandler returns the HTTP handler for the API routes and, if opts.UI is set,
+// the embedded web viewer.
+func NewHandler(backend *db.Backend, opts *options.ServeOptions) http.Handler {
+	api := &apiHandler{backend: backend, opts: opts}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/documents", api.listDocuments)
+	mux.HandleFunc("GET /api/document", api.getDocument)
+
+	if opts.UI {
+		// The embedded directory always exists, so fs.Sub cannot fail.
+		ui, _ := fs.Sub(uiFiles, "ui") //nolint:errcheck
+
+		mux.Handle("GET /", http.FileServerFS(ui))
+	}
+
+	return mux
+}
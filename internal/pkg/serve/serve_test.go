@@ -0,0 +1,170 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/serve/serve_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package serve_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/serve"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+type serveSuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	server       *httptest.Server
+	documentInfo []testutil.DocumentInfo
+}
+
+func (ss *serveSuite) SetupSuite() {
+	var err error
+
+	ss.Backend, err = testutil.NewTestBackend()
+	ss.Require().NoError(err, "failed database backend creation")
+
+	ss.documentInfo, err = testutil.AddTestDocuments(ss.Backend)
+	ss.Require().NoError(err, "failed database backend setup")
+
+	ss.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, ss.Backend))
+
+	cdxID := ss.documentInfo[0].Document.GetMetadata().GetId()
+	spdxID := ss.documentInfo[1].Document.GetMetadata().GetId()
+	nodeID := ss.documentInfo[1].Document.GetNodeList().GetNodes()[0].GetId()
+
+	ss.Require().NoError(ss.Backend.AddDocumentAnnotations(cdxID, db.LinkToAnnotation, spdxID))
+	ss.Require().NoError(ss.Backend.AddNodeAnnotations(nodeID, db.LinkToAnnotation, cdxID))
+
+	ss.server = httptest.NewServer(serve.NewHandler(ss.Backend, &options.ServeOptions{Options: ss.Options, UI: true}))
+}
+
+func (ss *serveSuite) TearDownSuite() {
+	ss.server.Close()
+	ss.Backend.CloseClient()
+}
+
+func (ss *serveSuite) get(path string, value any) int {
+	ss.T().Helper()
+
+	resp, err := http.Get(ss.server.URL + path) //nolint:noctx
+	ss.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	if value != nil {
+		ss.Require().NoError(json.NewDecoder(resp.Body).Decode(value))
+	}
+
+	return resp.StatusCode
+}
+
+func (ss *serveSuite) TestListDocuments() {
+	summaries := []serve.DocumentSummary{}
+	ss.Require().Equal(http.StatusOK, ss.get("/api/documents", &summaries))
+	ss.Require().Len(summaries, len(ss.documentInfo))
+
+	for idx, expected := range []struct {
+		alias string
+		tags  []string
+	}{
+		{alias: "cdx", tags: []string{"tag1", "tag2"}},
+		{alias: "spdx", tags: []string{"tag2", "tag3"}},
+	} {
+		document := ss.documentInfo[idx].Document
+
+		ss.Equal(document.GetMetadata().GetId(), summaries[idx].ID)
+		ss.Equal(expected.alias, summaries[idx].Alias)
+		ss.ElementsMatch(expected.tags, summaries[idx].Tags)
+		ss.Len(document.GetNodeList().GetNodes(), summaries[idx].NodeCount)
+	}
+}
+
+func (ss *serveSuite) TestGetDocument() {
+	cdx, spdx := ss.documentInfo[0], ss.documentInfo[1]
+	detail := serve.DocumentDetail{}
+
+	ss.Require().Equal(http.StatusOK, ss.get("/api/document?id=cdx", &detail))
+	ss.Equal(cdx.Document.GetMetadata().GetId(), detail.ID)
+	ss.Equal(cdx.Document.GetNodeList().GetRootElements(), detail.Roots)
+	ss.Len(detail.Nodes, len(cdx.Document.GetNodeList().GetNodes()))
+	ss.Len(detail.Edges, detail.EdgeCount)
+
+	ss.Equal([]serve.Link{{
+		From: detail.ID, FromType: "document", FromAlias: "cdx", To: spdx.Document.GetMetadata().GetId(), ToAlias: "spdx",
+	}}, detail.Links.Outgoing)
+
+	ss.Equal([]serve.Link{{
+		From: spdx.Document.GetNodeList().GetNodes()[0].GetId(), FromType: "node", To: detail.ID, ToAlias: "cdx",
+	}}, detail.Links.Incoming)
+
+	detail = serve.DocumentDetail{}
+	spdxPath := "/api/document?id=" + url.QueryEscape(spdx.Document.GetMetadata().GetId())
+	ss.Require().Equal(http.StatusOK, ss.get(spdxPath, &detail))
+	ss.Equal("spdx", detail.Alias)
+	ss.Len(detail.Links.Incoming, 1)
+	ss.Equal("document", detail.Links.Incoming[0].FromType)
+	ss.Len(detail.Links.Outgoing, 1)
+	ss.Equal("node", detail.Links.Outgoing[0].FromType)
+
+	body := map[string]string{}
+	ss.Require().Equal(http.StatusNotFound, ss.get("/api/document?id=missing", &body))
+	ss.Equal("document not found: missing", body["error"])
+}
+
+func (ss *serveSuite) TestUI() {
+	for _, path := range []string{"/", "/app.js", "/style.css"} {
+		ss.Equal(http.StatusOK, ss.get(path, nil), path)
+	}
+
+	server := httptest.NewServer(serve.NewHandler(ss.Backend, &options.ServeOptions{Options: ss.Options}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/") //nolint:noctx
+	ss.Require().NoError(err)
+
+	defer resp.Body.Close()
+
+	ss.Equal(http.StatusNotFound, resp.StatusCode)
+}
+
+func (ss *serveSuite) TestServe() {
+	ctx, cancel := context.WithCancel(ss.Options.Context())
+	opts := &options.ServeOptions{Options: options.New().WithContext(ctx), Host: "localhost"}
+
+	cancel()
+	ss.Require().NoError(serve.Serve(opts))
+
+	opts.Port = -1
+	ss.Require().ErrorContains(serve.Serve(opts), "failed to listen")
+}
+
+func TestServeSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(serveSuite))
+}
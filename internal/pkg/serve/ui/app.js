@@ -0,0 +1,357 @@
+/*
+ * -----------------------------------------------------------------------------
+ * SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+ * SPDX-FileName: internal/pkg/serve/ui/app.js
+ * SPDX-FileType: SOURCE
+ * SPDX-License-Identifier: Apache-2.0
+ * -----------------------------------------------------------------------------
+ */
+
+"use strict";
+
+const SVG_NS = "http://www.w3.org/2000/svg";
+const VERTEX_WIDTH = 220;
+const VERTEX_HEIGHT = 28;
+const COLUMN_GAP = 80;
+const ROW_GAP = 12;
+
+const state = { documents: [], document: null, view: { x: 20, y: 20, scale: 1 } };
+
+const $ = (id) => document.getElementById(id);
+
+function element(tag, attrs = {}, text = "") {
+  const el = tag.startsWith("svg:") ? document.createElementNS(SVG_NS, tag.slice(4)) : document.createElement(tag);
+
+  for (const [name, value] of Object.entries(attrs)) {
+    el.setAttribute(name, value);
+  }
+
+  if (text) {
+    el.textContent = text;
+  }
+
+  return el;
+}
+
+async function fetchJSON(url) {
+  const response = await fetch(url);
+  const body = await response.json();
+
+  if (!response.ok) {
+    throw new Error(body.error || response.statusText);
+  }
+
+  return body;
+}
+
+function label(node) {
+  const name = node.name || node.id;
+
+  return node.version ? `${name}@${node.version}` : name;
+}
+
+function truncate(text, length) {
+  return text.length > length ? `${text.slice(0, length - 1)}…` : text;
+}
+
+// Document list.
+
+function renderDocuments() {
+  const filter = $("filter").value.toLowerCase();
+  const list = $("documents");
+
+  list.replaceChildren();
+
+  for (const doc of state.documents) {
+    const haystack = [doc.id, doc.alias, doc.name, ...doc.tags].join(" ").toLowerCase();
+    if (filter && !haystack.includes(filter)) {
+      continue;
+    }
+
+    const item = element("button", { class: "document", type: "button" });
+    item.classList.toggle("selected", state.document?.id === doc.id);
+    item.append(element("div", { class: "id" }, doc.id));
+
+    const meta = [doc.alias && `alias: ${doc.alias}`, doc.name, `${doc.nodeCount} nodes, ${doc.edgeCount} edges`];
+    item.append(element("div", { class: "meta" }, meta.filter(Boolean).join(" · ")));
+
+    for (const tag of doc.tags) {
+      item.append(element("span", { class: "tag" }, tag));
+    }
+
+    item.addEventListener("click", () => selectDocument(doc.id));
+    list.append(item);
+  }
+}
+
+async function selectDocument(id) {
+  state.document = await fetchJSON(`api/document?id=${encodeURIComponent(id)}`);
+  state.view = { x: 20, y: 20, scale: 1 };
+
+  history.replaceState(null, "", `#${encodeURIComponent(id)}`);
+
+  $("empty").hidden = true;
+  $("document").hidden = false;
+  $("title").textContent = state.document.alias ? `${state.document.id} (${state.document.alias})` : state.document.id;
+  $("search").value = "";
+  $("node").replaceChildren(element("dd", {}, "Click a node to show its details."));
+
+  renderDocuments();
+  renderGraph();
+  renderLinks();
+}
+
+// Graph.
+
+// layout assigns each node a column by its breadth-first depth from the root elements and a row
+// by the order in which it was reached. Nodes not reachable from a root are placed in a final column.
+function layout(doc) {
+  const children = new Map();
+  for (const edge of doc.edges) {
+    if (!children.has(edge.from)) {
+      children.set(edge.from, []);
+    }
+
+    children.get(edge.from).push(edge.to);
+  }
+
+  const depths = new Map();
+  const queue = doc.roots.filter((id) => doc.nodes.some((node) => node.id === id));
+  queue.forEach((id) => depths.set(id, 0));
+
+  while (queue.length > 0) {
+    const current = queue.shift();
+
+    for (const child of children.get(current) || []) {
+      if (!depths.has(child)) {
+        depths.set(child, depths.get(current) + 1);
+        queue.push(child);
+      }
+    }
+  }
+
+  const maxDepth = Math.max(-1, ...depths.values());
+  const rows = [];
+  const positions = new Map();
+
+  for (const node of doc.nodes) {
+    const depth = depths.has(node.id) ? depths.get(node.id) : maxDepth + 1;
+    rows[depth] = (rows[depth] || 0) + 1;
+
+    positions.set(node.id, {
+      x: depth * (VERTEX_WIDTH + COLUMN_GAP),
+      y: (rows[depth] - 1) * (VERTEX_HEIGHT + ROW_GAP),
+    });
+  }
+
+  return positions;
+}
+
+function renderGraph() {
+  const doc = state.document;
+  const svg = $("graph");
+  const positions = layout(doc);
+  const linked = new Set(doc.links.outgoing.filter((link) => link.fromType === "node").map((link) => link.from));
+
+  const defs = element("svg:defs");
+  const marker = element("svg:marker", {
+    id: "arrow", viewBox: "0 0 10 10", refX: 10, refY: 5, markerWidth: 6, markerHeight: 6, orient: "auto",
+  });
+  marker.append(element("svg:path", { d: "M 0 0 L 10 5 L 0 10 z", fill: "#8c959f" }));
+  defs.append(marker);
+
+  const root = element("svg:g", { id: "viewport" });
+
+  for (const edge of doc.edges) {
+    const from = positions.get(edge.from);
+    const to = positions.get(edge.to);
+    if (!from || !to) {
+      continue;
+    }
+
+    const x1 = from.x + VERTEX_WIDTH;
+    const y1 = from.y + VERTEX_HEIGHT / 2;
+    const x2 = to.x;
+    const y2 = to.y + VERTEX_HEIGHT / 2;
+    const bend = Math.max(COLUMN_GAP / 2, Math.abs(x2 - x1) / 2);
+
+    const path = element("svg:path", { class: "arc", d: `M ${x1} ${y1} C ${x1 + bend} ${y1}, ${x2 - bend} ${y2}, ${x2} ${y2}` });
+    path.append(element("svg:title", {}, edge.type));
+    root.append(path);
+  }
+
+  for (const node of doc.nodes) {
+    const position = positions.get(node.id);
+    const vertex = element("svg:g", { class: "vertex", transform: `translate(${position.x} ${position.y})` });
+    vertex.dataset.id = node.id;
+    vertex.classList.toggle("root", doc.roots.includes(node.id));
+    vertex.classList.toggle("linked", linked.has(node.id));
+
+    vertex.append(element("svg:rect", { width: VERTEX_WIDTH, height: VERTEX_HEIGHT }));
+    vertex.append(element("svg:text", { x: 8, y: VERTEX_HEIGHT / 2 + 4 }, truncate(label(node), 32)));
+    vertex.append(element("svg:title", {}, node.purl || label(node)));
+    vertex.addEventListener("click", () => selectNode(node));
+
+    root.append(vertex);
+  }
+
+  svg.replaceChildren(defs, root);
+  applyView();
+}
+
+function applyView() {
+  const { x, y, scale } = state.view;
+  $("viewport")?.setAttribute("transform", `translate(${x} ${y}) scale(${scale})`);
+}
+
+function highlight() {
+  const query = $("search").value.toLowerCase();
+
+  for (const vertex of document.querySelectorAll(".vertex")) {
+    const node = state.document.nodes.find((n) => n.id === vertex.dataset.id);
+    const haystack = [node.id, node.name, node.purl].join(" ").toLowerCase();
+
+    vertex.classList.toggle("match", query !== "" && haystack.includes(query));
+  }
+}
+
+function selectNode(node) {
+  for (const vertex of document.querySelectorAll(".vertex")) {
+    vertex.classList.toggle("selected", vertex.dataset.id === node.id);
+  }
+
+  const details = $("node");
+  details.replaceChildren();
+
+  for (const [name, value] of [["ID", node.id], ["Name", node.name], ["Version", node.version], ["Type", node.type], ["Purl", node.purl]]) {
+    if (value) {
+      details.append(element("dt", {}, name), element("dd", {}, value));
+    }
+  }
+
+  for (const link of state.document.links.outgoing.filter((l) => l.from === node.id)) {
+    const target = element("dd");
+    target.append(documentAnchor(link.to, link.toAlias));
+    details.append(element("dt", {}, "Links to"), target);
+  }
+}
+
+// Links.
+
+function documentAnchor(id, alias) {
+  const anchor = element("a", { href: `#${encodeURIComponent(id)}` }, alias ? `${id} (${alias})` : id);
+
+  anchor.addEventListener("click", (event) => {
+    event.preventDefault();
+
+    if (state.documents.some((doc) => doc.id === id)) {
+      selectDocument(id);
+    }
+  });
+
+  return anchor;
+}
+
+function renderLinks() {
+  const { outgoing, incoming } = state.document.links;
+
+  const render = (list, links, describe) => {
+    list.replaceChildren();
+
+    if (links.length === 0) {
+      list.append(element("li", {}, "None"));
+    }
+
+    for (const link of links) {
+      const item = element("li");
+      describe(item, link);
+      list.append(item);
+    }
+  };
+
+  render($("outgoing"), outgoing, (item, link) => {
+    if (link.fromType === "node") {
+      item.append(`node ${link.from} → `);
+    }
+
+    item.append(documentAnchor(link.to, link.toAlias));
+  });
+
+  render($("incoming"), incoming, (item, link) => {
+    if (link.fromType === "node") {
+      item.append(`node ${link.from}`);
+    } else {
+      item.append(documentAnchor(link.from, link.fromAlias));
+    }
+  });
+}
+
+// Pan and zoom.
+
+function enablePanZoom() {
+  const svg = $("graph");
+  let drag = null;
+
+  svg.addEventListener("pointerdown", (event) => {
+    drag = { x: event.clientX - state.view.x, y: event.clientY - state.view.y };
+    svg.classList.add("dragging");
+  });
+
+  svg.addEventListener("pointermove", (event) => {
+    if (drag) {
+      state.view.x = event.clientX - drag.x;
+      state.view.y = event.clientY - drag.y;
+      applyView();
+    }
+  });
+
+  for (const type of ["pointerup", "pointerleave"]) {
+    svg.addEventListener(type, () => {
+      drag = null;
+      svg.classList.remove("dragging");
+    });
+  }
+
+  svg.addEventListener("wheel", (event) => {
+    event.preventDefault();
+
+    const rect = svg.getBoundingClientRect();
+    const pointerX = event.clientX - rect.left;
+    const pointerY = event.clientY - rect.top;
+    const factor = event.deltaY < 0 ? 1.1 : 1 / 1.1;
+    const scale = Math.min(4, Math.max(0.1, state.view.scale * factor));
+
+    state.view.x = pointerX - ((pointerX - state.view.x) * scale) / state.view.scale;
+    state.view.y = pointerY - ((pointerY - state.view.y) * scale) / state.view.scale;
+    state.view.scale = scale;
+    applyView();
+  }, { passive: false });
+}
+
+async function main() {
+  $("filter").addEventListener("input", renderDocuments);
+  $("search").addEventListener("input", highlight);
+  $("reset").addEventListener("click", () => {
+    state.view = { x: 20, y: 20, scale: 1 };
+    applyView();
+  });
+
+  enablePanZoom();
+
+  try {
+    state.documents = await fetchJSON("api/documents");
+  } catch (error) {
+    $("documents").replaceChildren(element("p", {}, `Failed to load documents: ${error.message}`));
+
+    return;
+  }
+
+  renderDocuments();
+
+  const selected = decodeURIComponent(location.hash.slice(1));
+  if (selected) {
+    selectDocument(selected).catch(() => history.replaceState(null, "", location.pathname));
+  }
+}
+
+main();
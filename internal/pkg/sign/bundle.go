@@ -0,0 +1,146 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/sign/bundle.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package sign
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+)
+
+const (
+	// BundleMediaType is the media type of the Sigstore bundles created by bomctl.
+	BundleMediaType = "application/vnd.dev.sigstore.bundle.v0.3+json"
+
+	digestAlgorithm = "SHA2_256"
+)
+
+var (
+	errDigestMismatch    = errors.New("signed digest does not match the document")
+	errInvalidSignature  = errors.New("invalid signature")
+	errUnsupportedDigest = errors.New("unsupported digest algorithm")
+)
+
+type (
+	// Bundle is a Sigstore bundle holding a signature made with a key pair rather than a certificate.
+	// Since no transparency log is involved, cosign verify-blob requires --insecure-ignore-tlog to verify it.
+	//
+	//nolint:govet // Field order determines the serialized key order.
+	Bundle struct {
+		MediaType            string               `json:"mediaType"`
+		VerificationMaterial VerificationMaterial `json:"verificationMaterial"`
+		MessageSignature     MessageSignature     `json:"messageSignature"`
+	}
+
+	VerificationMaterial struct {
+		PublicKey PublicKeyIdentifier `json:"publicKey"`
+	}
+
+	PublicKeyIdentifier struct {
+		Hint string `json:"hint"`
+	}
+
+	MessageSignature struct {
+		MessageDigest MessageDigest `json:"messageDigest"`
+		Signature     []byte        `json:"signature"`
+	}
+
+	MessageDigest struct {
+		Algorithm string `json:"algorithm"`
+		Digest    []byte `json:"digest"`
+	}
+)
+
+// SignPayload signs payload with signer the same way as cosign sign-blob, and returns the signature in a
+// bundle. ECDSA keys sign the SHA-256 digest of the payload, while Ed25519 keys sign the payload itself.
+func SignPayload(signer crypto.Signer, payload []byte) (*Bundle, error) {
+	digest := sha256.Sum256(payload)
+
+	var (
+		signature []byte
+		err       error
+	)
+
+	switch signer.(type) {
+	case *ecdsa.PrivateKey:
+		signature, err = signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+	case ed25519.PrivateKey:
+		signature, err = signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	default:
+		return nil, errUnsupportedKey
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	hint, err := KeyHint(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Bundle{
+		MediaType:            BundleMediaType,
+		VerificationMaterial: VerificationMaterial{PublicKey: PublicKeyIdentifier{Hint: hint}},
+		MessageSignature: MessageSignature{
+			MessageDigest: MessageDigest{Algorithm: digestAlgorithm, Digest: digest[:]},
+			Signature:     signature,
+		},
+	}, nil
+}
+
+// VerifyPayload checks that the signature in bundle was made over payload with the private key of publicKey.
+func VerifyPayload(publicKey crypto.PublicKey, payload []byte, bundle *Bundle) error {
+	digest := sha256.Sum256(payload)
+	messageDigest := bundle.MessageSignature.MessageDigest
+
+	// The digest is informational in cosign bundles, so it is only checked when present.
+	if len(messageDigest.Digest) > 0 {
+		if messageDigest.Algorithm != digestAlgorithm {
+			return fmt.Errorf("%w: %s", errUnsupportedDigest, messageDigest.Algorithm)
+		}
+
+		if !bytes.Equal(messageDigest.Digest, digest[:]) {
+			return errDigestMismatch
+		}
+	}
+
+	var valid bool
+
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(key, digest[:], bundle.MessageSignature.Signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, payload, bundle.MessageSignature.Signature)
+	default:
+		return errUnsupportedKey
+	}
+
+	if !valid {
+		return errInvalidSignature
+	}
+
+	return nil
+}
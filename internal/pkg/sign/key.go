@@ -0,0 +1,233 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/sign/key.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package sign
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// PasswordEnvVar is the environment variable holding the password of an encrypted private key.
+	// It is shared with cosign so that the same key pair can be used with either tool.
+	PasswordEnvVar = "COSIGN_PASSWORD"
+
+	certificateType        = "CERTIFICATE"
+	cosignPrivateKeyType   = "ENCRYPTED COSIGN PRIVATE KEY"
+	ecPrivateKeyType       = "EC PRIVATE KEY"
+	privateKeyType         = "PRIVATE KEY"
+	publicKeyType          = "PUBLIC KEY"
+	sigstorePrivateKeyType = "ENCRYPTED SIGSTORE PRIVATE KEY"
+
+	keyLength   = 32
+	nonceLength = 24
+)
+
+var (
+	errDecryptFailed   = errors.New("failed to decrypt private key, check the password")
+	errInvalidPEM      = errors.New("no PEM block found")
+	errMissingPassword = errors.New("private key is encrypted, set " + PasswordEnvVar + " to its password")
+	errUnsupportedKDF  = errors.New("unsupported key encryption")
+	errUnsupportedKey  = errors.New("unsupported key type, only ECDSA and Ed25519 keys are supported")
+)
+
+// encryptedKey is the JSON structure of the private keys generated by cosign generate-key-pair.
+type encryptedKey struct {
+	Ciphertext []byte `json:"ciphertext"`
+	Cipher     struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	KDF struct {
+		Name   string `json:"name"`
+		Salt   []byte `json:"salt"`
+		Params struct {
+			N int `json:"N"` //nolint:tagliatelle
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+	} `json:"kdf"`
+}
+
+// LoadPrivateKey reads a PEM encoded ECDSA or Ed25519 private key from path. Unencrypted PKCS #8 and SEC 1
+// keys are supported, along with the encrypted keys generated by cosign, which are decrypted with password.
+func LoadPrivateKey(path string, password []byte) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key any
+
+	switch block.Type {
+	case privateKeyType:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case ecPrivateKeyType:
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case cosignPrivateKeyType, sigstorePrivateKeyType:
+		key, err = decryptPrivateKey(block.Bytes, password)
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedKey, block.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	switch signer := key.(type) {
+	case *ecdsa.PrivateKey:
+		return signer, nil
+	case ed25519.PrivateKey:
+		return signer, nil
+	default:
+		return nil, errUnsupportedKey
+	}
+}
+
+// LoadPublicKey reads a PEM encoded ECDSA or Ed25519 public key from path, such as the cosign.pub file
+// generated by cosign generate-key-pair.
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return ParsePublicKey(data)
+}
+
+// ParsePublicKey parses a PEM encoded ECDSA or Ed25519 public key, or the public key of a PEM encoded
+// certificate.
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errInvalidPEM
+	}
+
+	var (
+		key any
+		err error
+	)
+
+	switch block.Type {
+	case publicKeyType:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case certificateType:
+		var certificate *x509.Certificate
+
+		if certificate, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = certificate.PublicKey
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedKey, block.Type)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	switch key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, errUnsupportedKey
+	}
+}
+
+// KeyHint returns the base64 encoded SHA-256 digest of the DER encoded public key, which identifies the key
+// a signature was made with.
+func KeyHint(publicKey crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", fmt.Errorf("%w", err)
+	}
+
+	digest := sha256.Sum256(der)
+
+	return base64.StdEncoding.EncodeToString(digest[:]), nil
+}
+
+func decryptPrivateKey(data, password []byte) (any, error) {
+	if len(password) == 0 {
+		return nil, errMissingPassword
+	}
+
+	encrypted := &encryptedKey{}
+	if err := json.Unmarshal(data, encrypted); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if encrypted.KDF.Name != "scrypt" || encrypted.Cipher.Name != "nacl/secretbox" ||
+		len(encrypted.Cipher.Nonce) != nonceLength {
+		return nil, fmt.Errorf("%w: %s, %s", errUnsupportedKDF, encrypted.KDF.Name, encrypted.Cipher.Name)
+	}
+
+	params := encrypted.KDF.Params
+
+	secret, err := scrypt.Key(password, encrypted.KDF.Salt, params.N, params.R, params.P, keyLength)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	var (
+		boxKey [keyLength]byte
+		nonce  [nonceLength]byte
+	)
+
+	copy(boxKey[:], secret)
+	copy(nonce[:], encrypted.Cipher.Nonce)
+
+	der, ok := secretbox.Open(nil, encrypted.Ciphertext, &nonce, &boxKey)
+	if !ok {
+		return nil, errDecryptFailed
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: %s", errInvalidPEM, path)
+	}
+
+	return block, nil
+}
@@ -0,0 +1,183 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/sign/sign.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package sign
+
+import (
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/outpututil"
+)
+
+var (
+	errDocumentNotFound = errors.New("document not found")
+	errEmptyPayload     = errors.New("document has no content to sign")
+)
+
+// Sign signs the document with the specified ID or alias, and stores the resulting bundle as a signature
+// annotation of the document. A previous signature made with the same key is replaced.
+func Sign(sbomID string, signer crypto.Signer, opts *options.SignOptions) (*Bundle, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	document, err := backend.GetDocumentByIDOrAlias(sbomID)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if document == nil {
+		return nil, fmt.Errorf("%w: %s", errDocumentNotFound, sbomID)
+	}
+
+	if err := storeSerialization(backend, document); err != nil {
+		return nil, err
+	}
+
+	payload, err := Payload(document, opts.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := SignPayload(signer, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	documentID := document.GetMetadata().GetId()
+	hint := bundle.VerificationMaterial.PublicKey.Hint
+
+	opts.Logger.Info("Signing document", "sbomID", documentID, "keyHint", hint)
+
+	previous, err := StoredBundles(documentID, opts.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	for value, stored := range previous {
+		if stored.VerificationMaterial.PublicKey.Hint != hint {
+			continue
+		}
+
+		if err := backend.RemoveDocumentAnnotations(documentID, db.SignatureAnnotation, value); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if err := backend.AddDocumentAnnotations(documentID, db.SignatureAnnotation, string(data)); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	return bundle, nil
+}
+
+// Payload returns the bytes of the document that are signed, which are the original content stored with it.
+// These are the bytes the document was imported or fetched from, or its serialization at the time it was
+// revised or first signed, so a signature verifies against the same bytes as long as the document is cached.
+func Payload(document *sbom.Document, opts *options.Options) ([]byte, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	documentID := document.GetMetadata().GetId()
+
+	sourceData, err := backend.GetDocumentUniqueAnnotation(documentID, db.SourceDataAnnotation)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if sourceData == "" {
+		return nil, fmt.Errorf("%w: %s", errEmptyPayload, documentID)
+	}
+
+	return []byte(sourceData), nil
+}
+
+// storeSerialization stores the serialization of document in its source format as its original content, if
+// it has none, such as a merged or split document, so that it is signed and verified as the same bytes.
+func storeSerialization(backend *db.Backend, document *sbom.Document) error {
+	documentID := document.GetMetadata().GetId()
+
+	sourceData, err := backend.GetDocumentUniqueAnnotation(documentID, db.SourceDataAnnotation)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if sourceData != "" {
+		return nil
+	}
+
+	data, err := outpututil.Serialize(document)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if len(data) == 0 {
+		return fmt.Errorf("%w: %s", errEmptyPayload, documentID)
+	}
+
+	if err := backend.SetSourceData(documentID, data); err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	return nil
+}
+
+// StoredBundles returns the signature bundles stored for the document with the specified ID, keyed by
+// their annotation values.
+func StoredBundles(documentID string, opts *options.Options) (map[string]*Bundle, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	annotations, err := backend.GetDocumentAnnotations(documentID, db.SignatureAnnotation)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	bundles := map[string]*Bundle{}
+
+	for _, annotation := range annotations {
+		bundle := &Bundle{}
+		if err := json.Unmarshal([]byte(annotation.Value), bundle); err != nil {
+			opts.Logger.Warn("Skipping malformed signature annotation", "sbomID", documentID, "err", err)
+
+			continue
+		}
+
+		bundles[annotation.Value] = bundle
+	}
+
+	return bundles, nil
+}
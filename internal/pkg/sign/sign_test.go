@@ -0,0 +1,314 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/sign/sign_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package sign_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/protobom/protobom/pkg/formats"
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/sign"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+const testPassword = "hunter2"
+
+type signSuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	ecdsaKey     *ecdsa.PrivateKey
+	ed25519Key   ed25519.PrivateKey
+	keyDir       string
+	documentInfo []testutil.DocumentInfo
+}
+
+func (ss *signSuite) SetupSuite() {
+	var err error
+
+	ss.Backend, err = testutil.NewTestBackend()
+	ss.Require().NoError(err, "failed database backend creation")
+
+	ss.documentInfo, err = testutil.AddTestDocuments(ss.Backend)
+	ss.Require().NoError(err, "failed database backend setup")
+
+	ss.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, ss.Backend))
+
+	ss.ecdsaKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ss.Require().NoError(err)
+
+	_, ss.ed25519Key, err = ed25519.GenerateKey(rand.Reader)
+	ss.Require().NoError(err)
+
+	ss.keyDir = ss.T().TempDir()
+}
+
+func (ss *signSuite) TearDownSuite() {
+	ss.Backend.CloseClient()
+}
+
+func (ss *signSuite) writePEM(name, blockType string, data []byte) string {
+	path := filepath.Join(ss.keyDir, name)
+	ss.Require().NoError(os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0o600))
+
+	return path
+}
+
+func (ss *signSuite) writePKCS8(name string, key crypto.PrivateKey) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	ss.Require().NoError(err)
+
+	return ss.writePEM(name, "PRIVATE KEY", der)
+}
+
+// writeCosignKey writes key encrypted the same way as cosign generate-key-pair, with cheaper scrypt parameters.
+func (ss *signSuite) writeCosignKey(name string, key crypto.PrivateKey) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	ss.Require().NoError(err)
+
+	var (
+		boxKey [32]byte
+		nonce  [24]byte
+	)
+
+	salt := make([]byte, 32)
+	_, err = rand.Read(salt)
+	ss.Require().NoError(err)
+
+	_, err = rand.Read(nonce[:])
+	ss.Require().NoError(err)
+
+	secret, err := scrypt.Key([]byte(testPassword), salt, 1024, 8, 1, len(boxKey))
+	ss.Require().NoError(err)
+
+	copy(boxKey[:], secret)
+
+	data, err := json.Marshal(map[string]any{
+		"kdf": map[string]any{
+			"name":   "scrypt",
+			"params": map[string]int{"N": 1024, "r": 8, "p": 1},
+			"salt":   salt,
+		},
+		"cipher":     map[string]any{"name": "nacl/secretbox", "nonce": nonce[:]},
+		"ciphertext": secretbox.Seal(nil, der, &nonce, &boxKey),
+	})
+	ss.Require().NoError(err)
+
+	return ss.writePEM(name, "ENCRYPTED SIGSTORE PRIVATE KEY", data)
+}
+
+func (ss *signSuite) TestLoadPrivateKey() {
+	ecDER, err := x509.MarshalECPrivateKey(ss.ecdsaKey)
+	ss.Require().NoError(err)
+
+	pubDER, err := x509.MarshalPKIXPublicKey(ss.ecdsaKey.Public())
+	ss.Require().NoError(err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	ss.Require().NoError(err)
+
+	for _, subtest := range []struct {
+		expected crypto.PublicKey
+		name     string
+		path     string
+		password string
+	}{
+		{name: "pkcs8 ecdsa", path: ss.writePKCS8("ecdsa.key", ss.ecdsaKey), expected: ss.ecdsaKey.Public()},
+		{name: "pkcs8 ed25519", path: ss.writePKCS8("ed25519.key", ss.ed25519Key), expected: ss.ed25519Key.Public()},
+		{name: "sec1 ecdsa", path: ss.writePEM("sec1.key", "EC PRIVATE KEY", ecDER), expected: ss.ecdsaKey.Public()},
+		{
+			name:     "cosign",
+			path:     ss.writeCosignKey("cosign.key", ss.ecdsaKey),
+			password: testPassword,
+			expected: ss.ecdsaKey.Public(),
+		},
+		{name: "cosign without password", path: ss.writeCosignKey("nopass.key", ss.ecdsaKey)},
+		{name: "cosign wrong password", path: ss.writeCosignKey("wrong.key", ss.ecdsaKey), password: "wrong"},
+		{name: "rsa", path: ss.writePKCS8("rsa.key", rsaKey)},
+		{name: "public key", path: ss.writePEM("cosign.pub", "PUBLIC KEY", pubDER)},
+		{name: "missing file", path: filepath.Join(ss.keyDir, "nonexistent.key")},
+	} {
+		ss.Run(subtest.name, func() {
+			signer, err := sign.LoadPrivateKey(subtest.path, []byte(subtest.password))
+			if subtest.expected == nil {
+				ss.Require().Error(err)
+
+				return
+			}
+
+			ss.Require().NoError(err)
+			ss.Equal(subtest.expected, signer.Public())
+		})
+	}
+}
+
+func (ss *signSuite) TestLoadPublicKey() {
+	pubDER, err := x509.MarshalPKIXPublicKey(ss.ed25519Key.Public())
+	ss.Require().NoError(err)
+
+	publicKey, err := sign.LoadPublicKey(ss.writePEM("ed25519.pub", "PUBLIC KEY", pubDER))
+	ss.Require().NoError(err)
+	ss.Equal(ss.ed25519Key.Public(), publicKey)
+
+	_, err = sign.LoadPublicKey(ss.writePKCS8("private.key", ss.ed25519Key))
+	ss.Require().Error(err)
+}
+
+func (ss *signSuite) TestParsePublicKey() {
+	template := &x509.Certificate{SerialNumber: big.NewInt(1)}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, ss.ecdsaKey.Public(), ss.ecdsaKey)
+	ss.Require().NoError(err)
+
+	publicKey, err := sign.ParsePublicKey(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}))
+	ss.Require().NoError(err)
+	ss.True(ss.ecdsaKey.PublicKey.Equal(publicKey))
+
+	_, err = sign.ParsePublicKey([]byte("not a key"))
+	ss.Require().Error(err)
+}
+
+func (ss *signSuite) TestSignPayload() {
+	payload := []byte(`{"bomFormat": "CycloneDX"}`)
+	digest := sha256.Sum256(payload)
+
+	bundle, err := sign.SignPayload(ss.ecdsaKey, payload)
+	ss.Require().NoError(err)
+
+	hint, err := sign.KeyHint(ss.ecdsaKey.Public())
+	ss.Require().NoError(err)
+
+	ss.Equal(sign.BundleMediaType, bundle.MediaType)
+	ss.Equal(hint, bundle.VerificationMaterial.PublicKey.Hint)
+	ss.Equal(digest[:], bundle.MessageSignature.MessageDigest.Digest)
+	ss.True(ecdsa.VerifyASN1(&ss.ecdsaKey.PublicKey, digest[:], bundle.MessageSignature.Signature))
+
+	bundle, err = sign.SignPayload(ss.ed25519Key, payload)
+	ss.Require().NoError(err)
+
+	publicKey, ok := ss.ed25519Key.Public().(ed25519.PublicKey)
+	ss.Require().True(ok)
+	ss.True(ed25519.Verify(publicKey, payload, bundle.MessageSignature.Signature))
+}
+
+func (ss *signSuite) TestVerifyPayload() {
+	payload := []byte(`{"bomFormat": "CycloneDX"}`)
+
+	for _, signer := range []crypto.Signer{ss.ecdsaKey, ss.ed25519Key} {
+		bundle, err := sign.SignPayload(signer, payload)
+		ss.Require().NoError(err)
+
+		ss.Require().NoError(sign.VerifyPayload(signer.Public(), payload, bundle))
+		ss.Require().Error(sign.VerifyPayload(signer.Public(), []byte("{}"), bundle))
+
+		// Detached signatures carry no digest, so only the signature itself is checked.
+		detached := &sign.Bundle{MessageSignature: sign.MessageSignature{Signature: bundle.MessageSignature.Signature}}
+		ss.Require().NoError(sign.VerifyPayload(signer.Public(), payload, detached))
+		ss.Require().Error(sign.VerifyPayload(signer.Public(), []byte("{}"), detached))
+	}
+
+	bundle, err := sign.SignPayload(ss.ecdsaKey, payload)
+	ss.Require().NoError(err)
+	ss.Require().Error(sign.VerifyPayload(ss.ed25519Key.Public(), payload, bundle))
+}
+
+func (ss *signSuite) TestSign() {
+	opts := &options.SignOptions{Options: ss.Options}
+	documentID := ss.documentInfo[0].Document.GetMetadata().GetId()
+
+	bundle, err := sign.Sign("cdx", ss.ecdsaKey, opts)
+	ss.Require().NoError(err)
+
+	// Unmodified documents are signed as they were imported.
+	digest := sha256.Sum256(ss.documentInfo[0].Content)
+	ss.Equal(digest[:], bundle.MessageSignature.MessageDigest.Digest)
+	ss.True(ecdsa.VerifyASN1(&ss.ecdsaKey.PublicKey, digest[:], bundle.MessageSignature.Signature))
+
+	_, err = sign.Sign(documentID, ss.ed25519Key, opts)
+	ss.Require().NoError(err)
+
+	bundles, err := sign.StoredBundles(documentID, ss.Options)
+	ss.Require().NoError(err)
+	ss.Len(bundles, 2)
+
+	// Signing again with the same key replaces the previous signature.
+	bundle, err = sign.Sign("cdx", ss.ecdsaKey, opts)
+	ss.Require().NoError(err)
+
+	bundles, err = sign.StoredBundles(documentID, ss.Options)
+	ss.Require().NoError(err)
+	ss.Len(bundles, 2)
+	ss.Contains(bundles, string(ss.marshal(bundle)))
+
+	// Documents with no original content, such as merged documents, are signed as their serialization, which
+	// is stored as their original content.
+	merged := testutil.NewTestDocument()
+	merged.Metadata.SourceData = &sbom.SourceData{Format: string(formats.CDX15JSON)}
+	ss.Require().NoError(ss.Backend.StoreDocument(merged))
+
+	bundle, err = sign.Sign("urn:uuid:test", ss.ecdsaKey, opts)
+	ss.Require().NoError(err)
+
+	sourceData, err := ss.Backend.GetDocumentUniqueAnnotation("urn:uuid:test", db.SourceDataAnnotation)
+	ss.Require().NoError(err)
+	ss.Require().NotEmpty(sourceData)
+
+	digest = sha256.Sum256([]byte(sourceData))
+	ss.Equal(digest[:], bundle.MessageSignature.MessageDigest.Digest)
+
+	bundle, err = sign.Sign("urn:uuid:test", ss.ecdsaKey, opts)
+	ss.Require().NoError(err)
+	ss.Equal(digest[:], bundle.MessageSignature.MessageDigest.Digest)
+
+	_, err = sign.Sign("urn:uuid:nonexistent", ss.ecdsaKey, opts)
+	ss.Require().Error(err)
+}
+
+func (ss *signSuite) marshal(value any) []byte {
+	data, err := json.Marshal(value)
+	ss.Require().NoError(err)
+
+	return data
+}
+
+func TestSignSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(signSuite))
+}
@@ -0,0 +1,314 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/split/split.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package split
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/protobom/protobom/pkg/sbom"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/query"
+)
+
+var (
+	errCopyFailed       = errors.New("failed to copy document metadata")
+	errDocumentNotFound = errors.New("document not found")
+	errNoRules          = errors.New("at least one purl type, selector or --top-level must be specified")
+	errNothingSplit     = errors.New("no nodes matched the split rules")
+)
+
+type (
+	// Piece is a part of a document selected by a split rule.
+	Piece struct {
+		// NodeList holds the split points and every node reachable from them. Nodes are shared with the
+		// original document.
+		NodeList *sbom.NodeList
+
+		// Label describes the rule that selected the piece, such as a purl type or component name.
+		Label string
+
+		// SplitPoints are the IDs of the nodes the piece was split off at, which become its root elements.
+		SplitPoints []string
+	}
+
+	// Rules describes how to split a document into pieces.
+	Rules struct {
+		purlTypes []string
+		selectors []string
+		programs  []*query.Program
+		topLevel  bool
+	}
+)
+
+// Split divides the document with the specified ID or alias into pieces according to opts, and stores
+// each piece as a new document. The split points of the original document are linked to the new
+// documents, and the original document is otherwise unchanged.
+func Split(sbomID string, opts *options.SplitOptions) ([]*sbom.Document, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	rules, err := NewRules(opts.PurlTypes, opts.Selectors, opts.TopLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := backend.GetDocumentByIDOrAlias(sbomID)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if parent == nil {
+		return nil, fmt.Errorf("%w: %s", errDocumentNotFound, sbomID)
+	}
+
+	tags, err := backend.GetDocumentTags(parent.GetMetadata().GetId())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	tags = append(tags, opts.Tags...)
+	slices.Sort(tags)
+	tags = slices.Compact(tags)
+
+	pieces := rules.Apply(parent)
+	if len(pieces) == 0 {
+		return nil, errNothingSplit
+	}
+
+	documents := []*sbom.Document{}
+
+	for _, piece := range pieces {
+		document, err := newDocument(parent, piece)
+		if err != nil {
+			return nil, err
+		}
+
+		backend.Logger.Info("Storing split document", "label", piece.Label, "id", document.GetMetadata().GetId(),
+			"nodes", len(piece.NodeList.GetNodes()))
+
+		if err := backend.StoreDocument(document); err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		if err := backend.AddDocumentAnnotations(document.GetMetadata().GetId(), db.TagAnnotation, tags...); err != nil {
+			opts.Logger.Warn("Tag(s) could not be set.", "err", err)
+		}
+
+		for _, nodeID := range piece.SplitPoints {
+			if err := backend.AddNodeAnnotations(nodeID, db.LinkToAnnotation, document.GetMetadata().GetId()); err != nil {
+				return nil, fmt.Errorf("adding node link: %w", err)
+			}
+		}
+
+		documents = append(documents, document)
+	}
+
+	return documents, nil
+}
+
+// NewRules returns rules that split off the nodes of each of the given purl types, the nodes matching each
+// of the given CEL selectors, and, if topLevel is set, each direct dependency of the root elements.
+func NewRules(purlTypes, selectors []string, topLevel bool) (*Rules, error) {
+	if len(purlTypes) == 0 && len(selectors) == 0 && !topLevel {
+		return nil, errNoRules
+	}
+
+	rules := &Rules{selectors: slices.Clone(selectors), topLevel: topLevel}
+
+	for _, purlType := range purlTypes {
+		rules.purlTypes = append(rules.purlTypes, strings.TrimPrefix(strings.ToLower(purlType), "pkg:"))
+	}
+
+	for _, selector := range selectors {
+		program, err := query.Compile(selector)
+		if err != nil {
+			return nil, fmt.Errorf("selector %q: %w", selector, err)
+		}
+
+		rules.programs = append(rules.programs, program)
+	}
+
+	return rules, nil
+}
+
+// Apply returns the pieces selected by the rules from document. Each piece holds its split points along
+// with every node reachable from them. Rules selecting no nodes, or every node of the document, produce
+// no piece. The document is not modified.
+func (rules *Rules) Apply(document *sbom.Document) []*Piece {
+	nodeList := document.GetNodeList()
+	pieces := []*Piece{}
+
+	addPiece := func(label string, nodeIDs []string) {
+		if piece := newPiece(nodeList, label, nodeIDs); piece != nil &&
+			len(piece.NodeList.GetNodes()) < len(nodeList.GetNodes()) {
+			pieces = append(pieces, piece)
+		}
+	}
+
+	for _, purlType := range rules.purlTypes {
+		addPiece(purlType, matchingNodes(nodeList, func(node *sbom.Node) bool {
+			return nodePurlType(node) == purlType
+		}))
+	}
+
+	for idx, program := range rules.programs {
+		addPiece(rules.selectors[idx], matchingNodes(nodeList, func(node *sbom.Node) bool {
+			// Nodes failing evaluation are not matched, as with the query command.
+			matched, err := program.Match(node)
+
+			return err == nil && matched
+		}))
+	}
+
+	if rules.topLevel {
+		for _, root := range nodeList.GetRootElements() {
+			for _, edge := range nodeList.GetEdges() {
+				if edge.GetFrom() != root {
+					continue
+				}
+
+				for _, to := range edge.GetTo() {
+					if node := nodeList.GetNodeByID(to); node != nil {
+						addPiece(node.GetName(), []string{to})
+					}
+				}
+			}
+		}
+	}
+
+	return pieces
+}
+
+func newPiece(nodeList *sbom.NodeList, label string, splitPoints []string) *Piece {
+	if len(splitPoints) == 0 {
+		return nil
+	}
+
+	children := map[string][]string{}
+
+	for _, edge := range nodeList.GetEdges() {
+		children[edge.GetFrom()] = append(children[edge.GetFrom()], edge.GetTo()...)
+	}
+
+	included := map[string]bool{}
+	queue := slices.Clone(splitPoints)
+
+	for _, id := range queue {
+		included[id] = true
+	}
+
+	for ; len(queue) > 0; queue = queue[1:] {
+		for _, child := range children[queue[0]] {
+			if !included[child] {
+				included[child] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	piece := &Piece{Label: label, NodeList: &sbom.NodeList{}}
+
+	for _, node := range nodeList.GetNodes() {
+		if included[node.GetId()] {
+			piece.NodeList.Nodes = append(piece.NodeList.Nodes, node)
+		}
+	}
+
+	// Split points depended on by other nodes of the piece are not roots of it.
+	targets := map[string]bool{}
+
+	for _, edge := range nodeList.GetEdges() {
+		if !included[edge.GetFrom()] {
+			continue
+		}
+
+		to := slices.DeleteFunc(slices.Clone(edge.GetTo()), func(id string) bool { return !included[id] })
+		if len(to) == 0 {
+			continue
+		}
+
+		piece.NodeList.Edges = append(piece.NodeList.Edges, &sbom.Edge{Type: edge.GetType(), From: edge.GetFrom(), To: to})
+
+		for _, id := range to {
+			targets[id] = true
+		}
+	}
+
+	for _, id := range splitPoints {
+		if !targets[id] {
+			piece.SplitPoints = append(piece.SplitPoints, id)
+		}
+	}
+
+	// Split points that all depend on each other have no natural root, so they are all kept.
+	if len(piece.SplitPoints) == 0 {
+		piece.SplitPoints = slices.Clone(splitPoints)
+	}
+
+	piece.NodeList.RootElements = slices.Clone(piece.SplitPoints)
+
+	return piece
+}
+
+func newDocument(parent *sbom.Document, piece *Piece) (*sbom.Document, error) {
+	metadata, ok := proto.Clone(parent.GetMetadata()).(*sbom.Metadata)
+	if !ok {
+		return nil, errCopyFailed
+	}
+
+	metadata.Id = uuid.New().URN()
+	metadata.Date = timestamppb.Now()
+	metadata.Version = "1"
+	metadata.Name = piece.Label
+
+	if name := parent.GetMetadata().GetName(); name != "" {
+		metadata.Name = fmt.Sprintf("%s (%s)", name, piece.Label)
+	}
+
+	return &sbom.Document{Metadata: metadata, NodeList: piece.NodeList}, nil
+}
+
+func matchingNodes(nodeList *sbom.NodeList, match func(*sbom.Node) bool) []string {
+	nodeIDs := []string{}
+
+	for _, node := range nodeList.GetNodes() {
+		if match(node) {
+			nodeIDs = append(nodeIDs, node.GetId())
+		}
+	}
+
+	return nodeIDs
+}
+
+func nodePurlType(node *sbom.Node) string {
+	purlType, _, _ := strings.Cut(strings.TrimPrefix(string(node.Purl()), "pkg:"), "/")
+
+	return purlType
+}
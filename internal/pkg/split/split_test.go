@@ -0,0 +1,162 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/split/split_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package split_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/link"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/split"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+type splitSuite struct {
+	testutil.BackendSuite
+}
+
+func (ss *splitSuite) TestNewRules() {
+	_, err := split.NewRules(nil, nil, false)
+	ss.Require().Error(err)
+
+	_, err = split.NewRules(nil, []string{"name"}, false)
+	ss.Require().Error(err)
+
+	_, err = split.NewRules([]string{"pkg:npm"}, []string{`name == "a"`}, true)
+	ss.Require().NoError(err)
+}
+
+func (ss *splitSuite) TestApply() {
+	type expectedPiece struct {
+		label       string
+		splitPoints []string
+		nodes       []string
+	}
+
+	for _, subtest := range []struct {
+		name      string
+		purlTypes []string
+		selectors []string
+		expected  []expectedPiece
+		topLevel  bool
+	}{
+		{
+			name:      "purl type",
+			purlTypes: []string{"npm"},
+			expected:  []expectedPiece{{"npm", []string{"a"}, []string{"a", "c", "d", "e"}}},
+		},
+		{
+			name:      "selector",
+			selectors: []string{`name == "d" || name == "b"`},
+			expected:  []expectedPiece{{`name == "d" || name == "b"`, []string{"b"}, []string{"b", "d", "e"}}},
+		},
+		{
+			name:     "top level",
+			topLevel: true,
+			expected: []expectedPiece{
+				{"a", []string{"a"}, []string{"a", "c", "d", "e"}},
+				{"b", []string{"b"}, []string{"b", "d", "e"}},
+			},
+		},
+		{name: "whole document", purlTypes: []string{"generic"}, expected: []expectedPiece{}},
+		{name: "no match", purlTypes: []string{"pypi"}, expected: []expectedPiece{}},
+	} {
+		ss.Run(subtest.name, func() {
+			document := testutil.NewTestDocument()
+
+			rules, err := split.NewRules(subtest.purlTypes, subtest.selectors, subtest.topLevel)
+			ss.Require().NoError(err)
+
+			pieces := rules.Apply(document)
+			ss.Require().Len(pieces, len(subtest.expected))
+
+			for idx, expected := range subtest.expected {
+				ss.Equal(expected.label, pieces[idx].Label)
+				ss.Equal(expected.splitPoints, pieces[idx].SplitPoints)
+				ss.Equal(expected.splitPoints, pieces[idx].NodeList.GetRootElements())
+				ss.ElementsMatch(expected.nodes, testutil.NodeIDs(pieces[idx].NodeList))
+
+				// Edges only reference nodes of the piece.
+				for _, edge := range pieces[idx].NodeList.GetEdges() {
+					ss.Contains(expected.nodes, edge.GetFrom())
+					ss.Subset(expected.nodes, edge.GetTo())
+				}
+			}
+
+			// The original document is unchanged.
+			ss.Len(document.GetNodeList().GetNodes(), 6)
+			ss.Len(document.GetNodeList().GetEdges(), 4)
+		})
+	}
+}
+
+func (ss *splitSuite) TestSplit() {
+	parent := ss.DocumentInfo[0].Document
+	opts := &options.SplitOptions{Options: ss.Options, TopLevel: true, Tags: []string{"split"}}
+
+	documents, err := split.Split("cdx", opts)
+	ss.Require().NoError(err)
+	ss.Require().NotEmpty(documents)
+
+	for _, document := range documents {
+		stored, err := ss.Backend.GetDocumentByID(document.GetMetadata().GetId())
+		ss.Require().NoError(err)
+		ss.Require().NotNil(stored)
+		ss.Require().Len(stored.GetNodeList().GetRootElements(), 1)
+
+		root := stored.GetNodeList().GetRootElements()[0]
+		ss.NotNil(parent.GetNodeList().GetNodeByID(root))
+
+		// The split point is linked to the new document.
+		links, err := ss.Backend.GetNodeAnnotations(root, db.LinkToAnnotation)
+		ss.Require().NoError(err)
+		ss.Require().Len(links, 1)
+		ss.Equal(document.GetMetadata().GetId(), links[0].Value)
+
+		tags, err := ss.Backend.GetDocumentTags(document.GetMetadata().GetId())
+		ss.Require().NoError(err)
+		ss.ElementsMatch([]string{"split", "tag1", "tag2"}, tags)
+	}
+
+	// The split points shared by the original and new documents make no cycle.
+	problems, err := link.CheckLinks(ss.Backend, &options.LinkOptions{Options: ss.Options})
+	ss.Require().NoError(err)
+	ss.Empty(problems)
+
+	// The original document is otherwise unchanged.
+	original, err := ss.Backend.GetDocumentByIDOrAlias("cdx")
+	ss.Require().NoError(err)
+	ss.Equal(parent.GetMetadata().GetId(), original.GetMetadata().GetId())
+	ss.Len(original.GetNodeList().GetNodes(), len(parent.GetNodeList().GetNodes()))
+
+	_, err = split.Split("cdx", &options.SplitOptions{Options: ss.Options, PurlTypes: []string{"pypi"}})
+	ss.Require().Error(err)
+
+	_, err = split.Split("urn:uuid:nonexistent", opts)
+	ss.Require().Error(err)
+}
+
+func TestSplitSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(splitSuite))
+}
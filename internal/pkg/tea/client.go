@@ -0,0 +1,237 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/tea/client.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+// Package tea implements the lookup side of the Transparency Exchange API (TEA), which publishes SBOMs,
+// VEX documents and other artifacts for products and their components.
+package tea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Artifact types defined by the Transparency Exchange API.
+const (
+	ArtifactTypeAttestation     = "ATTESTATION"
+	ArtifactTypeBOM             = "BOM"
+	ArtifactTypeVulnerabilities = "VULNERABILITIES"
+
+	// IdentifierTypePURL is the identifier type used to look up products by package URL.
+	IdentifierTypePURL = "PURL"
+)
+
+var (
+	errInvalidURL       = errors.New("invalid TEA URL")
+	errUnexpectedPage   = errors.New("unexpected page of results")
+	errUnexpectedStatus = errors.New("unexpected response status")
+
+	// ErrNotFound is returned when the TEA server has no record of the requested object.
+	ErrNotFound = errors.New("not found")
+)
+
+type (
+	// Client looks up products, releases and artifact collections from a TEA server.
+	Client struct {
+		httpClient *http.Client
+		baseURL    *url.URL
+	}
+
+	// Identifier is an external identifier of a product or release, such as a purl or CPE.
+	Identifier struct {
+		IDType  string `json:"idType"`
+		IDValue string `json:"idValue"`
+	}
+
+	// Product is a TEA product, made of one or more components.
+	Product struct {
+		UUID        string         `json:"uuid"`
+		Name        string         `json:"name"`
+		Identifiers []Identifier   `json:"identifiers"`
+		Components  []ComponentRef `json:"components"`
+	}
+
+	// ComponentRef references a component of a product, optionally pinned to one of its releases.
+	ComponentRef struct {
+		UUID    string `json:"uuid"`
+		Release string `json:"release,omitempty"`
+	}
+
+	// Release is a version of a component.
+	Release struct {
+		UUID        string       `json:"uuid"`
+		Version     string       `json:"version"`
+		Identifiers []Identifier `json:"identifiers"`
+		PreRelease  bool         `json:"preRelease"`
+	}
+
+	// Collection is the set of artifacts published for a release.
+	Collection struct {
+		UUID      string     `json:"uuid"`
+		Artifacts []Artifact `json:"artifacts"`
+		Version   int        `json:"version"`
+	}
+
+	// Artifact is a document published for a release, such as an SBOM or VEX, in one or more formats.
+	Artifact struct {
+		UUID    string           `json:"uuid"`
+		Name    string           `json:"name"`
+		Type    string           `json:"type"`
+		Formats []ArtifactFormat `json:"formats"`
+	}
+
+	// ArtifactFormat is a downloadable representation of an artifact.
+	ArtifactFormat struct {
+		MediaType    string     `json:"mediaType"`
+		Description  string     `json:"description"`
+		URL          string     `json:"url"`
+		SignatureURL string     `json:"signatureUrl"`
+		Checksums    []Checksum `json:"checksums"`
+	}
+
+	// Checksum is a digest of an artifact format.
+	Checksum struct {
+		AlgType  string `json:"algType"`
+		AlgValue string `json:"algValue"`
+	}
+
+	productsResponse struct {
+		PageStartIndex *int      `json:"pageStartIndex"`
+		TotalResults   *int      `json:"totalResults"`
+		Results        []Product `json:"results"`
+	}
+
+	releasesResponse struct {
+		Releases []Release `json:"releases"`
+	}
+)
+
+// NewClient returns a client for the TEA server with the given base URL, which includes the API version
+// path, such as https://tea.example.com/tea/v1.
+func NewClient(baseURL string) (*Client, error) {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidURL, err)
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("%w: %s", errInvalidURL, baseURL)
+	}
+
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+
+	return &Client{httpClient: http.DefaultClient, baseURL: parsed}, nil
+}
+
+// SearchProducts returns the products having the given identifier. The results are paginated by the server,
+// so pages are requested until all of the results it reports have been read, or until a page adds no new
+// results for servers that do not report their number or ignore the requested page.
+func (client *Client) SearchProducts(ctx context.Context, idType, idValue string) ([]Product, error) {
+	products := []Product{}
+	seen := map[string]bool{}
+
+	for {
+		response := &productsResponse{}
+
+		query := url.Values{"idType": {idType}, "idValue": {idValue}, "pageOffset": {strconv.Itoa(len(products))}}
+		if err := client.get(ctx, "/products", query, response); err != nil {
+			return nil, err
+		}
+
+		if response.PageStartIndex != nil && *response.PageStartIndex != len(products) {
+			return nil, fmt.Errorf("%w: page starts at result %d, expected %d",
+				errUnexpectedPage, *response.PageStartIndex, len(products))
+		}
+
+		added := 0
+
+		for _, product := range response.Results {
+			if seen[product.UUID] {
+				continue
+			}
+
+			seen[product.UUID] = true
+			products = append(products, product)
+			added++
+		}
+
+		if added == 0 || (response.TotalResults != nil && len(products) >= *response.TotalResults) {
+			return products, nil
+		}
+	}
+}
+
+// ComponentReleases returns the releases of the component with the given UUID.
+func (client *Client) ComponentReleases(ctx context.Context, componentUUID string) ([]Release, error) {
+	response := &releasesResponse{}
+
+	if err := client.get(ctx, "/component/"+url.PathEscape(componentUUID)+"/releases", nil, response); err != nil {
+		return nil, err
+	}
+
+	return response.Releases, nil
+}
+
+// LatestCollection returns the latest artifact collection of the release with the given UUID.
+func (client *Client) LatestCollection(ctx context.Context, releaseUUID string) (*Collection, error) {
+	collection := &Collection{}
+
+	if err := client.get(ctx, "/release/"+url.PathEscape(releaseUUID)+"/collection/latest", nil, collection); err != nil {
+		return nil, err
+	}
+
+	return collection, nil
+}
+
+func (client *Client) get(ctx context.Context, path string, query url.Values, response any) error {
+	requestURL := client.baseURL.JoinPath(path)
+	requestURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed creating request to %s: %w", requestURL, err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed request to %s: %w", requestURL, err)
+	}
+
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("%w: %s", ErrNotFound, requestURL)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%w from %s: %s", errUnexpectedStatus, requestURL, resp.Status)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("error reading response from %s: %w", requestURL, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/tea/client_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package tea_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/tea"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+const (
+	testPurl    = "pkg:npm/example/component@1.0.0"
+	testVersion = "1.0.0"
+)
+
+type teaClientSuite struct {
+	suite.Suite
+	*tea.Client
+	*httptest.Server
+}
+
+func (tcs *teaClientSuite) SetupSuite() {
+	var err error
+
+	tcs.Server = testutil.NewTEAServer(
+		testutil.TEARelease{Purl: testPurl, Version: testVersion},
+		testutil.TEARelease{Purl: testPurl, Version: testVersion, MissingComponent: true},
+	)
+
+	tcs.Client, err = tea.NewClient(tcs.Server.URL + testutil.TEAPath + "/")
+	tcs.Require().NoError(err)
+}
+
+func (tcs *teaClientSuite) TearDownSuite() {
+	tcs.Server.Close()
+}
+
+func (tcs *teaClientSuite) TestNewClient() {
+	for _, subtest := range []struct {
+		name    string
+		baseURL string
+		wantErr bool
+	}{
+		{name: "https", baseURL: "https://tea.example.com/tea/v1"},
+		{name: "unsupported scheme", baseURL: "oci://tea.example.com", wantErr: true},
+		{name: "invalid", baseURL: "http://[::1", wantErr: true},
+	} {
+		tcs.Run(subtest.name, func() {
+			_, err := tea.NewClient(subtest.baseURL)
+			if subtest.wantErr {
+				tcs.Require().Error(err)
+			} else {
+				tcs.Require().NoError(err)
+			}
+		})
+	}
+}
+
+func (tcs *teaClientSuite) TestLookup() {
+	ctx := context.Background()
+
+	// The stub server lists one product per page.
+	products, err := tcs.SearchProducts(ctx, tea.IdentifierTypePURL, testPurl)
+	tcs.Require().NoError(err)
+	tcs.Require().Len(products, 2)
+	tcs.NotEqual(products[0].UUID, products[1].UUID)
+	tcs.Equal([]tea.Identifier{{IDType: tea.IdentifierTypePURL, IDValue: testPurl}}, products[0].Identifiers)
+	tcs.Require().Len(products[0].Components, 1)
+
+	releases, err := tcs.ComponentReleases(ctx, products[0].Components[0].UUID)
+	tcs.Require().NoError(err)
+	tcs.Require().NotEmpty(releases)
+	tcs.Equal(testVersion, releases[0].Version)
+
+	collection, err := tcs.LatestCollection(ctx, releases[0].UUID)
+	tcs.Require().NoError(err)
+	tcs.Require().Len(collection.Artifacts, 2)
+	tcs.Equal(tea.ArtifactTypeBOM, collection.Artifacts[0].Type)
+	tcs.Equal(tea.ArtifactTypeVulnerabilities, collection.Artifacts[1].Type)
+	tcs.Equal(tcs.Server.URL+"/artifacts/0/bom.cdx.json", collection.Artifacts[0].Formats[0].URL)
+}
+
+func (tcs *teaClientSuite) TestLookupNotFound() {
+	ctx := context.Background()
+
+	products, err := tcs.SearchProducts(ctx, tea.IdentifierTypePURL, "pkg:npm/unknown@1.0.0")
+	tcs.Require().NoError(err)
+	tcs.Empty(products)
+
+	_, err = tcs.ComponentReleases(ctx, "unknown")
+	tcs.Require().ErrorIs(err, tea.ErrNotFound)
+
+	products, err = tcs.SearchProducts(ctx, tea.IdentifierTypePURL, testPurl)
+	tcs.Require().NoError(err)
+	tcs.Require().Len(products, 2)
+
+	_, err = tcs.ComponentReleases(ctx, products[1].Components[0].UUID)
+	tcs.Require().ErrorIs(err, tea.ErrNotFound)
+
+	_, err = tcs.LatestCollection(ctx, "unknown")
+	tcs.Require().ErrorIs(err, tea.ErrNotFound)
+}
+
+func (tcs *teaClientSuite) TestSearchProductsPaging() {
+	ctx := context.Background()
+	products := []map[string]any{{"uuid": "product-0"}, {"uuid": "product-1"}, {"uuid": "product-2"}}
+
+	for _, data := range []struct {
+		page func(offset int) map[string]any
+		name string
+	}{
+		{
+			name: "without total or start index",
+			page: func(offset int) map[string]any {
+				return map[string]any{"results": products[min(offset, len(products)):min(offset+1, len(products))]}
+			},
+		},
+		{
+			name: "ignoring the page offset",
+			page: func(int) map[string]any {
+				return map[string]any{"results": products, "totalResults": len(products) + 1}
+			},
+		},
+	} {
+		tcs.Run(data.name, func() {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				offset, err := strconv.Atoi(r.URL.Query().Get("pageOffset"))
+				tcs.Require().NoError(err)
+				tcs.Require().NoError(json.NewEncoder(w).Encode(data.page(offset)))
+			}))
+			defer server.Close()
+
+			client, err := tea.NewClient(server.URL + testutil.TEAPath)
+			tcs.Require().NoError(err)
+
+			found, err := client.SearchProducts(ctx, tea.IdentifierTypePURL, testPurl)
+			tcs.Require().NoError(err)
+			tcs.Require().Len(found, len(products))
+			tcs.Equal("product-2", found[2].UUID)
+		})
+	}
+}
+
+func TestTEAClientSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(teaClientSuite))
+}
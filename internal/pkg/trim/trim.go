@@ -0,0 +1,263 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/trim/trim.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package trim
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/protobom/protobom/pkg/sbom"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/outpututil"
+)
+
+var (
+	errCopyFailed       = errors.New("failed to copy document")
+	errDocumentNotFound = errors.New("document not found")
+	errNegativeDepth    = errors.New("depth must not be negative")
+	errNoRules          = errors.New("at least one purl type, node ID or depth must be specified")
+	errNothingTrimmed   = errors.New("no nodes matched the trim rules")
+)
+
+// Rules describes which subtrees to remove from a document.
+type Rules struct {
+	purlTypes []string
+	nodeIDs   []string
+	maxDepth  int
+}
+
+// Trim removes the subtrees selected by opts from the document with the specified ID or alias, and stores
+// the result as a new revision of it. The original document remains unchanged and is referenced as the
+// base document of the revision.
+func Trim(sbomID string, opts *options.TrimOptions) (*sbom.Document, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	rules, err := NewRules(opts.PurlTypes, opts.NodeIDs, opts.MaxDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := backend.GetDocumentByIDOrAlias(sbomID)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if base == nil {
+		return nil, fmt.Errorf("%w: %s", errDocumentNotFound, sbomID)
+	}
+
+	tags, err := backend.GetDocumentTags(base.GetMetadata().GetId())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	trimmed, err := rules.Apply(base)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed.Metadata.Id = uuid.New().URN()
+	trimmed.Metadata.Date = timestamppb.Now()
+
+	backend.Logger.Info("Storing trimmed revision", "baseID", base.GetMetadata().GetId(),
+		"id", trimmed.GetMetadata().GetId(),
+		"removed", len(base.GetNodeList().GetNodes())-len(trimmed.GetNodeList().GetNodes()))
+
+	sourceData, err := outpututil.Serialize(trimmed)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if err := backend.StoreDocument(
+		trimmed, db.WithRevisedDocumentAnnotations(base), db.WithSourceDataAnnotations(sourceData),
+	); err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if err := backend.AddDocumentAnnotations(trimmed.GetMetadata().GetId(), db.TagAnnotation, tags...); err != nil {
+		opts.Logger.Warn("Tag(s) could not be set.", "err", err)
+	}
+
+	return trimmed, nil
+}
+
+// NewRules returns rules that remove the nodes with any of the given purl types or node IDs, along with
+// the nodes deeper than maxDepth from the root elements. A maxDepth of 0 disables the depth limit.
+func NewRules(purlTypes, nodeIDs []string, maxDepth int) (*Rules, error) {
+	if maxDepth < 0 {
+		return nil, fmt.Errorf("%w: %d", errNegativeDepth, maxDepth)
+	}
+
+	if len(purlTypes) == 0 && len(nodeIDs) == 0 && maxDepth == 0 {
+		return nil, errNoRules
+	}
+
+	rules := &Rules{nodeIDs: slices.Clone(nodeIDs), maxDepth: maxDepth}
+
+	for _, purlType := range purlTypes {
+		rules.purlTypes = append(rules.purlTypes, strings.TrimPrefix(strings.ToLower(purlType), "pkg:"))
+	}
+
+	return rules, nil
+}
+
+// Apply returns a copy of document with the selected nodes removed, along with any nodes that are no
+// longer reachable from the root elements as a result. Edges and root elements referencing removed
+// nodes are rewritten. The original document is not modified.
+func (rules *Rules) Apply(document *sbom.Document) (*sbom.Document, error) {
+	trimmed, ok := proto.Clone(document).(*sbom.Document)
+	if !ok {
+		return nil, errCopyFailed
+	}
+
+	nodeList := trimmed.GetNodeList()
+	if nodeList == nil {
+		return nil, errNothingTrimmed
+	}
+
+	children := childMap(nodeList.GetEdges())
+	roots := rootIDs(nodeList)
+	depths := reachable(roots, children, nil)
+
+	removed := map[string]bool{}
+
+	for _, node := range nodeList.GetNodes() {
+		depth, seen := depths[node.GetId()]
+
+		if rules.matches(node) || (rules.maxDepth > 0 && seen && depth > rules.maxDepth) {
+			removed[node.GetId()] = true
+		}
+	}
+
+	if len(removed) == 0 {
+		return nil, errNothingTrimmed
+	}
+
+	// Nodes that were reachable before but not after removing the selected nodes are orphans.
+	remaining := reachable(roots, children, removed)
+
+	for id := range depths {
+		if _, ok := remaining[id]; !ok {
+			removed[id] = true
+		}
+	}
+
+	nodeList.Nodes = slices.DeleteFunc(nodeList.Nodes, func(node *sbom.Node) bool {
+		return removed[node.GetId()]
+	})
+
+	nodeList.RootElements = slices.DeleteFunc(nodeList.RootElements, func(id string) bool {
+		return removed[id]
+	})
+
+	nodeList.Edges = slices.DeleteFunc(nodeList.Edges, func(edge *sbom.Edge) bool {
+		edge.To = slices.DeleteFunc(edge.To, func(id string) bool { return removed[id] })
+
+		return removed[edge.GetFrom()] || len(edge.GetTo()) == 0
+	})
+
+	return trimmed, nil
+}
+
+func (rules *Rules) matches(node *sbom.Node) bool {
+	if slices.Contains(rules.nodeIDs, node.GetId()) {
+		return true
+	}
+
+	return slices.Contains(rules.purlTypes, purlType(node))
+}
+
+func childMap(edges []*sbom.Edge) map[string][]string {
+	children := map[string][]string{}
+
+	for _, edge := range edges {
+		children[edge.GetFrom()] = append(children[edge.GetFrom()], edge.GetTo()...)
+	}
+
+	return children
+}
+
+// rootIDs returns the root elements of the node list, or the nodes without incoming edges if it has none.
+func rootIDs(nodeList *sbom.NodeList) []string {
+	if len(nodeList.GetRootElements()) > 0 {
+		return nodeList.GetRootElements()
+	}
+
+	targets := map[string]bool{}
+
+	for _, edge := range nodeList.GetEdges() {
+		for _, to := range edge.GetTo() {
+			targets[to] = true
+		}
+	}
+
+	roots := []string{}
+
+	for _, node := range nodeList.GetNodes() {
+		if !targets[node.GetId()] {
+			roots = append(roots, node.GetId())
+		}
+	}
+
+	return roots
+}
+
+// reachable performs a breadth-first traversal from the root nodes, skipping excluded nodes, and returns
+// the depth of each node reached.
+func reachable(roots []string, children map[string][]string, excluded map[string]bool) map[string]int {
+	depths := map[string]int{}
+	queue := []string{}
+
+	for _, root := range roots {
+		if _, seen := depths[root]; !seen && !excluded[root] {
+			depths[root] = 0
+			queue = append(queue, root)
+		}
+	}
+
+	for ; len(queue) > 0; queue = queue[1:] {
+		current := queue[0]
+
+		for _, child := range children[current] {
+			if _, seen := depths[child]; !seen && !excluded[child] {
+				depths[child] = depths[current] + 1
+				queue = append(queue, child)
+			}
+		}
+	}
+
+	return depths
+}
+
+func purlType(node *sbom.Node) string {
+	purlType, _, _ := strings.Cut(strings.TrimPrefix(string(node.Purl()), "pkg:"), "/")
+
+	return purlType
+}
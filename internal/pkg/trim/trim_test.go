@@ -0,0 +1,134 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/trim/trim_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package trim_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/trim"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+type trimSuite struct {
+	testutil.BackendSuite
+}
+
+func (ts *trimSuite) TestNewRules() {
+	_, err := trim.NewRules(nil, nil, 0)
+	ts.Require().Error(err)
+
+	_, err = trim.NewRules(nil, nil, -1)
+	ts.Require().Error(err)
+
+	_, err = trim.NewRules([]string{"pkg:NPM"}, nil, 0)
+	ts.Require().NoError(err)
+}
+
+func (ts *trimSuite) TestApply() {
+	for _, subtest := range []struct {
+		name      string
+		purlTypes []string
+		nodeIDs   []string
+		expected  []string
+		maxDepth  int
+		noRoots   bool
+		wantErr   bool
+	}{
+		{name: "purl type", purlTypes: []string{"npm"}, expected: []string{"app", "b", "d", "e"}},
+		{name: "purl type with prefix", purlTypes: []string{"pkg:npm"}, expected: []string{"app", "b", "d", "e"}},
+		{name: "shared subtree kept", nodeIDs: []string{"b"}, expected: []string{"app", "a", "c", "d", "e"}},
+		{name: "orphans removed", nodeIDs: []string{"a", "b"}, expected: []string{"app"}},
+		{name: "depth", maxDepth: 1, expected: []string{"app", "a", "b"}},
+		{name: "combined", purlTypes: []string{"npm"}, maxDepth: 2, expected: []string{"app", "b", "d"}},
+		{name: "no root elements", purlTypes: []string{"npm"}, noRoots: true, expected: []string{"app", "b", "d", "e"}},
+		{name: "no match", purlTypes: []string{"pypi"}, wantErr: true},
+	} {
+		ts.Run(subtest.name, func() {
+			document := testutil.NewTestDocument()
+			if subtest.noRoots {
+				document.NodeList.RootElements = nil
+			}
+
+			rules, err := trim.NewRules(subtest.purlTypes, subtest.nodeIDs, subtest.maxDepth)
+			ts.Require().NoError(err)
+
+			trimmed, err := rules.Apply(document)
+			if subtest.wantErr {
+				ts.Require().Error(err)
+
+				return
+			}
+
+			ts.Require().NoError(err)
+			ts.ElementsMatch(subtest.expected, testutil.NodeIDs(trimmed.GetNodeList()))
+
+			// Edges only reference remaining nodes.
+			for _, edge := range trimmed.GetNodeList().GetEdges() {
+				ts.Contains(subtest.expected, edge.GetFrom())
+				ts.NotEmpty(edge.GetTo())
+				ts.Subset(subtest.expected, edge.GetTo())
+			}
+
+			// The original document is unchanged.
+			ts.Len(document.GetNodeList().GetNodes(), 6)
+		})
+	}
+}
+
+func (ts *trimSuite) TestTrim() {
+	base := ts.DocumentInfo[0].Document
+	removed := base.GetNodeList().GetEdges()[0].GetTo()[0]
+	opts := &options.TrimOptions{Options: ts.Options, NodeIDs: []string{removed}}
+
+	trimmed, err := trim.Trim("cdx", opts)
+	ts.Require().NoError(err)
+
+	ts.NotEqual(base.GetMetadata().GetId(), trimmed.GetMetadata().GetId())
+
+	stored, err := ts.Backend.GetDocumentByID(trimmed.GetMetadata().GetId())
+	ts.Require().NoError(err)
+	ts.Require().NotNil(stored)
+
+	ts.Len(stored.GetNodeList().GetNodes(), len(base.GetNodeList().GetNodes())-1)
+	ts.Nil(stored.GetNodeList().GetNodeByID(removed))
+
+	revisions, err := ts.Backend.GetDocumentRevisions(trimmed.GetMetadata().GetId())
+	ts.Require().NoError(err)
+	ts.Require().Len(revisions, 2)
+	ts.Equal(base.GetMetadata().GetId(), revisions[0].GetMetadata().GetId())
+
+	// The alias moves to the trimmed revision, and its tags are copied.
+	ts.Equal("cdx", ts.Backend.GetDocumentAlias(trimmed.GetMetadata().GetId()))
+
+	tags, err := ts.Backend.GetDocumentTags(trimmed.GetMetadata().GetId())
+	ts.Require().NoError(err)
+	ts.ElementsMatch([]string{"tag1", "tag2"}, tags)
+
+	_, err = trim.Trim("urn:uuid:nonexistent", opts)
+	ts.Require().Error(err)
+}
+
+func TestTrimSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(trimSuite))
+}
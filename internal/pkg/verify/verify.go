@@ -0,0 +1,270 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/verify/verify.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package verify
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/sign"
+)
+
+const (
+	StatusFail    = "fail"
+	StatusPass    = "pass"
+	StatusSkipped = "skipped"
+
+	// SourceAnnotation is the source of signatures stored with a document by the sign command.
+	SourceAnnotation = "annotation"
+)
+
+var (
+	errDocumentNotFound = errors.New("document not found")
+	errInvalidBundle    = errors.New("invalid Sigstore bundle")
+)
+
+type (
+	// Check is the outcome of a single verification of a document.
+	Check struct {
+		Status  string `json:"status"`
+		Message string `json:"message,omitempty"`
+	}
+
+	// SignatureCheck is the outcome of verifying one signature of a document.
+	SignatureCheck struct {
+		Check
+		Source  string `json:"source"`
+		KeyHint string `json:"keyHint,omitempty"`
+	}
+
+	signature struct {
+		bundle *sign.Bundle
+		source string
+	}
+
+	// Result reports the verification of a document. A document fails verification if its original content
+	// no longer matches its stored digest, if any signature is invalid, or if public keys were given and no
+	// signature could be verified with them.
+	Result struct {
+		Digest     Check            `json:"digest"`
+		DocumentID string           `json:"documentId"`
+		Alias      string           `json:"alias,omitempty"`
+		Status     string           `json:"status"`
+		Message    string           `json:"message,omitempty"`
+		Signatures []SignatureCheck `json:"signatures"`
+	}
+)
+
+// Passed reports whether the document passed verification.
+func (result *Result) Passed() bool {
+	return result.Status == StatusPass
+}
+
+// Verify checks the integrity of the original content of the document with the specified ID or alias, along
+// with its stored signatures and any signature or bundle file given in opts, against publicKeys.
+func Verify(sbomID string, publicKeys []crypto.PublicKey, opts *options.VerifyOptions) (*Result, error) {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	document, err := backend.GetDocumentByIDOrAlias(sbomID)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	if document == nil {
+		return nil, fmt.Errorf("%w: %s", errDocumentNotFound, sbomID)
+	}
+
+	documentID := document.GetMetadata().GetId()
+	result := &Result{
+		DocumentID: documentID,
+		Alias:      backend.GetDocumentAlias(documentID),
+		Signatures: []SignatureCheck{},
+	}
+
+	opts.Logger.Info("Verifying document", "sbomID", documentID)
+
+	if result.Digest, err = checkDigest(documentID, backend); err != nil {
+		return nil, err
+	}
+
+	bundles, err := sign.StoredBundles(documentID, opts.Options)
+	if err != nil {
+		return nil, fmt.Errorf("%w", err)
+	}
+
+	signatures := []signature{}
+
+	// Stored signatures are sorted so that results are reported in a stable order.
+	for _, value := range slices.Sorted(maps.Keys(bundles)) {
+		signatures = append(signatures, signature{bundles[value], SourceAnnotation})
+	}
+
+	files, err := readSignatureFiles(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Signatures are verified against the original content stored with the document, the same bytes that were
+	// signed, rather than a serialization of the document as it is cached.
+	payload, payloadErr := sign.Payload(document, opts.Options)
+
+	for _, sig := range append(signatures, files...) {
+		bundle := sig.bundle
+		check := SignatureCheck{Source: sig.source, KeyHint: bundle.VerificationMaterial.PublicKey.Hint}
+
+		if payloadErr != nil {
+			check.Check = Check{Status: StatusFail, Message: payloadErr.Error()}
+		} else {
+			check.Check = CheckSignature(bundle, payload, publicKeys)
+		}
+
+		result.Signatures = append(result.Signatures, check)
+	}
+
+	result.Status, result.Message = status(result, len(publicKeys) > 0)
+
+	return result, nil
+}
+
+func checkDigest(documentID string, backend *db.Backend) (Check, error) {
+	sourceData, err := backend.GetDocumentUniqueAnnotation(documentID, db.SourceDataAnnotation)
+	if err != nil {
+		return Check{}, fmt.Errorf("%w", err)
+	}
+
+	if sourceData == "" {
+		return Check{Status: StatusSkipped, Message: "no original content is stored"}, nil
+	}
+
+	sourceHash, err := backend.GetDocumentUniqueAnnotation(documentID, db.SourceHashAnnotation)
+	if err != nil {
+		return Check{}, fmt.Errorf("%w", err)
+	}
+
+	if digest := sha256.Sum256([]byte(sourceData)); sourceHash != string(digest[:]) {
+		return Check{Status: StatusFail, Message: "original content does not match its stored SHA-256 digest"}, nil
+	}
+
+	return Check{Status: StatusPass}, nil
+}
+
+// CheckSignature verifies the signature in bundle over payload with publicKeys. Keys are matched to the bundle
+// by its key hint, and the check is skipped if none of them match or no keys are given.
+func CheckSignature(bundle *sign.Bundle, payload []byte, publicKeys []crypto.PublicKey) Check {
+	if len(publicKeys) == 0 {
+		return Check{Status: StatusSkipped, Message: "no public key given"}
+	}
+
+	hint := bundle.VerificationMaterial.PublicKey.Hint
+	candidates := []crypto.PublicKey{}
+
+	for _, publicKey := range publicKeys {
+		if keyHint, err := sign.KeyHint(publicKey); hint == "" || (err == nil && keyHint == hint) {
+			candidates = append(candidates, publicKey)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Check{Status: StatusSkipped, Message: "signed with another key"}
+	}
+
+	var err error
+
+	for _, publicKey := range candidates {
+		if err = sign.VerifyPayload(publicKey, payload, bundle); err == nil {
+			return Check{Status: StatusPass}
+		}
+	}
+
+	return Check{Status: StatusFail, Message: err.Error()}
+}
+
+// readSignatureFiles returns the signatures of the detached signature and bundle files given in opts.
+func readSignatureFiles(opts *options.VerifyOptions) ([]signature, error) {
+	signatures := []signature{}
+
+	if opts.SignaturePath != "" {
+		data, err := os.ReadFile(opts.SignaturePath)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		// Detached signatures written by cosign and bomctl are base64 encoded, but raw signatures are accepted too.
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		if err != nil {
+			decoded = data
+		}
+
+		bundle := &sign.Bundle{MessageSignature: sign.MessageSignature{Signature: decoded}}
+		signatures = append(signatures, signature{bundle, opts.SignaturePath})
+	}
+
+	if opts.BundlePath != "" {
+		data, err := os.ReadFile(opts.BundlePath)
+		if err != nil {
+			return nil, fmt.Errorf("%w", err)
+		}
+
+		bundle := &sign.Bundle{}
+		if err := json.Unmarshal(data, bundle); err != nil || len(bundle.MessageSignature.Signature) == 0 {
+			return nil, fmt.Errorf("%w: %s", errInvalidBundle, opts.BundlePath)
+		}
+
+		signatures = append(signatures, signature{bundle, opts.BundlePath})
+	}
+
+	return signatures, nil
+}
+
+func status(result *Result, keysGiven bool) (status, message string) {
+	if result.Digest.Status == StatusFail {
+		return StatusFail, result.Digest.Message
+	}
+
+	passed := false
+
+	for _, check := range result.Signatures {
+		switch check.Status {
+		case StatusFail:
+			return StatusFail, fmt.Sprintf("signature from %s: %s", check.Source, check.Message)
+		case StatusPass:
+			passed = true
+		}
+	}
+
+	if keysGiven && !passed {
+		return StatusFail, "no signature could be verified with the given key(s)"
+	}
+
+	return StatusPass, ""
+}
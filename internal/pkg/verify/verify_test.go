@@ -0,0 +1,249 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/verify/verify_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package verify_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/sign"
+	"github.com/bomctl/bomctl/internal/pkg/trim"
+	"github.com/bomctl/bomctl/internal/pkg/verify"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+type verifySuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	signingKey   *ecdsa.PrivateKey
+	otherKey     ed25519.PrivateKey
+	bundle       *sign.Bundle
+	documentInfo []testutil.DocumentInfo
+}
+
+func (vs *verifySuite) SetupSuite() {
+	var err error
+
+	vs.Backend, err = testutil.NewTestBackend()
+	vs.Require().NoError(err, "failed database backend creation")
+
+	vs.documentInfo, err = testutil.AddTestDocuments(vs.Backend)
+	vs.Require().NoError(err, "failed database backend setup")
+
+	vs.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, vs.Backend))
+
+	vs.signingKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	vs.Require().NoError(err)
+
+	_, vs.otherKey, err = ed25519.GenerateKey(rand.Reader)
+	vs.Require().NoError(err)
+
+	vs.bundle, err = sign.Sign("cdx", vs.signingKey, &options.SignOptions{Options: vs.Options})
+	vs.Require().NoError(err)
+}
+
+func (vs *verifySuite) TearDownSuite() {
+	vs.Backend.CloseClient()
+}
+
+func (vs *verifySuite) writeFile(data []byte) string {
+	path := filepath.Join(vs.T().TempDir(), "signature")
+	vs.Require().NoError(os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func (vs *verifySuite) TestVerify() {
+	for _, subtest := range []struct {
+		name       string
+		status     string
+		publicKeys []crypto.PublicKey
+		expected   []string
+	}{
+		{name: "no keys", expected: []string{verify.StatusSkipped}, status: verify.StatusPass},
+		{
+			name:       "signing key",
+			publicKeys: []crypto.PublicKey{vs.signingKey.Public()},
+			expected:   []string{verify.StatusPass},
+			status:     verify.StatusPass,
+		},
+		{
+			name:       "other key",
+			publicKeys: []crypto.PublicKey{vs.otherKey.Public()},
+			expected:   []string{verify.StatusSkipped},
+			status:     verify.StatusFail,
+		},
+		{
+			name:       "both keys",
+			publicKeys: []crypto.PublicKey{vs.otherKey.Public(), vs.signingKey.Public()},
+			expected:   []string{verify.StatusPass},
+			status:     verify.StatusPass,
+		},
+	} {
+		vs.Run(subtest.name, func() {
+			result, err := verify.Verify("cdx", subtest.publicKeys, &options.VerifyOptions{Options: vs.Options})
+			vs.Require().NoError(err)
+
+			vs.Equal(vs.documentInfo[0].Document.GetMetadata().GetId(), result.DocumentID)
+			vs.Equal("cdx", result.Alias)
+			vs.Equal(verify.StatusPass, result.Digest.Status)
+			vs.Equal(subtest.status, result.Status)
+			vs.Equal(subtest.status == verify.StatusPass, result.Passed())
+
+			statuses := []string{}
+
+			for _, check := range result.Signatures {
+				vs.Equal(verify.SourceAnnotation, check.Source)
+				statuses = append(statuses, check.Status)
+			}
+
+			vs.Equal(subtest.expected, statuses)
+		})
+	}
+}
+
+func (vs *verifySuite) TestVerifyFiles() {
+	publicKeys := []crypto.PublicKey{vs.signingKey.Public()}
+	detached := base64.StdEncoding.EncodeToString(vs.bundle.MessageSignature.Signature) + "\n"
+
+	bundle, err := json.Marshal(vs.bundle)
+	vs.Require().NoError(err)
+
+	for _, subtest := range []struct {
+		opts    *options.VerifyOptions
+		name    string
+		status  string
+		wantErr bool
+	}{
+		{
+			name:   "detached signature",
+			opts:   &options.VerifyOptions{SignaturePath: vs.writeFile([]byte(detached))},
+			status: verify.StatusPass,
+		},
+		{
+			name:   "raw signature",
+			opts:   &options.VerifyOptions{SignaturePath: vs.writeFile(vs.bundle.MessageSignature.Signature)},
+			status: verify.StatusPass,
+		},
+		{
+			name:   "invalid signature",
+			opts:   &options.VerifyOptions{SignaturePath: vs.writeFile([]byte("c2lnbmF0dXJl"))},
+			status: verify.StatusFail,
+		},
+		{
+			name:   "bundle",
+			opts:   &options.VerifyOptions{BundlePath: vs.writeFile(bundle)},
+			status: verify.StatusPass,
+		},
+		{
+			name:    "invalid bundle",
+			opts:    &options.VerifyOptions{BundlePath: vs.writeFile([]byte("{}"))},
+			wantErr: true,
+		},
+		{
+			name:    "missing file",
+			opts:    &options.VerifyOptions{SignaturePath: filepath.Join(vs.T().TempDir(), "missing.sig")},
+			wantErr: true,
+		},
+	} {
+		vs.Run(subtest.name, func() {
+			subtest.opts.Options = vs.Options
+
+			result, err := verify.Verify("cdx", publicKeys, subtest.opts)
+			if subtest.wantErr {
+				vs.Require().Error(err)
+
+				return
+			}
+
+			vs.Require().NoError(err)
+			vs.Require().Len(result.Signatures, 2)
+			vs.Equal(verify.SourceAnnotation, result.Signatures[0].Source)
+			vs.NotEqual(verify.SourceAnnotation, result.Signatures[1].Source)
+			vs.Equal(subtest.status, result.Signatures[1].Status)
+			vs.Equal(subtest.status, result.Status)
+		})
+	}
+}
+
+func (vs *verifySuite) TestVerifyDigest() {
+	opts := &options.VerifyOptions{Options: vs.Options}
+	documentID := vs.documentInfo[1].Document.GetMetadata().GetId()
+
+	result, err := verify.Verify(documentID, nil, opts)
+	vs.Require().NoError(err)
+	vs.Equal(verify.StatusPass, result.Digest.Status)
+	vs.Empty(result.Signatures)
+
+	vs.Require().NoError(vs.Backend.SetDocumentUniqueAnnotation(documentID, db.SourceDataAnnotation, "tampered"))
+
+	result, err = verify.Verify(documentID, nil, opts)
+	vs.Require().NoError(err)
+	vs.Equal(verify.StatusFail, result.Digest.Status)
+	vs.Equal(verify.StatusFail, result.Status)
+	vs.False(result.Passed())
+
+	_, err = verify.Verify("urn:uuid:nonexistent", nil, opts)
+	vs.Require().Error(err)
+}
+
+func (vs *verifySuite) TestVerifyRevision() {
+	publicKeys := []crypto.PublicKey{vs.signingKey.Public()}
+
+	revision, err := trim.Trim(vs.documentInfo[1].Document.GetMetadata().GetId(), &options.TrimOptions{
+		Options:  vs.Options,
+		MaxDepth: 1,
+	})
+	vs.Require().NoError(err)
+
+	revisionID := revision.GetMetadata().GetId()
+
+	_, err = sign.Sign(revisionID, vs.signingKey, &options.SignOptions{Options: vs.Options})
+	vs.Require().NoError(err)
+
+	// Revisions are verified against the bytes stored when they were made, however often they are read.
+	for range 2 {
+		result, err := verify.Verify(revisionID, publicKeys, &options.VerifyOptions{Options: vs.Options})
+		vs.Require().NoError(err)
+		vs.Equal(verify.StatusPass, result.Digest.Status)
+		vs.Require().Len(result.Signatures, 1)
+		vs.Equal(verify.StatusPass, result.Signatures[0].Status)
+		vs.True(result.Passed())
+	}
+}
+
+func TestVerifySuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(verifySuite))
+}
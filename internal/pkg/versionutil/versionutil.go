@@ -0,0 +1,118 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/versionutil/versionutil.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package versionutil
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Compare compares two version strings, returning -1 if a < b, 0 if a == b and +1 if a > b.
+// Versions are compared using semantic versioning precedence where possible: an optional "v" prefix
+// is ignored, numeric segments are compared numerically and a pre-release sorts before its release.
+// Build metadata is ignored. Segments that are not numeric are compared lexically.
+func Compare(a, b string) int {
+	releaseA, preA := split(a)
+	releaseB, preB := split(b)
+
+	if result := compareSegments(releaseA, releaseB); result != 0 {
+		return result
+	}
+
+	switch {
+	case preA == preB:
+		return 0
+	case preA == "":
+		return 1
+	case preB == "":
+		return -1
+	default:
+		return compareSegments(segments(preA), segments(preB))
+	}
+}
+
+// IsDowngrade reports whether the revised version has a lower precedence than the base version.
+// Empty versions are never considered a downgrade.
+func IsDowngrade(base, revised string) bool {
+	if base == "" || revised == "" {
+		return false
+	}
+
+	return Compare(base, revised) > 0
+}
+
+func split(version string) (release []string, prerelease string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+
+	if idx := strings.Index(version, "+"); idx != -1 {
+		version = version[:idx]
+	}
+
+	if idx := strings.Index(version, "-"); idx != -1 {
+		version, prerelease = version[:idx], version[idx+1:]
+	}
+
+	return segments(version), prerelease
+}
+
+func segments(version string) []string {
+	return strings.FieldsFunc(version, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func compareSegments(a, b []string) int {
+	for idx := range max(len(a), len(b)) {
+		segmentA, segmentB := "0", "0"
+
+		if idx < len(a) {
+			segmentA = a[idx]
+		}
+
+		if idx < len(b) {
+			segmentB = b[idx]
+		}
+
+		if result := compareSegment(segmentA, segmentB); result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+func compareSegment(a, b string) int {
+	numA, errA := strconv.ParseUint(a, 10, 64)
+	numB, errB := strconv.ParseUint(b, 10, 64)
+
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(numA, numB)
+	case errA == nil:
+		// Numeric identifiers have lower precedence than alphanumeric ones.
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
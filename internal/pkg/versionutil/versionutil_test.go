@@ -0,0 +1,68 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/versionutil/versionutil_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package versionutil_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/versionutil"
+)
+
+type versionutilSuite struct {
+	suite.Suite
+}
+
+func (vs *versionutilSuite) TestCompare() {
+	for _, subtest := range []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.0.0", b: "1.0.0", expected: 0},
+		{a: "v1.0.0", b: "1.0.0", expected: 0},
+		{a: "1.0", b: "1.0.0", expected: 0},
+		{a: "1.0.0", b: "1.0.1", expected: -1},
+		{a: "1.10.0", b: "1.9.0", expected: 1},
+		{a: "v0.9.0", b: "v1.0.0", expected: -1},
+		{a: "1.0.0-rc.1", b: "1.0.0", expected: -1},
+		{a: "1.0.0-alpha", b: "1.0.0-beta", expected: -1},
+		{a: "1.0.0-rc.2", b: "1.0.0-rc.10", expected: -1},
+		{a: "1.0.0+build.1", b: "1.0.0+build.2", expected: 0},
+		{a: "1.0.9-r3", b: "1.0.9-r2", expected: 1},
+	} {
+		vs.Run(subtest.a+" "+subtest.b, func() {
+			vs.Equal(subtest.expected, versionutil.Compare(subtest.a, subtest.b))
+			vs.Equal(-subtest.expected, versionutil.Compare(subtest.b, subtest.a))
+		})
+	}
+}
+
+func (vs *versionutilSuite) TestIsDowngrade() {
+	vs.True(versionutil.IsDowngrade("v1.0.0", "v0.9.0"))
+	vs.False(versionutil.IsDowngrade("v0.8.0", "v0.8.1"))
+	vs.False(versionutil.IsDowngrade("", "v0.8.1"))
+	vs.False(versionutil.IsDowngrade("v0.8.1", ""))
+}
+
+func TestVersionutilSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(versionutilSuite))
+}
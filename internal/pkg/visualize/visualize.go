@@ -0,0 +1,314 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/visualize/visualize.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package visualize
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/protobom/protobom/pkg/sbom"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+)
+
+var (
+	errDocumentNotFound = errors.New("document not found")
+	errUnknownFormat    = errors.New("unknown graph format")
+)
+
+type (
+	// Vertex is a node of the rendered graph. A vertex represents either a single SBOM node
+	// or all nodes of a collapsed purl type.
+	Vertex struct {
+		ID          string
+		Label       string
+		Highlighted bool
+	}
+
+	// Arc is a directed edge of the rendered graph.
+	Arc struct {
+		From, To string
+		Type     string
+	}
+
+	// Graph is the subset of a document's dependency graph selected for rendering.
+	Graph struct {
+		Name     string
+		Vertices []*Vertex
+		Arcs     []Arc
+	}
+
+	graphBuilder struct {
+		opts     *options.VisualizeOptions
+		nodes    map[string]*sbom.Node
+		children map[string][]*sbom.Edge
+		vertices map[string]*Vertex
+		arcs     map[Arc]struct{}
+		graph    *Graph
+	}
+)
+
+// Visualize renders the dependency graph of the document with the specified ID or alias,
+// writing it to opts.OutputFile if set or stdout otherwise.
+func Visualize(sbomID string, opts *options.VisualizeOptions) error {
+	backend, err := db.BackendFromContext(opts.Context())
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	document, err := backend.GetDocumentByIDOrAlias(sbomID)
+	if err != nil {
+		return fmt.Errorf("%w", err)
+	}
+
+	if document == nil {
+		return fmt.Errorf("%w: %s", errDocumentNotFound, sbomID)
+	}
+
+	var out io.Writer = os.Stdout
+	if opts.OutputFile != nil {
+		out = opts.OutputFile
+	}
+
+	return NewGraph(document, opts).Write(out, opts.Format)
+}
+
+// NewGraph builds the graph of nodes reachable from the document's root elements, honoring the
+// depth limit, collapsed purl types and highlighted nodes in opts.
+func NewGraph(document *sbom.Document, opts *options.VisualizeOptions) *Graph {
+	builder := &graphBuilder{
+		opts:     opts,
+		nodes:    map[string]*sbom.Node{},
+		children: map[string][]*sbom.Edge{},
+		vertices: map[string]*Vertex{},
+		arcs:     map[Arc]struct{}{},
+		graph:    &Graph{Name: document.GetMetadata().GetId()},
+	}
+
+	for _, node := range document.GetNodeList().GetNodes() {
+		builder.nodes[node.GetId()] = node
+	}
+
+	for _, edge := range document.GetNodeList().GetEdges() {
+		builder.children[edge.GetFrom()] = append(builder.children[edge.GetFrom()], edge)
+	}
+
+	builder.walk(document.GetNodeList().GetRootElements())
+
+	return builder.graph
+}
+
+// Write renders the graph in the given format.
+func (g *Graph) Write(out io.Writer, format string) error {
+	var rendered string
+
+	switch format {
+	case FormatDOT:
+		rendered = g.DOT()
+	case FormatMermaid:
+		rendered = g.Mermaid()
+	default:
+		return fmt.Errorf("%w: %s", errUnknownFormat, format)
+	}
+
+	if _, err := io.WriteString(out, rendered); err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+
+	return nil
+}
+
+// DOT renders the graph as a Graphviz DOT digraph.
+func (g *Graph) DOT() string {
+	builder := &strings.Builder{}
+
+	fmt.Fprintf(builder, "digraph %s {\n", dotQuote(g.Name))
+	builder.WriteString("  rankdir=LR;\n")
+	builder.WriteString("  node [shape=box];\n")
+
+	for _, vertex := range g.Vertices {
+		attrs := "label=" + dotQuote(vertex.Label)
+		if vertex.Highlighted {
+			attrs += ", style=filled, fillcolor=yellow"
+		}
+
+		fmt.Fprintf(builder, "  %s [%s];\n", vertex.ID, attrs)
+	}
+
+	for _, arc := range g.Arcs {
+		fmt.Fprintf(builder, "  %s -> %s [label=%s];\n", arc.From, arc.To, dotQuote(arc.Type))
+	}
+
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+// Mermaid renders the graph as a Mermaid flowchart.
+func (g *Graph) Mermaid() string {
+	builder := &strings.Builder{}
+	highlighted := []string{}
+
+	builder.WriteString("flowchart LR\n")
+
+	for _, vertex := range g.Vertices {
+		fmt.Fprintf(builder, "  %s[\"%s\"]\n", vertex.ID, mermaidEscape(vertex.Label))
+
+		if vertex.Highlighted {
+			highlighted = append(highlighted, vertex.ID)
+		}
+	}
+
+	for _, arc := range g.Arcs {
+		fmt.Fprintf(builder, "  %s -->|%s| %s\n", arc.From, mermaidEscape(arc.Type), arc.To)
+	}
+
+	if len(highlighted) > 0 {
+		builder.WriteString("  classDef highlight fill:#ff0,stroke:#f66,stroke-width:2px\n")
+		fmt.Fprintf(builder, "  class %s highlight\n", strings.Join(highlighted, ","))
+	}
+
+	return builder.String()
+}
+
+// walk performs a breadth-first traversal from the root nodes, adding a vertex for each node
+// within the depth limit and an arc for each edge between them.
+func (b *graphBuilder) walk(roots []string) {
+	depths := map[string]int{}
+	queue := []string{}
+
+	for _, root := range roots {
+		if _, ok := b.nodes[root]; ok {
+			depths[root] = 0
+			queue = append(queue, root)
+			b.vertex(b.nodes[root])
+		}
+	}
+
+	for ; len(queue) > 0; queue = queue[1:] {
+		current := queue[0]
+
+		if b.opts.MaxDepth > 0 && depths[current] >= b.opts.MaxDepth {
+			continue
+		}
+
+		for _, edge := range b.children[current] {
+			for _, to := range edge.GetTo() {
+				child, ok := b.nodes[to]
+				if !ok {
+					continue
+				}
+
+				b.arc(b.vertex(b.nodes[current]), b.vertex(child), edge.GetType().String())
+
+				if _, seen := depths[to]; !seen {
+					depths[to] = depths[current] + 1
+					queue = append(queue, to)
+				}
+			}
+		}
+	}
+}
+
+// vertex returns the vertex representing node, creating it if needed.
+func (b *graphBuilder) vertex(node *sbom.Node) *Vertex {
+	key, label := node.GetId(), nodeLabel(node)
+
+	if purlType := purlType(node); purlType != "" && slices.Contains(b.opts.CollapseTypes, purlType) {
+		key, label = "pkg:"+purlType, purlType
+	}
+
+	vertex, ok := b.vertices[key]
+	if !ok {
+		vertex = &Vertex{ID: fmt.Sprintf("n%d", len(b.graph.Vertices)), Label: label}
+		b.vertices[key] = vertex
+		b.graph.Vertices = append(b.graph.Vertices, vertex)
+	}
+
+	if b.isHighlighted(node) {
+		vertex.Highlighted = true
+	}
+
+	return vertex
+}
+
+func (b *graphBuilder) arc(from, to *Vertex, edgeType string) {
+	arc := Arc{From: from.ID, To: to.ID, Type: edgeType}
+
+	// Skip duplicate arcs and self-loops introduced by collapsing.
+	if _, ok := b.arcs[arc]; ok || from == to {
+		return
+	}
+
+	b.arcs[arc] = struct{}{}
+	b.graph.Arcs = append(b.graph.Arcs, arc)
+}
+
+// isHighlighted reports whether the node's ID, purl (with or without version) or name
+// matches one of the highlight values in opts.
+func (b *graphBuilder) isHighlighted(node *sbom.Node) bool {
+	purl := string(node.Purl())
+	purlWithoutVersion, _, _ := strings.Cut(purl, "?")
+
+	// The version follows the name, the namespace may itself contain an "@", as in scoped npm packages.
+	if idx := strings.LastIndex(purlWithoutVersion, "@"); idx > strings.LastIndex(purlWithoutVersion, "/") {
+		purlWithoutVersion = purlWithoutVersion[:idx]
+	}
+
+	return slices.ContainsFunc(b.opts.Highlight, func(value string) bool {
+		return value == node.GetId() || value == node.GetName() ||
+			(purl != "" && (value == purl || value == purlWithoutVersion))
+	})
+}
+
+func nodeLabel(node *sbom.Node) string {
+	switch {
+	case node.GetName() == "":
+		return node.GetId()
+	case node.GetVersion() == "":
+		return node.GetName()
+	default:
+		return node.GetName() + "@" + node.GetVersion()
+	}
+}
+
+func purlType(node *sbom.Node) string {
+	purlType, _, _ := strings.Cut(strings.TrimPrefix(string(node.Purl()), "pkg:"), "/")
+
+	return purlType
+}
+
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value) + `"`
+}
+
+func mermaidEscape(value string) string {
+	return strings.NewReplacer(`"`, "#quot;", "|", "#124;", "\n", " ").Replace(value)
+}
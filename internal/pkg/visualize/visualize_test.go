@@ -0,0 +1,193 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/pkg/visualize/visualize_test.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package visualize_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+	"github.com/bomctl/bomctl/internal/pkg/visualize"
+	"github.com/bomctl/bomctl/internal/testutil"
+)
+
+type visualizeSuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	document     *sbom.Document
+	documentInfo []testutil.DocumentInfo
+}
+
+func (vs *visualizeSuite) SetupSuite() {
+	var err error
+
+	vs.Backend, err = testutil.NewTestBackend()
+	vs.Require().NoError(err, "failed database backend creation")
+
+	vs.documentInfo, err = testutil.AddTestDocuments(vs.Backend)
+	vs.Require().NoError(err, "failed database backend setup")
+
+	vs.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, vs.Backend))
+
+	vs.document = sbom.NewDocument()
+	vs.document.Metadata.Id = "urn:uuid:graph"
+	vs.document.NodeList.Nodes = []*sbom.Node{
+		newNode("app", "app", "1.0.0", ""),
+		newNode("lib-a", "lib-a", "1.2.0", "pkg:golang/example.com/lib-a@1.2.0"),
+		newNode("lib-b", "lib-b", "0.3.0", "pkg:golang/example.com/lib-b@0.3.0"),
+		newNode("left-pad", "left-pad", "1.3.0", "pkg:npm/@acme/left-pad@1.3.0"),
+	}
+	vs.document.NodeList.RootElements = []string{"app"}
+	vs.document.NodeList.Edges = []*sbom.Edge{
+		{Type: sbom.Edge_dependsOn, From: "app", To: []string{"lib-a", "left-pad"}},
+		{Type: sbom.Edge_dependsOn, From: "lib-a", To: []string{"lib-b"}},
+	}
+}
+
+func (vs *visualizeSuite) TearDownSuite() {
+	vs.Backend.CloseClient()
+}
+
+func (vs *visualizeSuite) TestGraph_DOT() {
+	graph := visualize.NewGraph(vs.document, &options.VisualizeOptions{Options: vs.Options})
+
+	vs.Equal(`digraph "urn:uuid:graph" {
+  rankdir=LR;
+  node [shape=box];
+  n0 [label="app@1.0.0"];
+  n1 [label="lib-a@1.2.0"];
+  n2 [label="left-pad@1.3.0"];
+  n3 [label="lib-b@0.3.0"];
+  n0 -> n1 [label="dependsOn"];
+  n0 -> n2 [label="dependsOn"];
+  n1 -> n3 [label="dependsOn"];
+}
+`, graph.DOT())
+}
+
+func (vs *visualizeSuite) TestGraph_Mermaid() {
+	graph := visualize.NewGraph(vs.document, &options.VisualizeOptions{
+		Options:   vs.Options,
+		Highlight: []string{"pkg:npm/@acme/left-pad"},
+	})
+
+	vs.Equal(`flowchart LR
+  n0["app@1.0.0"]
+  n1["lib-a@1.2.0"]
+  n2["left-pad@1.3.0"]
+  n3["lib-b@0.3.0"]
+  n0 -->|dependsOn| n1
+  n0 -->|dependsOn| n2
+  n1 -->|dependsOn| n3
+  classDef highlight fill:#ff0,stroke:#f66,stroke-width:2px
+  class n2 highlight
+`, graph.Mermaid())
+}
+
+func (vs *visualizeSuite) TestNewGraph_Options() {
+	for _, subtest := range []struct {
+		opts        *options.VisualizeOptions
+		name        string
+		labels      []string
+		highlighted []string
+		arcs        int
+	}{
+		{
+			name:   "max depth",
+			opts:   &options.VisualizeOptions{MaxDepth: 1},
+			labels: []string{"app@1.0.0", "lib-a@1.2.0", "left-pad@1.3.0"},
+			arcs:   2,
+		},
+		{
+			name:   "collapse types",
+			opts:   &options.VisualizeOptions{CollapseTypes: []string{"golang"}},
+			labels: []string{"app@1.0.0", "golang", "left-pad@1.3.0"},
+			arcs:   2,
+		},
+		{
+			name:        "highlight",
+			opts:        &options.VisualizeOptions{Highlight: []string{"lib-b", "pkg:golang/example.com/lib-a@1.2.0"}},
+			labels:      []string{"app@1.0.0", "lib-a@1.2.0", "left-pad@1.3.0", "lib-b@0.3.0"},
+			highlighted: []string{"lib-a@1.2.0", "lib-b@0.3.0"},
+			arcs:        3,
+		},
+	} {
+		vs.Run(subtest.name, func() {
+			subtest.opts.Options = vs.Options
+			graph := visualize.NewGraph(vs.document, subtest.opts)
+
+			labels, highlighted := []string{}, []string{}
+
+			for _, vertex := range graph.Vertices {
+				labels = append(labels, vertex.Label)
+
+				if vertex.Highlighted {
+					highlighted = append(highlighted, vertex.Label)
+				}
+			}
+
+			vs.Equal(subtest.labels, labels)
+			vs.ElementsMatch(subtest.highlighted, highlighted)
+			vs.Len(graph.Arcs, subtest.arcs)
+		})
+	}
+}
+
+func (vs *visualizeSuite) TestVisualize() {
+	outputFile, err := os.Create(filepath.Join(vs.T().TempDir(), "graph.dot"))
+	vs.Require().NoError(err)
+
+	defer outputFile.Close()
+
+	opts := &options.VisualizeOptions{Options: vs.Options, OutputFile: outputFile, Format: visualize.FormatDOT}
+	vs.Require().NoError(visualize.Visualize("cdx", opts))
+
+	content, err := os.ReadFile(outputFile.Name())
+	vs.Require().NoError(err)
+	vs.Contains(string(content), `digraph "`+vs.documentInfo[0].Document.GetMetadata().GetId()+`" {`)
+
+	opts.Format = "svg"
+	vs.Require().EqualError(visualize.Visualize("cdx", opts), "unknown graph format: svg")
+
+	opts.Format = visualize.FormatMermaid
+	vs.Require().EqualError(visualize.Visualize("missing", opts), "document not found: missing")
+}
+
+func newNode(id, name, version, purl string) *sbom.Node {
+	node := &sbom.Node{Id: id, Name: name, Version: version, Type: sbom.Node_PACKAGE}
+
+	if purl != "" {
+		node.Identifiers = map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): purl}
+	}
+
+	return node
+}
+
+func TestVisualizeSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(visualizeSuite))
+}
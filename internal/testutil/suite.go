@@ -0,0 +1,99 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/testutil/suite.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package testutil
+
+import (
+	"context"
+
+	"github.com/protobom/protobom/pkg/sbom"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bomctl/bomctl/internal/pkg/db"
+	"github.com/bomctl/bomctl/internal/pkg/options"
+)
+
+// BackendSuite is a test suite backed by an in-memory database preloaded with the SBOMs from the testdata
+// directory, whose options carry the backend in their context.
+type BackendSuite struct {
+	suite.Suite
+	*options.Options
+	*db.Backend
+	DocumentInfo []DocumentInfo
+}
+
+func (bs *BackendSuite) SetupSuite() {
+	var err error
+
+	bs.Backend, err = NewTestBackend()
+	bs.Require().NoError(err, "failed database backend creation")
+
+	bs.DocumentInfo, err = AddTestDocuments(bs.Backend)
+	bs.Require().NoError(err, "failed database backend setup")
+
+	bs.Options = options.New().WithContext(context.WithValue(context.Background(), db.BackendKey{}, bs.Backend))
+}
+
+func (bs *BackendSuite) TearDownSuite() {
+	bs.Backend.CloseClient()
+}
+
+// NewTestDocument returns a document with the following dependency graph:
+//
+//	app -> a (npm) -> c (npm)
+//	       a       -> d (golang) -> e (golang)
+//	app -> b (golang) -> d.
+func NewTestDocument() *sbom.Document {
+	document := sbom.NewDocument()
+	document.Metadata.Id = "urn:uuid:test"
+
+	for _, node := range []struct{ id, purl string }{
+		{"app", "pkg:generic/app@1.0.0"},
+		{"a", "pkg:npm/a@1.0.0"},
+		{"b", "pkg:golang/example.com/b@v1.0.0"},
+		{"c", "pkg:npm/c@1.0.0"},
+		{"d", "pkg:golang/example.com/d@v1.0.0"},
+		{"e", "pkg:golang/example.com/e@v1.0.0"},
+	} {
+		document.NodeList.AddNode(&sbom.Node{
+			Id:          node.id,
+			Name:        node.id,
+			Identifiers: map[int32]string{int32(sbom.SoftwareIdentifierType_PURL): node.purl},
+		})
+	}
+
+	document.NodeList.RootElements = []string{"app"}
+	document.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_dependsOn, From: "app", To: []string{"a", "b"}})
+	document.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_dependsOn, From: "a", To: []string{"c", "d"}})
+	document.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_dependsOn, From: "b", To: []string{"d"}})
+	document.NodeList.AddEdge(&sbom.Edge{Type: sbom.Edge_dependsOn, From: "d", To: []string{"e"}})
+
+	return document
+}
+
+// NodeIDs returns the IDs of the nodes in nodeList.
+func NodeIDs(nodeList *sbom.NodeList) []string {
+	ids := []string{}
+
+	for _, node := range nodeList.GetNodes() {
+		ids = append(ids, node.GetId())
+	}
+
+	return ids
+}
@@ -0,0 +1,162 @@
+// -----------------------------------------------------------------------------
+// SPDX-FileCopyrightText: Copyright © 2024 bomctl a Series of LF Projects, LLC
+// SPDX-FileName: internal/testutil/tea.go
+// SPDX-FileType: SOURCE
+// SPDX-License-Identifier: Apache-2.0
+// -----------------------------------------------------------------------------
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+// -----------------------------------------------------------------------------
+
+package testutil
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+// TEAPath is the path of the API served by the stub TEA server.
+const TEAPath = "/tea/v1"
+
+// TEARelease is a component release published by the stub TEA server. If MissingComponent is set, the
+// release's product references a component the server has no record of.
+type TEARelease struct {
+	Purl             string
+	Version          string
+	MissingComponent bool
+}
+
+// NewTEAServer starts a stub Transparency Exchange API server publishing the given releases. Each release
+// belongs to its own product and component, and has a BOM artifact served by the stub and a VEX artifact
+// that is not. Unknown purls yield no products, and products are listed one per page. The caller must
+// close the server.
+func NewTEAServer(releases ...TEARelease) *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	writeJSON := func(w http.ResponseWriter, data any) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+
+	mux.HandleFunc("GET "+TEAPath+"/products", func(w http.ResponseWriter, r *http.Request) {
+		results := []map[string]any{}
+
+		for idx, release := range releases {
+			if r.URL.Query().Get("idType") == "PURL" && r.URL.Query().Get("idValue") == release.Purl {
+				results = append(results, map[string]any{
+					"uuid":        teaUUID("product", idx),
+					"name":        release.Purl,
+					"identifiers": []map[string]string{{"idType": "PURL", "idValue": release.Purl}},
+					"components":  []map[string]string{{"uuid": teaUUID("component", idx)}},
+				})
+			}
+		}
+
+		offset, err := strconv.Atoi(cmp.Or(r.URL.Query().Get("pageOffset"), "0"))
+		if err != nil || offset < 0 || offset > len(results) {
+			http.Error(w, "invalid pageOffset", http.StatusBadRequest)
+
+			return
+		}
+
+		writeJSON(w, map[string]any{
+			"pageStartIndex": offset,
+			"pageSize":       1,
+			"results":        results[offset:min(offset+1, len(results))],
+			"totalResults":   len(results),
+		})
+	})
+
+	mux.HandleFunc("GET "+TEAPath+"/component/{uuid}/releases", func(w http.ResponseWriter, r *http.Request) {
+		for idx, release := range releases {
+			if r.PathValue("uuid") == teaUUID("component", idx) && !release.MissingComponent {
+				writeJSON(w, map[string]any{"releases": []map[string]any{
+					{"uuid": teaUUID("release", idx), "version": release.Version},
+					{"uuid": teaUUID("previous-release", idx), "version": "0.0.0"},
+				}})
+
+				return
+			}
+		}
+
+		http.NotFound(w, r)
+	})
+
+	mux.HandleFunc("GET "+TEAPath+"/release/{uuid}/collection/latest", func(w http.ResponseWriter, r *http.Request) {
+		for idx := range releases {
+			if r.PathValue("uuid") == teaUUID("release", idx) {
+				writeJSON(w, map[string]any{
+					"uuid":    teaUUID("release", idx),
+					"version": 1,
+					"artifacts": []map[string]any{
+						teaArtifact("BOM", fmt.Sprintf("%s/artifacts/%d/bom.cdx.json", server.URL, idx)),
+						teaArtifact("VULNERABILITIES", fmt.Sprintf("%s/artifacts/%d/vex.json", server.URL, idx)),
+					},
+				})
+
+				return
+			}
+		}
+
+		http.NotFound(w, r)
+	})
+
+	mux.HandleFunc("GET /artifacts/{idx}/bom.cdx.json", func(w http.ResponseWriter, r *http.Request) {
+		idx, err := strconv.Atoi(r.PathValue("idx"))
+		if err != nil || idx >= len(releases) {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		writeJSON(w, map[string]any{
+			"bomFormat":    "CycloneDX",
+			"specVersion":  "1.5",
+			"serialNumber": uuid.NewSHA1(uuid.NameSpaceURL, []byte(releases[idx].Purl)).URN(),
+			"version":      1,
+			"metadata": map[string]any{
+				"component": map[string]any{
+					"bom-ref": releases[idx].Purl,
+					"type":    "library",
+					"name":    releases[idx].Purl,
+					"version": releases[idx].Version,
+					"purl":    releases[idx].Purl,
+				},
+			},
+		})
+	})
+
+	return server
+}
+
+func teaUUID(kind string, idx int) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(kind+strconv.Itoa(idx))).String()
+}
+
+func teaArtifact(artifactType, artifactURL string) map[string]any {
+	return map[string]any{
+		"uuid":    uuid.NewSHA1(uuid.NameSpaceURL, []byte(artifactURL)).String(),
+		"name":    artifactType,
+		"type":    artifactType,
+		"formats": []map[string]string{{"mediaType": "application/json", "url": artifactURL}},
+	}
+}